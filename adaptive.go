@@ -0,0 +1,46 @@
+package dataloaders
+
+import "time"
+
+// WithAdaptiveWait tunes the batch wait duration between min and max by the observed arrival rate
+// of keys missing the cache, replacing WithWait: the more often keys arrive the longer batches wait,
+// so bursts are fetched in bigger batches. The wait shrinks to min once keys arrive more than max
+// apart on average, so loads during low traffic aren't delayed waiting for keys that don't come.
+func WithAdaptiveWait(min, max time.Duration) Option {
+	return func(o *options) {
+		o.adaptiveWait = &adaptiveWait{min: min, max: max}
+	}
+}
+
+// adaptiveWait tracks the arrival rate of keys, guarded by the loader's mu.
+type adaptiveWait struct {
+	min, max time.Duration
+
+	// when the last key arrived, zero before the first
+	last time.Time
+	// the moving average of the time between arrivals
+	interval float64
+}
+
+// the weight of a new arrival in the moving average
+const adaptiveWeight = 0.2
+
+// arrive records a key arriving at now.
+func (a *adaptiveWait) arrive(now time.Time) {
+	if a.last.IsZero() {
+		// no interval yet, assume low traffic
+		a.interval = float64(a.max)
+	} else {
+		a.interval += adaptiveWeight * (float64(now.Sub(a.last)) - a.interval)
+	}
+	a.last = now
+}
+
+// wait returns the wait duration for a new batch,
+// linearly from max for keys arriving at once to min for keys arriving max apart.
+func (a *adaptiveWait) wait() time.Duration {
+	if a.interval >= float64(a.max) {
+		return a.min
+	}
+	return a.max - time.Duration(float64(a.max-a.min)*a.interval/float64(a.max))
+}
@@ -0,0 +1,43 @@
+package dataloaders
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestAdaptiveWait(t *testing.T) {
+	a := &adaptiveWait{min: time.Millisecond, max: 10 * time.Millisecond}
+	now := time.Unix(0, 0)
+	a.arrive(now)
+	if w := a.wait(); w != time.Millisecond {
+		t.Fatalf("got wait %v after the first key, want %v", w, time.Millisecond)
+	}
+	// burst
+	for i := 0; i < 50; i++ {
+		now = now.Add(10 * time.Microsecond)
+		a.arrive(now)
+	}
+	if w := a.wait(); w < 9*time.Millisecond {
+		t.Fatalf("got wait %v during a burst, want close to %v", w, 10*time.Millisecond)
+	}
+	// low traffic
+	for i := 0; i < 50; i++ {
+		now = now.Add(time.Second)
+		a.arrive(now)
+	}
+	if w := a.wait(); w != time.Millisecond {
+		t.Fatalf("got wait %v during low traffic, want %v", w, time.Millisecond)
+	}
+}
+
+func TestWithAdaptiveWait(t *testing.T) {
+	clock := NewFakeClock(time.Unix(0, 0))
+	l := NewTyped(echoFetcher, WithClock(clock), WithAdaptiveWait(time.Millisecond, 10*time.Millisecond))
+	thunk := l.LoadThunk(context.Background(), 1)
+	// the first key waits the minimum
+	clock.Advance(time.Millisecond)
+	if v, err := thunk(); err != nil || v != 1 {
+		t.Fatalf("got %v, %v, want 1", v, err)
+	}
+}
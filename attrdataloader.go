@@ -1,8 +1,12 @@
 package dataloaders
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"runtime/debug"
 	"sync"
+	"time"
 )
 
 func NewAttrDataLoader(initLoaders AttrDataLoaderInits, propagators ValuePropagators) *AttrDataLoader {
@@ -19,6 +23,33 @@ func NewAttrDataLoader(initLoaders AttrDataLoaderInits, propagators ValuePropaga
 	}
 }
 
+// NewAttrDataLoaderWithOptions creates an AttrDataLoader whose loaders are created on first use
+// from the fetchers of their attribute, configured by the shared defaults followed by
+// the options of the attribute, which override the defaults:
+//
+// 	NewAttrDataLoaderWithOptions(AttrFetchers{
+// 		"id":    fetchByID,
+// 		"email": fetchByEmail,
+// 	}, []Option{WithTTL(time.Minute)}, AttrOptions{
+// 		"id":    {WithMaxBatch(500), WithWait(5 * time.Millisecond)},
+// 		"email": {WithMaxBatch(10), WithWait(time.Millisecond)},
+// 	}, propagators)
+//
+// Options of attributes without fetcher are ignored.
+func NewAttrDataLoaderWithOptions(fetchers AttrFetchers, defaults []Option, attrOptions AttrOptions, propagators ValuePropagators) *AttrDataLoader {
+	inits := make(AttrDataLoaderInits, len(fetchers))
+	for attribute, fetch := range fetchers {
+		opts := make([]Option, 0, len(defaults)+len(attrOptions[attribute]))
+		opts = append(opts, defaults...)
+		opts = append(opts, attrOptions[attribute]...)
+		fetch := fetch
+		inits[attribute] = func() *DataLoader {
+			return New(fetch, opts...)
+		}
+	}
+	return NewAttrDataLoader(inits, propagators)
+}
+
 type AttrDataLoader struct {
 	// Init loader when uninitialized attribute is called.
 	initLoaders AttrDataLoaderInits
@@ -29,6 +60,28 @@ type AttrDataLoader struct {
 	// See ValuePropagator type description.
 	propagators ValuePropagators
 
+	// Run for the values of all attributes, see GlobalValuePropagator.
+	globalPropagators []GlobalValuePropagator
+
+	// Called with the errors of propagators, may be nil.
+	onPropagatorError func(value Value, attribute Attribute, err error)
+	// Logs the errors of propagators, may be nil, see SetLogger.
+	logger Logger
+
+	// Whether the attribute keys of loaded values are tracked, see SetValueID.
+	tracked bool
+	// Returns the identity of values, may be nil, see SetValueID.
+	valueID func(value Value) interface{}
+	// Lazily created attribute keys of the cached values by identity, see ClearValue.
+	valueKeys map[interface{}]map[attrKey]struct{}
+	// The canonical value by identity, nil without identity map, see SetIdentityMap.
+	identities map[interface{}]Value
+	// Lazily created identity of the value cached at every tracked attribute key.
+	keyIDs map[attrKey]interface{}
+
+	// Whether Close was called, no more loaders are initialized.
+	closed bool
+
 	// Mutex to prevent races.
 	mu sync.Mutex
 }
@@ -39,6 +92,12 @@ type AttrDataLoaderInits map[Attribute]func() *DataLoader
 // AttrDataLoaders map
 type AttrDataLoaders map[Attribute]*DataLoader
 
+// AttrFetchers map
+type AttrFetchers map[Attribute]Fetcher
+
+// AttrOptions map
+type AttrOptions map[Attribute][]Option
+
 // ValuePropagators map
 type ValuePropagators map[Attribute]ValuePropagator
 
@@ -59,39 +118,115 @@ type ValuePropagators map[Attribute]ValuePropagator
 // 			we pre-allocate the keys (e.g. email) with already loaded Values (e.g. UserAccount) containing the attribute (e.g. email).
 // 		How?:
 // 			You can propagate/prime a cache using l.Prime(attribute, key, value).
-type ValuePropagator func(loadedValue Value, l *AttrDataLoader)
+//
+// The returned error doesn't fail the load, it is passed to the handler set with OnPropagatorError.
+// Panics are recovered and reported as *PropagatorPanicError.
+type ValuePropagator func(loadedValue Value, l *AttrDataLoader) error
+
+// GlobalValuePropagator is a ValuePropagator run for the loaded values of every attribute,
+// e.g. to prime an identity map or for audit logging.
+// It is executed after the propagator of the attribute.
+type GlobalValuePropagator func(loadedValue Value, attribute Attribute, l *AttrDataLoader) error
 type Attribute interface{}
 
-func (l *AttrDataLoader) Load(attribute Attribute, key Key) (Value, error) {
+func (l *AttrDataLoader) Load(ctx context.Context, attribute Attribute, key Key, opts ...LoadOption) (Value, error) {
 	if loader := l.loader(attribute); loader != nil {
-		value, err := loader.Load(key)
+		value, err := loader.Load(ctx, key, opts...)
 		if err == nil {
+			l.track(attribute, key, value)
 			l.RunPropagator(value, attribute)
 		}
-		return value, err
+		return value, keyedAt(err, nil, attribute)
 	} else {
-		return nil, NewAttrNotRegError(fmt.Sprintf("no dataloader for attribute '%s' registered", attribute))
+		return nil, l.notRegError(attribute)
 	}
 }
 
-func (l *AttrDataLoader) LoadAll(attribute Attribute, keys []Key) ([]Value, []error) {
+func (l *AttrDataLoader) LoadAll(ctx context.Context, attribute Attribute, keys []Key, opts ...LoadOption) ([]Value, []error) {
 	if loader := l.loader(attribute); loader != nil {
-		values, errs := loader.LoadAll(keys)
-		for val := range values {
-			l.RunPropagator(val, attribute)
+		values, errs := loader.LoadAll(ctx, keys, opts...)
+		for i, value := range values {
+			if i < len(errs) && errs[i] != nil {
+				errs[i] = keyedAt(errs[i], nil, attribute)
+				continue
+			}
+			l.track(attribute, keys[i], value)
+			l.RunPropagator(value, attribute)
 		}
 		return values, errs
 	} else {
-		return nil, []error{NewAttrNotRegError(fmt.Sprintf("no dataloader for attribute '%s' registered", attribute))}
+		return nil, []error{l.notRegError(attribute)}
 	}
 }
 
-// Runs the propagator if registered for the attribute.
-func (l *AttrDataLoader) RunPropagator(value Value, attribute Attribute) {
+// Refresh the value at key at attribute from the fetcher and cache the new value.
+func (l *AttrDataLoader) Refresh(ctx context.Context, attribute Attribute, key Key) (Value, error) {
+	return l.Load(ctx, attribute, key, ForceRefresh())
+}
+
+// RefreshMany refreshes the values at keys at attribute from the fetcher and caches the new values.
+func (l *AttrDataLoader) RefreshMany(ctx context.Context, attribute Attribute, keys []Key) ([]Value, []error) {
+	return l.LoadAll(ctx, attribute, keys, ForceRefresh())
+}
+
+// Runs the propagator if registered for the attribute and the global propagators.
+// Returns the errors of all propagators joined, they are also passed to the OnPropagatorError handler.
+func (l *AttrDataLoader) RunPropagator(value Value, attribute Attribute) error {
+	l.mu.Lock()
 	propagator, exists := l.propagators[attribute]
+	globals := l.globalPropagators
+	onError := l.onPropagatorError
+	logger := l.logger
+	l.mu.Unlock()
+
+	var errs []error
 	if exists {
-		propagator(value, l)
+		errs = append(errs, safePropagate(attribute, func() error {
+			return propagator(value, l)
+		}))
+	}
+	for _, global := range globals {
+		errs = append(errs, safePropagate(attribute, func() error {
+			return global(value, attribute, l)
+		}))
+	}
+	err := errors.Join(errs...)
+	if err != nil && onError != nil {
+		onError(value, attribute, err)
 	}
+	if err != nil && logger != nil {
+		logPropagatorError(logger, err, "attribute", attribute)
+	}
+	return err
+}
+
+// OnPropagatorError sets the handler called with the errors of the propagators of a loaded value.
+func (l *AttrDataLoader) OnPropagatorError(fn func(value Value, attribute Attribute, err error)) *AttrDataLoader {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.onPropagatorError = fn
+	return l
+}
+
+// Runs the propagator, recovering a panic as *PropagatorPanicError.
+func safePropagate(attribute Attribute, propagate func() error) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = &PropagatorPanicError{Attribute: attribute, Recovered: r, Stack: debug.Stack()}
+		}
+	}()
+	return propagate()
+}
+
+// AddGlobalPropagators registers propagators run for the loaded values of every attribute.
+func (l *AttrDataLoader) AddGlobalPropagators(propagators ...GlobalValuePropagator) *AttrDataLoader {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	// copy so running propagators keep their slice
+	all := make([]GlobalValuePropagator, 0, len(l.globalPropagators)+len(propagators))
+	all = append(all, l.globalPropagators...)
+	l.globalPropagators = append(all, propagators...)
+	return l
 }
 
 // Prime the cache with the provided attribute, key and value.
@@ -107,21 +242,147 @@ func (l *AttrDataLoader) ForcePrime(attribute Attribute, key Key, value Value) {
 	l.prime(attribute, key, value, true)
 }
 
+// PrimeWithTTL primes the cache of attribute with a value expiring after ttl,
+// see DataLoader.PrimeWithTTL. Returns false if attribute not registered.
+func (l *AttrDataLoader) PrimeWithTTL(attribute Attribute, key Key, value Value, ttl time.Duration) bool {
+	if loader := l.loader(attribute); loader != nil {
+		primed := loader.PrimeWithTTL(key, value, ttl)
+		if primed {
+			l.track(attribute, key, value)
+		}
+		return primed
+	}
+	return false
+}
+
+// PrimeMany primes the cache of attribute with all provided keys and values.
+// Keys that already exist are not changed.
+// Returns the number of primed keys, 0 if attribute not registered.
+func (l *AttrDataLoader) PrimeMany(attribute Attribute, values map[Key]Value) int {
+	if loader := l.loader(attribute); loader != nil {
+		n := loader.PrimeMany(values)
+		for key, value := range values {
+			l.track(attribute, key, value)
+		}
+		return n
+	}
+	return 0
+}
+
 func (l *AttrDataLoader) prime(attribute Attribute, key Key, value Value, forcePrime bool) bool {
 	if loader := l.loader(attribute); loader != nil {
-		return loader.prime(key, value, forcePrime)
+		primed := loader.prime(key, value, forcePrime)
+		if primed {
+			l.track(attribute, key, value)
+		}
+		return primed
 	}
 	return false
 }
 
 // Clear the value at key at attribute from the cache, if it exists.
+// With an identity map the value is cleared from all attributes, see SetIdentityMap.
 func (l *AttrDataLoader) Clear(attribute Attribute, key Key) *AttrDataLoader {
+	k := attrKey{attribute: attribute, key: key}
+	if l.clearIdentity(k) {
+		return l
+	}
+	l.mu.Lock()
+	l.untrack(k)
+	l.mu.Unlock()
 	if loader := l.loader(attribute); loader != nil {
 		loader.Clear(key)
 	}
 	return l
 }
 
+// Clear the entire cache of all attributes.
+func (l *AttrDataLoader) ClearAll() *AttrDataLoader {
+	l.mu.Lock()
+	loaders := make([]*DataLoader, 0, len(l.loaders))
+	for _, loader := range l.loaders {
+		loaders = append(loaders, loader)
+	}
+	l.valueKeys, l.keyIDs = nil, nil
+	if l.identities != nil {
+		l.identities = map[interface{}]Value{}
+	}
+	l.mu.Unlock()
+	for _, loader := range loaders {
+		loader.ClearAll()
+	}
+	return l
+}
+
+// RegisterAttr registers the loader initializer and propagator of the attribute after construction.
+// An already registered attribute is replaced including its loader and cache.
+// The replaced loader is closed, waiting for its batches (see DataLoader.Close).
+// The propagator may be nil.
+func (l *AttrDataLoader) RegisterAttr(attribute Attribute, init func() *DataLoader, propagator ValuePropagator) {
+	l.mu.Lock()
+	l.initLoaders[attribute] = init
+	replaced := l.removeLoader(attribute)
+	if propagator != nil {
+		l.propagators[attribute] = propagator
+	} else {
+		delete(l.propagators, attribute)
+	}
+	l.mu.Unlock()
+	if replaced != nil {
+		replaced.Close(context.Background())
+	}
+}
+
+// DeregisterAttr removes the attribute including its loader, cache and propagator.
+// The removed loader is closed, waiting for its batches (see DataLoader.Close).
+// Loads of the attribute return an AttrNotRegError afterwards.
+// Returns false if the attribute was not registered.
+func (l *AttrDataLoader) DeregisterAttr(attribute Attribute) bool {
+	l.mu.Lock()
+	_, registered := l.initLoaders[attribute]
+	delete(l.initLoaders, attribute)
+	removed := l.removeLoader(attribute)
+	delete(l.propagators, attribute)
+	l.mu.Unlock()
+	if removed != nil {
+		removed.Close(context.Background())
+	}
+	return removed != nil || registered
+}
+
+// removeLoader removes the loader of the attribute and the keys tracked at the attribute.
+// Returns the removed loader, nil if not initialized.
+// Must be called with l.mu held.
+func (l *AttrDataLoader) removeLoader(attribute Attribute) *DataLoader {
+	loader := l.loaders[attribute]
+	delete(l.loaders, attribute)
+	for k := range l.keyIDs {
+		if k.attribute == attribute {
+			l.untrack(k)
+		}
+	}
+	return loader
+}
+
+// Attributes returns the registered attributes in no particular order.
+func (l *AttrDataLoader) Attributes() []Attribute {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	attributes := make([]Attribute, 0, len(l.initLoaders))
+	// initializers of initialized loaders are kept as nil
+	for attribute := range l.initLoaders {
+		attributes = append(attributes, attribute)
+	}
+	return attributes
+}
+
+// Loader returns the dataloader of the attribute, initializing it if needed.
+// Returns false if the attribute is not registered.
+func (l *AttrDataLoader) Loader(attribute Attribute) (*DataLoader, bool) {
+	loader := l.loader(attribute)
+	return loader, loader != nil
+}
+
 // Returns the dataloader of the attribute.
 // Initializes the dataloader if not exists and initializer is registered.
 func (l *AttrDataLoader) loader(attribute Attribute) *DataLoader {
@@ -131,7 +392,7 @@ func (l *AttrDataLoader) loader(attribute Attribute) *DataLoader {
 	if loader, exists := l.loaders[attribute]; exists {
 		return loader
 	} else { // Init if init func registered.
-		if loaderInit, exists := l.initLoaders[attribute]; exists {
+		if loaderInit, exists := l.initLoaders[attribute]; exists && !l.closed {
 			// create loader
 			loader = loaderInit()
 			// remove init func, since no longer needed
@@ -146,15 +407,53 @@ func (l *AttrDataLoader) loader(attribute Attribute) *DataLoader {
 	return nil
 }
 
+// Returns the error for loads of an attribute without loader.
+func (l *AttrDataLoader) notRegError(attribute Attribute) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.closed {
+		return ErrClosed
+	}
+	return &AttrNotRegError{
+		Attribute: attribute,
+		msg:       fmt.Sprintf("no dataloader for attribute '%s' registered", attribute),
+	}
+}
+
+// Occurs when a propagator panics while propagating a loaded value.
+type PropagatorPanicError struct {
+	// The attribute the value was loaded by.
+	Attribute Attribute
+	// The value passed to panic.
+	Recovered interface{}
+	// The stack trace of the panicking goroutine.
+	Stack []byte
+}
+
+func (e *PropagatorPanicError) Error() string {
+	return fmt.Sprintf("propagator of attribute '%v' panicked: %v", e.Attribute, e.Recovered)
+}
+
 // Occurs when an unregistered attribute is requested.
 type AttrNotRegError struct {
-	msg string
+	// The requested attribute, nil if created by NewAttrNotRegError.
+	Attribute Attribute
+	msg       string
 }
 
 func (e *AttrNotRegError) Error() string {
 	return e.msg
 }
 
+// Is reports whether target is an *AttrNotRegError of the same attribute,
+// or of any attribute if the Attribute of target is nil:
+//
+// 	errors.Is(err, &AttrNotRegError{Attribute: "email"})
+func (e *AttrNotRegError) Is(target error) bool {
+	t, ok := target.(*AttrNotRegError)
+	return ok && (t.Attribute == nil || t.Attribute == e.Attribute)
+}
+
 func NewAttrNotRegError(msg string) error {
 	return &AttrNotRegError{msg: msg}
 }
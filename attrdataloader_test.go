@@ -0,0 +1,275 @@
+package dataloaders
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestLoadAllPropagatesValues(t *testing.T) {
+	errMissing := errors.New("missing")
+	var mu sync.Mutex
+	var propagated []Value
+	l := NewAttrDataLoader(AttrDataLoaderInits{
+		"id": func() *DataLoader {
+			return New(func(ctx context.Context, keys []Key) ([]Value, []error) {
+				values := make([]Value, len(keys))
+				errs := make([]error, len(keys))
+				for i, key := range keys {
+					if key == 2 {
+						errs[i] = errMissing
+					} else {
+						values[i] = &account{ID: key.(int)}
+					}
+				}
+				return values, errs
+			})
+		},
+	}, ValuePropagators{
+		"id": func(value Value, l *AttrDataLoader) error {
+			mu.Lock()
+			defer mu.Unlock()
+			propagated = append(propagated, value)
+			return nil
+		},
+	})
+
+	values, errs := l.LoadAll(context.Background(), "id", []Key{1, 2, 3})
+	if !errors.Is(errs[1], errMissing) {
+		t.Fatalf("got %v, want %v", errs[1], errMissing)
+	}
+	want := []Value{values[0], values[2]}
+	if !reflect.DeepEqual(propagated, want) {
+		t.Fatalf("propagated %v, want %v", propagated, want)
+	}
+}
+
+func TestLoadPropagatesToOtherAttributes(t *testing.T) {
+	db := newAccountDB(&account{ID: 1, Email: "a"})
+	l := NewAttrDataLoader(AttrDataLoaderInits{
+		"id":    func() *DataLoader { return New(db.fetcher("id")) },
+		"email": func() *DataLoader { return New(db.fetcher("email")) },
+	}, ValuePropagators{
+		"id": func(value Value, l *AttrDataLoader) error {
+			l.Prime("email", value.(*account).Email, value)
+			return nil
+		},
+	})
+	ctx := context.Background()
+	byID, _ := l.Load(ctx, "id", 1)
+	byEmail, _ := l.Load(ctx, "email", "a")
+	if byID != byEmail || db.fetches("email") != 0 {
+		t.Fatalf("email fetched %d times, want the propagated value", db.fetches("email"))
+	}
+}
+
+func TestAttrClearAll(t *testing.T) {
+	db := newAccountDB(&account{ID: 1, Email: "a"})
+	l := db.loader()
+	ctx := context.Background()
+	l.Load(ctx, "id", 1)
+	l.Load(ctx, "email", "a")
+	l.ClearAll()
+	l.Load(ctx, "id", 1)
+	l.Load(ctx, "email", "a")
+	if db.fetches("id") != 2 || db.fetches("email") != 2 {
+		t.Fatalf("fetched id %d and email %d times, want 2", db.fetches("id"), db.fetches("email"))
+	}
+}
+
+func TestAttrPrimeMany(t *testing.T) {
+	db := newAccountDB()
+	l := db.loader()
+	primed := l.PrimeMany("email", map[Key]Value{"a": &account{ID: 1, Email: "a"}, "b": &account{ID: 2, Email: "b"}})
+	if primed != 2 {
+		t.Fatalf("primed %d keys, want 2", primed)
+	}
+	if _, err := l.Load(context.Background(), "email", "b"); err != nil || db.fetches("email") != 0 {
+		t.Fatalf("got %v, fetched %d keys, want the primed value", err, db.fetches("email"))
+	}
+	if l.PrimeMany("name", map[Key]Value{"a": nil}) != 0 {
+		t.Fatal("primed unregistered attribute")
+	}
+}
+
+func TestAttrRefresh(t *testing.T) {
+	db := newAccountDB(&account{ID: 1, Email: "a"})
+	l := db.loader()
+	ctx := context.Background()
+	cached, _ := l.Load(ctx, "id", 1)
+	refreshed, err := l.Refresh(ctx, "id", 1)
+	if err != nil || refreshed == cached || db.fetches("id") != 2 {
+		t.Fatalf("got %v, fetched %d keys, want a new instance", err, db.fetches("id"))
+	}
+	if v, _ := l.Load(ctx, "id", 1); v != refreshed {
+		t.Fatal("refreshed value not cached")
+	}
+}
+
+func TestRegisterAttr(t *testing.T) {
+	db := newAccountDB(&account{ID: 1, Email: "a"})
+	l := NewAttrDataLoader(AttrDataLoaderInits{}, nil)
+	ctx := context.Background()
+	l.RegisterAttr("id", func() *DataLoader { return New(db.fetcher("id")) }, func(v Value, l *AttrDataLoader) error {
+		l.Prime("email", v.(*account).Email, v)
+		return nil
+	})
+	l.RegisterAttr("email", func() *DataLoader { return New(db.fetcher("email")) }, nil)
+	if _, err := l.Load(ctx, "id", 1); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := l.Load(ctx, "email", "a"); err != nil || db.fetches("email") != 0 {
+		t.Fatalf("got %v, fetched email %d times, want the propagated value", err, db.fetches("email"))
+	}
+
+	if !l.DeregisterAttr("email") || l.DeregisterAttr("email") {
+		t.Fatal("deregistered attribute not reported once")
+	}
+	var notReg *AttrNotRegError
+	if _, err := l.Load(ctx, "email", "a"); !errors.As(err, &notReg) {
+		t.Fatalf("got %v, want an AttrNotRegError", err)
+	}
+}
+
+func TestRegisterAttrClosesReplacedLoader(t *testing.T) {
+	db := newAccountDB(&account{ID: 1, Email: "a"})
+	clock := NewFakeClock(time.Now())
+	init := func() *DataLoader {
+		return New(db.fetcher("id"), WithClock(clock), WithSynchronous(), WithTTL(time.Minute), WithJanitor(time.Second))
+	}
+	l := NewAttrDataLoader(AttrDataLoaderInits{"id": init}, nil).
+		SetValueID(func(v Value) interface{} { return v.(*account).ID })
+	ctx := context.Background()
+	replaced, _ := l.Loader("id")
+	if _, err := l.Load(ctx, "id", 1); err != nil {
+		t.Fatal(err)
+	}
+
+	l.RegisterAttr("id", init, nil)
+	if _, err := replaced.Load(ctx, 2); err != ErrClosed {
+		t.Fatalf("replaced loader: got %v, want %v", err, ErrClosed)
+	}
+	if len(l.keyIDs) != 0 || len(l.valueKeys) != 0 {
+		t.Fatalf("tracked %d keys and %d values of the replaced loader", len(l.keyIDs), len(l.valueKeys))
+	}
+	if n := clock.Timers(); n != 0 {
+		t.Fatalf("%d timers left, want the janitor of the replaced loader stopped", n)
+	}
+
+	if _, err := l.Load(ctx, "id", 1); err != nil {
+		t.Fatal(err)
+	}
+	removed, _ := l.Loader("id")
+	l.DeregisterAttr("id")
+	if _, err := removed.Load(ctx, 2); err != ErrClosed {
+		t.Fatalf("removed loader: got %v, want %v", err, ErrClosed)
+	}
+	if len(l.keyIDs) != 0 || clock.Timers() != 0 {
+		t.Fatalf("tracked %d keys and %d timers left of the removed loader", len(l.keyIDs), clock.Timers())
+	}
+}
+
+func TestAttributesAndLoader(t *testing.T) {
+	db := newAccountDB(&account{ID: 1, Email: "a"})
+	l := db.loader()
+	l.Load(context.Background(), "id", 1)
+	// initialized and uninitialized attributes are listed
+	attributes := map[Attribute]bool{}
+	for _, attribute := range l.Attributes() {
+		attributes[attribute] = true
+	}
+	if len(attributes) != 2 || !attributes["id"] || !attributes["email"] {
+		t.Fatalf("got attributes %v, want id and email", attributes)
+	}
+	loader, ok := l.Loader("id")
+	if !ok {
+		t.Fatal("no loader of a registered attribute")
+	}
+	loader.Clear(1)
+	l.Load(context.Background(), "id", 1)
+	if n := db.fetches("id"); n != 2 {
+		t.Fatalf("fetched id %d times, want the key cleared in the returned loader", n)
+	}
+	if _, ok := l.Loader("name"); ok {
+		t.Fatal("got a loader of an unregistered attribute")
+	}
+}
+
+func TestGlobalPropagators(t *testing.T) {
+	db := newAccountDB(&account{ID: 1, Email: "a"})
+	var calls []string
+	l := NewAttrDataLoader(AttrDataLoaderInits{
+		"id":    func() *DataLoader { return New(db.fetcher("id")) },
+		"email": func() *DataLoader { return New(db.fetcher("email")) },
+	}, ValuePropagators{
+		"id": func(v Value, l *AttrDataLoader) error {
+			calls = append(calls, "id")
+			return nil
+		},
+	}).AddGlobalPropagators(func(v Value, attribute Attribute, l *AttrDataLoader) error {
+		calls = append(calls, fmt.Sprint("global ", attribute))
+		return nil
+	})
+	ctx := context.Background()
+	l.Load(ctx, "id", 1)
+	l.Load(ctx, "email", "a")
+	// the global propagators run after the one of the attribute
+	if fmt.Sprint(calls) != "[id global id global email]" {
+		t.Fatalf("ran propagators %v", calls)
+	}
+}
+
+func TestPropagatorErrors(t *testing.T) {
+	db := newAccountDB(&account{ID: 1, Email: "a"})
+	errGlobal := errors.New("global")
+	var reported error
+	l := NewAttrDataLoader(AttrDataLoaderInits{
+		"id": func() *DataLoader { return New(db.fetcher("id")) },
+	}, ValuePropagators{
+		"id": func(v Value, l *AttrDataLoader) error { panic("boom") },
+	}).AddGlobalPropagators(func(v Value, attribute Attribute, l *AttrDataLoader) error {
+		return errGlobal
+	}).OnPropagatorError(func(v Value, attribute Attribute, err error) {
+		reported = err
+	})
+	// the panic neither fails the load nor skips the other propagators
+	if _, err := l.Load(context.Background(), "id", 1); err != nil {
+		t.Fatal(err)
+	}
+	var panicErr *PropagatorPanicError
+	if !errors.As(reported, &panicErr) || panicErr.Recovered != "boom" || panicErr.Attribute != "id" {
+		t.Fatalf("reported %v, want the recovered panic", reported)
+	}
+	if !errors.Is(reported, errGlobal) {
+		t.Fatalf("reported %v, want the error of the global propagator", reported)
+	}
+}
+
+func TestAttrDataLoaderWithOptions(t *testing.T) {
+	db := newAccountDB()
+	batches := map[Attribute]int{}
+	counted := func(attribute Attribute) Fetcher {
+		fetch := db.fetcher(attribute)
+		return func(ctx context.Context, keys []Key) ([]Value, []error) {
+			batches[attribute]++
+			return fetch(ctx, keys)
+		}
+	}
+	l := NewAttrDataLoaderWithOptions(AttrFetchers{
+		"id":    counted("id"),
+		"email": counted("email"),
+	}, []Option{WithSynchronous(), WithMaxBatch(2)}, AttrOptions{
+		// overrides the defaults
+		"id": {WithMaxBatch(10)},
+	}, nil)
+	ctx := context.Background()
+	l.LoadAll(ctx, "id", []Key{1, 2, 3, 4})
+	l.LoadAll(ctx, "email", []Key{"a", "b", "c", "d"})
+	if batches["id"] != 1 || batches["email"] != 2 {
+		t.Fatalf("fetched %v batches, want 1 of id and 2 of email", batches)
+	}
+}
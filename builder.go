@@ -0,0 +1,151 @@
+package dataloaders
+
+import "errors"
+
+// RegistryBuilder builds an ObjAttrDataLoader fluently instead of nesting initializer maps:
+//
+// 	l := NewRegistryBuilder().
+// 		Object("account").
+// 		Attr("id", fetchByID, WithMaxBatch(100)).
+// 		Attr("email", fetchByEmail).
+// 		PropagateFrom("id", primeEmail).
+// 		Object("payment").
+// 		Attr("id", fetchPaymentByID).
+// 		Build()
+type RegistryBuilder struct {
+	// the object types in registration order
+	objects []*ObjectBuilder
+	// the logger of all loaders, may be nil
+	logger Logger
+}
+
+// NewRegistryBuilder creates an empty RegistryBuilder.
+func NewRegistryBuilder() *RegistryBuilder {
+	return &RegistryBuilder{}
+}
+
+// Object returns the builder of the object type, adding it if not yet added.
+func (b *RegistryBuilder) Object(objectType ObjectType) *ObjectBuilder {
+	for _, o := range b.objects {
+		if o.objectType == objectType {
+			return o
+		}
+	}
+	o := &ObjectBuilder{
+		registry:    b,
+		objectType:  objectType,
+		propagators: ValuePropagators{},
+	}
+	b.objects = append(b.objects, o)
+	return o
+}
+
+// Build creates a new ObjAttrDataLoader of all added object types.
+// Every call returns new loaders with empty caches, e.g. one per request.
+func (b *RegistryBuilder) Build() *ObjAttrDataLoader {
+	inits := make(ObjAttrDataLoaderInits, len(b.objects))
+	for _, o := range b.objects {
+		inits[o.objectType] = o.newAttrDataLoader
+	}
+	l := NewObjAttrDataLoader(inits)
+	if b.logger != nil {
+		l.SetLogger(b.logger)
+	}
+	for _, o := range b.objects {
+		if len(o.objPropagators) != 0 {
+			l.AddPropagators(o.objectType, o.objPropagators...)
+		}
+	}
+	return l
+}
+
+// ObjectBuilder adds the attributes of an object type to a RegistryBuilder.
+type ObjectBuilder struct {
+	registry   *RegistryBuilder
+	objectType ObjectType
+
+	// the attributes in registration order
+	attrs       []attrBuilder
+	propagators ValuePropagators
+	// the options of all attributes, see Defaults
+	defaults []Option
+	// the propagators spanning object types, see PropagateObjects
+	objPropagators []ObjValuePropagator
+}
+
+type attrBuilder struct {
+	attribute Attribute
+	fetch     Fetcher
+	opts      []Option
+}
+
+// Attr adds the attribute loaded by fetch configured by opts.
+// An already added attribute is replaced.
+func (o *ObjectBuilder) Attr(attribute Attribute, fetch Fetcher, opts ...Option) *ObjectBuilder {
+	a := attrBuilder{attribute: attribute, fetch: fetch, opts: opts}
+	for i := range o.attrs {
+		if o.attrs[i].attribute == attribute {
+			o.attrs[i] = a
+			return o
+		}
+	}
+	o.attrs = append(o.attrs, a)
+	return o
+}
+
+// Defaults sets the options of all attributes of the object type,
+// applied before the options of every attribute which override them.
+func (o *ObjectBuilder) Defaults(opts ...Option) *ObjectBuilder {
+	o.defaults = opts
+	return o
+}
+
+// PropagateFrom adds a propagator run for the values loaded by the attribute.
+// Multiple propagators of an attribute run in the order they were added.
+func (o *ObjectBuilder) PropagateFrom(attribute Attribute, propagator ValuePropagator) *ObjectBuilder {
+	prev, exists := o.propagators[attribute]
+	if !exists {
+		o.propagators[attribute] = propagator
+		return o
+	}
+	o.propagators[attribute] = func(loadedValue Value, l *AttrDataLoader) error {
+		return errors.Join(prev(loadedValue, l), propagator(loadedValue, l))
+	}
+	return o
+}
+
+// PropagateObjects adds a propagator run for the values loaded by any attribute,
+// able to prime the caches of other object types (see ObjValuePropagator).
+func (o *ObjectBuilder) PropagateObjects(propagator ObjValuePropagator) *ObjectBuilder {
+	o.objPropagators = append(o.objPropagators, propagator)
+	return o
+}
+
+// Object returns the builder of another object type, see RegistryBuilder.Object.
+func (o *ObjectBuilder) Object(objectType ObjectType) *ObjectBuilder {
+	return o.registry.Object(objectType)
+}
+
+// Build creates a new ObjAttrDataLoader, see RegistryBuilder.Build.
+func (o *ObjectBuilder) Build() *ObjAttrDataLoader {
+	return o.registry.Build()
+}
+
+// Returns a new attribute dataloader of the object type.
+func (o *ObjectBuilder) newAttrDataLoader() *AttrDataLoader {
+	fetchers := make(AttrFetchers, len(o.attrs))
+	attrOptions := make(AttrOptions, len(o.attrs))
+	for _, a := range o.attrs {
+		fetchers[a.attribute] = a.fetch
+		attrOptions[a.attribute] = a.opts
+	}
+	propagators := make(ValuePropagators, len(o.propagators))
+	for attribute, propagator := range o.propagators {
+		propagators[attribute] = propagator
+	}
+	defaults := o.defaults
+	if logger := o.registry.logger; logger != nil {
+		defaults = append([]Option{WithLogger(logger)}, defaults...)
+	}
+	return NewAttrDataLoaderWithOptions(fetchers, defaults, attrOptions, propagators).SetLogger(o.registry.logger)
+}
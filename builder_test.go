@@ -0,0 +1,47 @@
+package dataloaders
+
+import (
+	"context"
+	"testing"
+)
+
+func TestRegistryBuilder(t *testing.T) {
+	db := newAccountDB(&account{ID: 1, Email: "a"})
+	var propagated []string
+	b := NewRegistryBuilder().
+		Object("account").
+		Defaults(WithSynchronous()).
+		Attr("id", db.fetcher("id")).
+		Attr("email", db.fetcher("email")).
+		PropagateFrom("id", func(v Value, l *AttrDataLoader) error {
+			propagated = append(propagated, "first")
+			l.Prime("email", v.(*account).Email, v)
+			return nil
+		}).
+		PropagateFrom("id", func(v Value, l *AttrDataLoader) error {
+			propagated = append(propagated, "second")
+			return nil
+		}).
+		Object("payment").
+		Attr("id", db.fetcher("payment"))
+	l := b.Build()
+	ctx := context.Background()
+	if _, err := l.Load(ctx, "account", "id", 1); err != nil {
+		t.Fatal(err)
+	}
+	if len(propagated) != 2 || propagated[0] != "first" || propagated[1] != "second" {
+		t.Fatalf("ran propagators %v, want both in order", propagated)
+	}
+	if _, err := l.Load(ctx, "account", "email", "a"); err != nil || db.fetches("email") != 0 {
+		t.Fatalf("got %v, fetched email %d times, want the propagated value", err, db.fetches("email"))
+	}
+	if _, err := l.Load(ctx, "payment", "id", 1); err != nil {
+		t.Fatal(err)
+	}
+
+	// every build has its own caches
+	b.Build().Load(ctx, "account", "id", 1)
+	if n := db.fetches("id"); n != 2 {
+		t.Fatalf("fetched id %d times, want once per build", n)
+	}
+}
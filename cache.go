@@ -0,0 +1,91 @@
+package dataloaders
+
+import (
+	"sync"
+	"time"
+)
+
+// Cache is the cache of the untyped DataLoader.
+type Cache = TypedCache[Key, Value]
+
+// TypedCache stores the loaded values of a DataLoader.
+// Implementations must be safe for concurrent use.
+type TypedCache[K comparable, V any] interface {
+	// Get returns the value cached at key and whether it exists.
+	Get(key K) (V, bool)
+	// Set caches the value at key, replacing any existing value.
+	Set(key K, value V)
+	// Delete removes the value at key, if it exists.
+	Delete(key K)
+	// Clear removes all values.
+	Clear()
+	// Len returns the number of cached values.
+	Len() int
+}
+
+// NewMapCache creates an unbounded cache backed by a map.
+// It is the default cache of a DataLoader.
+func NewMapCache[K comparable, V any]() TypedCache[K, V] {
+	return &mapCache[K, V]{}
+}
+
+type mapCache[K comparable, V any] struct {
+	// lazily created values
+	m map[K]V
+
+	mu sync.RWMutex
+}
+
+func (c *mapCache[K, V]) Get(key K) (V, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	v, ok := c.m[key]
+	return v, ok
+}
+
+func (c *mapCache[K, V]) Set(key K, value V) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.m == nil {
+		c.m = map[K]V{}
+	}
+	c.m[key] = value
+}
+
+func (c *mapCache[K, V]) Delete(key K) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.m, key)
+}
+
+func (c *mapCache[K, V]) Clear() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.m = nil
+}
+
+func (c *mapCache[K, V]) Len() int {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return len(c.m)
+}
+
+// nopCache caches nothing, see WithNoCache.
+type nopCache[K comparable, V any] struct{}
+
+func (nopCache[K, V]) Get(K) (V, bool) {
+	var zero V
+	return zero, false
+}
+
+func (nopCache[K, V]) Set(K, V) {}
+
+func (nopCache[K, V]) SetWithTTL(K, V, time.Duration) {}
+
+func (nopCache[K, V]) Delete(K) {}
+
+func (nopCache[K, V]) Clear() {}
+
+func (nopCache[K, V]) Len() int {
+	return 0
+}
@@ -0,0 +1,52 @@
+package dataloaders
+
+import (
+	"context"
+	"testing"
+)
+
+func TestMapCache(t *testing.T) {
+	c := NewMapCache[int, int]()
+	c.Set(1, 1)
+	c.Set(1, 2)
+	c.Set(2, 2)
+	if v, ok := c.Get(1); !ok || v != 2 {
+		t.Fatalf("got %d, %t, want the replaced 2", v, ok)
+	}
+	c.Delete(1)
+	if _, ok := c.Get(1); ok || c.Len() != 1 {
+		t.Fatalf("deleted value still cached, %d values", c.Len())
+	}
+	c.Clear()
+	if n := c.Len(); n != 0 {
+		t.Fatalf("cached %d values after Clear", n)
+	}
+}
+
+func TestWithCache(t *testing.T) {
+	cache := NewMapCache[int, int]()
+	cache.Set(1, 10)
+	f := newCountingFetcher(echo)
+	l := NewTyped(f.fetcher, WithCache[int, int](cache))
+	ctx := context.Background()
+	if v, _ := l.Load(ctx, 1); v != 10 {
+		t.Fatalf("got %d, want the cached 10", v)
+	}
+	l.Load(ctx, 2)
+	if v, ok := cache.Get(2); !ok || v != 2 {
+		t.Fatalf("loaded value not cached in the cache set, got %d, %t", v, ok)
+	}
+	l.Clear(1)
+	if _, ok := cache.Get(1); ok {
+		t.Fatal("cleared value still cached")
+	}
+}
+
+func TestWithCacheTypeMismatch(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Fatal("no panic for a cache of other types")
+		}
+	}()
+	NewTyped(echoFetcher, WithCache[string, int](NewMapCache[string, int]()))
+}
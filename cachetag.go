@@ -0,0 +1,125 @@
+package dataloaders
+
+// PrimeTagged primes the cache with the provided key and value like Prime
+// and tags the key, so it can be cleared together with all keys of the same tag by ClearTag,
+// e.g. all values belonging to a tenant. An already cached key is tagged but not primed.
+// Keys evicted by the cache (see WithCacheSize and WithTTL) stay tagged until cleared.
+func (l *TypedDataLoader[K, V]) PrimeTagged(key K, value V, tags ...string) bool {
+	defer l.flushL2()
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	primed := l.unsafePrime(key, value, false)
+	l.tag(l.id(key), tags)
+	return primed
+}
+
+// ClearTag clears all keys tagged with tag by PrimeTagged.
+func (l *TypedDataLoader[K, V]) ClearTag(tag string) *TypedDataLoader[K, V] {
+	l.clearTag(tag)
+	l.invalidator.publishTag(tag)
+	return l
+}
+
+func (l *TypedDataLoader[K, V]) clearTag(tag string) {
+	defer l.flushL2()
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for id := range l.tagKeys[tag] {
+		l.cache.Delete(id)
+		l.unindexValue(id)
+		l.untag(id)
+		delete(l.negatives, id)
+		// don't cache the result of a fetch in flight
+		delete(l.inflight, id)
+	}
+	l.stats.clears.Add(1)
+}
+
+// PrimeTagged primes the cache of attribute and tags the key, see DataLoader.PrimeTagged.
+// Returns false if attribute not registered.
+func (l *AttrDataLoader) PrimeTagged(attribute Attribute, key Key, value Value, tags ...string) bool {
+	if loader := l.loader(attribute); loader != nil {
+		primed := loader.PrimeTagged(key, value, tags...)
+		if primed {
+			l.track(attribute, key, value)
+		}
+		return primed
+	}
+	return false
+}
+
+// ClearTag clears the keys tagged with tag from the loaders of all attributes,
+// see DataLoader.ClearTag.
+func (l *AttrDataLoader) ClearTag(tag string) *AttrDataLoader {
+	l.mu.Lock()
+	loaders := make([]*DataLoader, 0, len(l.loaders))
+	for _, loader := range l.loaders {
+		loaders = append(loaders, loader)
+	}
+	l.mu.Unlock()
+	for _, loader := range loaders {
+		loader.ClearTag(tag)
+	}
+	return l
+}
+
+// PrimeTagged primes the cache of attribute for objectType and tags the key, see DataLoader.PrimeTagged.
+// Returns false if objectType or attribute not registered.
+func (l *ObjAttrDataLoader) PrimeTagged(objectType ObjectType, attribute Attribute, key Key, value Value, tags ...string) bool {
+	if loader := l.loader(objectType); loader != nil {
+		return loader.PrimeTagged(attribute, key, value, tags...)
+	}
+	return false
+}
+
+// ClearTag clears the keys tagged with tag from the loaders of all attributes of all object types,
+// see DataLoader.ClearTag.
+func (l *ObjAttrDataLoader) ClearTag(tag string) *ObjAttrDataLoader {
+	l.mu.Lock()
+	loaders := make([]*AttrDataLoader, 0, len(l.loaders))
+	for _, loader := range l.loaders {
+		loaders = append(loaders, loader)
+	}
+	l.mu.Unlock()
+	for _, loader := range loaders {
+		loader.ClearTag(tag)
+	}
+	return l
+}
+
+// tag adds the tags to the key at id.
+// Must be called with l.mu held.
+func (l *TypedDataLoader[K, V]) tag(id K, tags []string) {
+	if len(tags) == 0 {
+		return
+	}
+	if l.tagKeys == nil {
+		l.tagKeys = map[string]map[K]struct{}{}
+		l.keyTags = map[K][]string{}
+	}
+	for _, tag := range tags {
+		keys := l.tagKeys[tag]
+		if keys == nil {
+			keys = map[K]struct{}{}
+			l.tagKeys[tag] = keys
+		}
+		if _, tagged := keys[id]; !tagged {
+			keys[id] = struct{}{}
+			l.keyTags[id] = append(l.keyTags[id], tag)
+		}
+	}
+}
+
+// untag removes all tags of the key at id.
+// Must be called with l.mu held.
+func (l *TypedDataLoader[K, V]) untag(id K) {
+	for _, tag := range l.keyTags[id] {
+		if keys := l.tagKeys[tag]; keys != nil {
+			delete(keys, id)
+			if len(keys) == 0 {
+				delete(l.tagKeys, tag)
+			}
+		}
+	}
+	delete(l.keyTags, id)
+}
@@ -0,0 +1,42 @@
+package dataloaders
+
+import (
+	"context"
+	"testing"
+)
+
+func TestPrimeTaggedCachedKey(t *testing.T) {
+	f := newCountingFetcher(func(keys []int) ([]int, []error) { return keys, nil })
+	l := NewTyped(f.fetcher)
+	ctx := context.Background()
+	l.Load(ctx, 1)
+	if l.PrimeTagged(1, 10, "tenant") {
+		t.Fatal("primed already cached key")
+	}
+	l.ClearTag("tenant")
+	if v, _ := l.Load(ctx, 1); v != 1 {
+		t.Fatalf("got %d, want the fetched 1", v)
+	}
+	if n := f.fetches(1); n != 2 {
+		t.Fatalf("fetched key %d times, want 2", n)
+	}
+}
+
+func TestClearTag(t *testing.T) {
+	f := newCountingFetcher(func(keys []int) ([]int, []error) { return keys, nil })
+	l := NewTyped(f.fetcher, WithSynchronous())
+	ctx := context.Background()
+	l.PrimeTagged(1, 1, "a")
+	l.PrimeTagged(2, 2, "a", "b")
+	l.PrimeTagged(3, 3, "b")
+	l.ClearTag("a")
+	for _, key := range []int{1, 2, 3} {
+		l.Load(ctx, key)
+	}
+	if f.fetches(1) != 1 || f.fetches(2) != 1 || f.fetches(3) != 0 {
+		t.Fatalf("fetched keys %v, want 1 and 2 once", f.fetched)
+	}
+	if _, tagged := l.tagKeys["b"][2]; tagged {
+		t.Fatal("cleared key still tagged")
+	}
+}
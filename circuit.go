@@ -0,0 +1,80 @@
+package dataloaders
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+// ErrCircuitOpen is returned for loads failing fast while the circuit breaker is open.
+var ErrCircuitOpen = errors.New("circuit breaker open")
+
+// WithCircuitBreaker opens a circuit breaker after threshold consecutive batches
+// failed as a whole. While open, batches fail fast with ErrCircuitOpen without calling
+// the fetcher, and expired cached values are served if the cache keeps them (e.g. WithTTL).
+// After cooldown the next batch is sent as a probe, closing the circuit if it succeeds.
+func WithCircuitBreaker(threshold int, cooldown time.Duration) Option {
+	return func(o *options) {
+		o.breaker = &circuitBreaker{threshold: threshold, cooldown: cooldown}
+	}
+}
+
+type circuitBreaker struct {
+	// consecutive failures opening the circuit
+	threshold int
+	// how long the circuit stays open before probing
+	cooldown time.Duration
+	// the source of time
+	clock Clock
+
+	// consecutive failed batches
+	failures int
+	// when the circuit was last opened
+	openedAt time.Time
+	// whether a probe batch is being fetched
+	probing bool
+
+	mu sync.Mutex
+}
+
+// isOpen returns true if batches fail fast.
+func (c *circuitBreaker) isOpen() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.failures >= c.threshold
+}
+
+// allow returns true if a batch may be fetched,
+// either because the circuit is closed or as probe.
+func (c *circuitBreaker) allow() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.failures < c.threshold {
+		return true
+	}
+	if c.probing || c.clock.Now().Sub(c.openedAt) < c.cooldown {
+		return false
+	}
+	c.probing = true
+	return true
+}
+
+// record records the outcome of a fetched batch given its whole-batch error.
+// Context errors neither count as failure nor success, the ErrNotFound
+// of a single key counts as success (see batchFailed).
+func (c *circuitBreaker) record(err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.probing = false
+	if !batchFailed(err) {
+		c.failures = 0
+		return
+	}
+	if !cacheableError(err) {
+		return
+	}
+	c.failures++
+	if c.failures >= c.threshold {
+		c.openedAt = c.clock.Now()
+	}
+}
@@ -0,0 +1,80 @@
+package dataloaders
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// switchFetcher fails every batch as a whole while down.
+type switchFetcher struct {
+	down    bool
+	fetches int
+}
+
+func (f *switchFetcher) fetcher(ctx context.Context, keys []int) ([]int, []error) {
+	f.fetches++
+	if f.down {
+		return nil, []error{errors.New("down")}
+	}
+	return keys, nil
+}
+
+func TestCircuitBreaker(t *testing.T) {
+	clock := NewFakeClock(time.Now())
+	f := &switchFetcher{down: true}
+	l := NewTyped(f.fetcher, WithClock(clock), WithSynchronous(), WithCircuitBreaker(2, time.Minute))
+	ctx := context.Background()
+	l.Load(ctx, 1)
+	l.Load(ctx, 2)
+	if _, err := l.Load(ctx, 3); !errors.Is(err, ErrCircuitOpen) || f.fetches != 2 {
+		t.Fatalf("got %v after %d fetches, want %v without fetching", err, f.fetches, ErrCircuitOpen)
+	}
+
+	f.down = false
+	clock.Advance(time.Minute)
+	// the probe closes the circuit
+	if v, err := l.Load(ctx, 4); err != nil || v != 4 {
+		t.Fatalf("probe: got %d, %v", v, err)
+	}
+	if v, err := l.Load(ctx, 5); err != nil || v != 5 {
+		t.Fatalf("closed: got %d, %v", v, err)
+	}
+}
+
+func TestCircuitBreakerServesExpiredValues(t *testing.T) {
+	clock := NewFakeClock(time.Now())
+	f := &switchFetcher{}
+	l := NewTyped(f.fetcher, WithClock(clock), WithSynchronous(), WithTTL(time.Second), WithCircuitBreaker(1, time.Minute))
+	ctx := context.Background()
+	l.Load(ctx, 1)
+	f.down = true
+	l.Load(ctx, 2)
+	clock.Advance(time.Second)
+	if v, err := l.Load(ctx, 1); err != nil || v != 1 {
+		t.Fatalf("got %d, %v, want the expired value while the circuit is open", v, err)
+	}
+}
+
+func TestCircuitBreakerNotFound(t *testing.T) {
+	l := NewTyped(TypedMapFetcher[int, int](func(ctx context.Context, keys []int) (map[int]int, error) {
+		m := map[int]int{}
+		for _, key := range keys {
+			if key%2 == 0 {
+				m[key] = key
+			}
+		}
+		return m, nil
+	}).Fetcher(), WithSynchronous(), WithNotFound(NotFoundError), WithCircuitBreaker(2, time.Minute))
+	ctx := context.Background()
+	for _, key := range []int{1, 3, 5} {
+		if _, err := l.Load(ctx, key); !errors.Is(err, ErrNotFound) {
+			t.Fatalf("key %d: got %v, want %v", key, err, ErrNotFound)
+		}
+	}
+	// missing keys don't open the circuit
+	if v, err := l.Load(ctx, 2); err != nil || v != 2 {
+		t.Fatalf("got %d, %v, want 2", v, err)
+	}
+}
@@ -0,0 +1,128 @@
+package dataloaders
+
+import "reflect"
+
+// attrKey is a key of an attribute.
+type attrKey struct {
+	attribute Attribute
+	key       Key
+}
+
+// SetValueID starts tracking the attribute keys loaded and primed values are cached at,
+// identified by fn, e.g. their ID, so ClearValue can clear them from all attributes.
+// If fn is nil the value itself is the identity if it is comparable (e.g. a pointer).
+// Nothing is tracked until SetValueID or SetIdentityMap is called.
+// Keys evicted by the caches (see WithCacheSize and WithTTL) stay tracked until cleared.
+func (l *AttrDataLoader) SetValueID(fn func(value Value) interface{}) *AttrDataLoader {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.tracked = true
+	l.valueID = fn
+	l.valueKeys, l.keyIDs = nil, nil
+	if l.identities != nil {
+		l.identities = map[interface{}]Value{}
+	}
+	return l
+}
+
+// ClearValue clears the value from the caches of all attributes it was loaded or primed at,
+// e.g. after an update, so no stale copies remain at other attributes.
+// Keys the value was cached at are cleared even if they hold another value by now.
+// Does nothing until SetValueID or SetIdentityMap is called.
+func (l *AttrDataLoader) ClearValue(value Value) *AttrDataLoader {
+	l.mu.Lock()
+	id, ok := l.id(value)
+	l.mu.Unlock()
+	if ok {
+		l.clearID(id)
+	}
+	return l
+}
+
+// Clears the value of the identity from all attribute keys it is cached at.
+func (l *AttrDataLoader) clearID(id interface{}) {
+	l.mu.Lock()
+	keys := l.valueKeys[id]
+	delete(l.valueKeys, id)
+	delete(l.identities, id)
+	for k := range keys {
+		if l.keyIDs[k] == id {
+			delete(l.keyIDs, k)
+		}
+	}
+	l.mu.Unlock()
+	for k := range keys {
+		if loader := l.loader(k.attribute); loader != nil {
+			loader.Clear(k.key)
+		}
+	}
+}
+
+// ClearValue clears the value from the caches of all attributes of objectType, see AttrDataLoader.ClearValue.
+func (l *ObjAttrDataLoader) ClearValue(objectType ObjectType, value Value) *ObjAttrDataLoader {
+	if loader := l.loader(objectType); loader != nil {
+		loader.ClearValue(value)
+	}
+	return l
+}
+
+// Records that the value is cached at key at attribute, see SetValueID.
+// With an identity map a new instance replaces the value at the other attribute keys of its identity.
+func (l *AttrDataLoader) track(attribute Attribute, key Key, value Value) {
+	l.mu.Lock()
+	k := attrKey{attribute: attribute, key: key}
+	id, ok := l.id(value)
+	// the key may have moved to another value, e.g. a username reassigned to another user
+	if prev, tracked := l.keyIDs[k]; tracked && (!ok || prev != id) {
+		l.untrack(k)
+	}
+	if !ok {
+		l.mu.Unlock()
+		return
+	}
+	if l.valueKeys == nil {
+		l.valueKeys = map[interface{}]map[attrKey]struct{}{}
+	}
+	keys := l.valueKeys[id]
+	if keys == nil {
+		keys = map[attrKey]struct{}{}
+		l.valueKeys[id] = keys
+	}
+	keys[k] = struct{}{}
+	if l.keyIDs == nil {
+		l.keyIDs = map[attrKey]interface{}{}
+	}
+	l.keyIDs[k] = id
+	others := l.identify(id, k, value)
+	l.mu.Unlock()
+	l.share(others, value)
+}
+
+// Removes k from the attribute keys of the identity tracked at k.
+// Must be called with l.mu held.
+func (l *AttrDataLoader) untrack(k attrKey) {
+	id, ok := l.keyIDs[k]
+	if !ok {
+		return
+	}
+	delete(l.keyIDs, k)
+	keys := l.valueKeys[id]
+	delete(keys, k)
+	if len(keys) == 0 {
+		delete(l.valueKeys, id)
+		delete(l.identities, id)
+	}
+}
+
+// Returns the identity of the value and false if it has none or nothing is tracked.
+// Must be called with l.mu held.
+func (l *AttrDataLoader) id(value Value) (interface{}, bool) {
+	if !l.tracked || value == nil {
+		return nil, false
+	}
+	if l.valueID != nil {
+		id := l.valueID(value)
+		return id, id != nil && reflect.TypeOf(id).Comparable()
+	}
+	return value, reflect.TypeOf(value).Comparable()
+}
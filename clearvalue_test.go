@@ -0,0 +1,114 @@
+package dataloaders
+
+import (
+	"context"
+	"sync"
+	"testing"
+)
+
+type account struct {
+	ID    int
+	Email string
+}
+
+// accountDB is an in-memory store of accounts counting the fetched keys.
+type accountDB struct {
+	accounts []*account
+	fetched  map[Attribute]int
+	mu       sync.Mutex
+}
+
+func newAccountDB(accounts ...*account) *accountDB {
+	return &accountDB{accounts: accounts, fetched: map[Attribute]int{}}
+}
+
+// fetcher returns a fetcher of the accounts whose attribute matches the keys.
+// It returns copies, so every fetch is a new instance.
+func (db *accountDB) fetcher(attribute Attribute) Fetcher {
+	return func(ctx context.Context, keys []Key) ([]Value, []error) {
+		db.mu.Lock()
+		defer db.mu.Unlock()
+		db.fetched[attribute] += len(keys)
+		values := make([]Value, len(keys))
+		for i, key := range keys {
+			for _, a := range db.accounts {
+				if attribute == "id" && a.ID == key || attribute == "email" && a.Email == key {
+					c := *a
+					values[i] = &c
+				}
+			}
+		}
+		return values, nil
+	}
+}
+
+func (db *accountDB) fetches(attribute Attribute) int {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+	return db.fetched[attribute]
+}
+
+func (db *accountDB) loader() *AttrDataLoader {
+	return NewAttrDataLoader(AttrDataLoaderInits{
+		"id":    func() *DataLoader { return New(db.fetcher("id")) },
+		"email": func() *DataLoader { return New(db.fetcher("email")) },
+	}, nil)
+}
+
+func TestValuesNotTrackedWithoutValueID(t *testing.T) {
+	db := newAccountDB(&account{ID: 1, Email: "a"}, &account{ID: 2, Email: "b"})
+	l := db.loader()
+	ctx := context.Background()
+	for _, id := range []int{1, 2} {
+		if _, err := l.Load(ctx, "id", id); err != nil {
+			t.Fatal(err)
+		}
+	}
+	l.Prime("email", "c", &account{ID: 3, Email: "c"})
+	if len(l.valueKeys) != 0 || len(l.keyIDs) != 0 {
+		t.Fatalf("tracked %d values and %d keys without SetValueID", len(l.valueKeys), len(l.keyIDs))
+	}
+}
+
+func TestClearUntracksKey(t *testing.T) {
+	db := newAccountDB(&account{ID: 1, Email: "a"})
+	l := db.loader().SetValueID(func(v Value) interface{} { return v.(*account).ID })
+	ctx := context.Background()
+	if _, err := l.Load(ctx, "id", 1); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := l.Load(ctx, "email", "a"); err != nil {
+		t.Fatal(err)
+	}
+	if n := len(l.valueKeys[1]); n != 2 {
+		t.Fatalf("tracked %d keys of account 1, want 2", n)
+	}
+
+	l.Clear("id", 1)
+	if _, ok := l.valueKeys[1][attrKey{attribute: "id", key: 1}]; ok {
+		t.Fatal("cleared key still tracked")
+	}
+	l.Clear("email", "a")
+	if len(l.valueKeys) != 0 || len(l.keyIDs) != 0 {
+		t.Fatalf("tracked %d values and %d keys after clearing all keys", len(l.valueKeys), len(l.keyIDs))
+	}
+}
+
+func TestClearValue(t *testing.T) {
+	db := newAccountDB(&account{ID: 1, Email: "a"})
+	l := db.loader().SetValueID(func(v Value) interface{} { return v.(*account).ID })
+	ctx := context.Background()
+	v, _ := l.Load(ctx, "id", 1)
+	l.Prime("email", "a", v)
+
+	l.ClearValue(&account{ID: 1})
+	if _, err := l.Load(ctx, "email", "a"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := l.Load(ctx, "id", 1); err != nil {
+		t.Fatal(err)
+	}
+	if db.fetches("id") != 2 || db.fetches("email") != 1 {
+		t.Fatalf("fetched id %d and email %d times, want 2 and 1", db.fetches("id"), db.fetches("email"))
+	}
+}
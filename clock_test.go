@@ -0,0 +1,35 @@
+package dataloaders
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestFakeClock(t *testing.T) {
+	start := time.Now()
+	clock := NewFakeClock(start)
+	var fired []string
+	clock.AfterFunc(2*time.Second, func() { fired = append(fired, "2s") })
+	clock.AfterFunc(time.Second, func() {
+		fired = append(fired, "1s")
+		// timers created by fired timers fire within the same Advance if due
+		clock.AfterFunc(500*time.Millisecond, func() { fired = append(fired, "1.5s") })
+	})
+	stopped := clock.AfterFunc(time.Second, func() { fired = append(fired, "stopped") })
+	if !stopped.Stop() || stopped.Stop() {
+		t.Fatal("stopped timer not reported once")
+	}
+
+	clock.Advance(1500 * time.Millisecond)
+	if fmt.Sprint(fired) != "[1s 1.5s]" || clock.Timers() != 1 {
+		t.Fatalf("fired %v with %d timers left, want [1s 1.5s] and 1", fired, clock.Timers())
+	}
+	if now := clock.Now(); !now.Equal(start.Add(1500 * time.Millisecond)) {
+		t.Fatalf("now %v, want the advanced time", now)
+	}
+	clock.Advance(time.Second)
+	if fmt.Sprint(fired) != "[1s 1.5s 2s]" || clock.Timers() != 0 {
+		t.Fatalf("fired %v with %d timers left", fired, clock.Timers())
+	}
+}
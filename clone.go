@@ -0,0 +1,43 @@
+package dataloaders
+
+import "fmt"
+
+// WithCloner applies fn to every value returned by loads, hit or fetched,
+// so callers get their own copy and can't mutate the instance in the cache,
+// which is shared with the other callers of the batch and later loads.
+// Values returned with an error are not cloned.
+// The value type of fn must match the one of the DataLoader.
+func WithCloner[V any](fn func(value V) V) Option {
+	return func(o *options) {
+		o.cloner = fn
+	}
+}
+
+// newCloner returns the function set by WithCloner, nil if none.
+func newCloner[V any](o *options) func(V) V {
+	if o.cloner == nil {
+		return nil
+	}
+	fn, ok := o.cloner.(func(V) V)
+	if !ok {
+		panic(fmt.Sprintf("dataloaders: cloner %T does not match the DataLoader's value type", o.cloner))
+	}
+	return fn
+}
+
+// clone returns the copy of value returned to a caller, value itself without WithCloner.
+func (l *TypedDataLoader[K, V]) clone(value V) V {
+	if l.cloner == nil {
+		return value
+	}
+	return l.cloner(value)
+}
+
+// fetched returns the copy of the value and the error fetched for key at pos of batch b.
+// The batch must be done.
+func (l *TypedDataLoader[K, V]) fetched(b *batch[K, V], key K, pos int) (V, error) {
+	if err := b.error[pos]; err != nil {
+		return b.data[pos], l.keyed(key, err)
+	}
+	return l.clone(b.data[pos]), nil
+}
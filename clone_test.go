@@ -0,0 +1,41 @@
+package dataloaders
+
+import (
+	"context"
+	"testing"
+)
+
+func TestWithCloner(t *testing.T) {
+	l := NewTyped(func(ctx context.Context, keys []int) ([]*account, []error) {
+		values := make([]*account, len(keys))
+		for i, key := range keys {
+			values[i] = &account{ID: key}
+		}
+		return values, nil
+	}, WithSynchronous(), WithCloner(func(a *account) *account {
+		c := *a
+		return &c
+	}))
+	ctx := context.Background()
+	fetched, err := l.Load(ctx, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fetched.Email = "mutated"
+	hit, err := l.Load(ctx, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if hit == fetched || hit.Email != "" {
+		t.Fatalf("got %+v, want an unmutated copy", hit)
+	}
+}
+
+func TestWithClonerTypeMismatch(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Fatal("no panic for cloner of another value type")
+		}
+	}()
+	NewTyped(echoFetcher, WithCloner(func(s string) string { return s }))
+}
@@ -0,0 +1,92 @@
+package dataloaders
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+// ErrClosed is returned for loads of a closed DataLoader.
+var ErrClosed = errors.New("dataloader closed")
+
+// Close stops accepting loads, immediately dispatches the pending batch,
+// stops the janitor, unsubscribes from the invalidation bus and waits until all batches are fetched.
+// Loads after Close return ErrClosed.
+// If ctx is done first, the fetch contexts of the remaining batches
+// are canceled and ctx.Err() is returned without waiting for them.
+func (l *TypedDataLoader[K, V]) Close(ctx context.Context) error {
+	l.mu.Lock()
+	l.closed.Store(true)
+	pending := l.dispatch()
+	l.mu.Unlock()
+	for _, b := range pending {
+		b.run(l)
+	}
+	l.invalidator.stop()
+	if l.janitor != nil {
+		l.janitor.Stop()
+	}
+
+	done := make(chan struct{})
+	go func() {
+		l.wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		l.mu.Lock()
+		for b := range l.running {
+			b.cancel()
+		}
+		l.mu.Unlock()
+		return ctx.Err()
+	}
+}
+
+// Close closes the loaders of all attributes at once, see DataLoader.Close.
+// Loads of attributes not yet initialized return ErrClosed.
+func (l *AttrDataLoader) Close(ctx context.Context) error {
+	l.mu.Lock()
+	l.closed = true
+	closers := make([]func(context.Context) error, 0, len(l.loaders))
+	for _, loader := range l.loaders {
+		closers = append(closers, loader.Close)
+	}
+	l.mu.Unlock()
+	return closeAll(ctx, closers)
+}
+
+// Close closes the loaders of all object types at once, see DataLoader.Close.
+// Loads of object types not yet initialized return ErrClosed.
+func (l *ObjAttrDataLoader) Close(ctx context.Context) error {
+	l.mu.Lock()
+	l.closed = true
+	closers := make([]func(context.Context) error, 0, len(l.loaders))
+	for _, loader := range l.loaders {
+		closers = append(closers, loader.Close)
+	}
+	l.mu.Unlock()
+	return closeAll(ctx, closers)
+}
+
+// closeAll calls all closers concurrently and returns the first error.
+func closeAll(ctx context.Context, closers []func(context.Context) error) error {
+	errs := make([]error, len(closers))
+	var wg sync.WaitGroup
+	for i, closer := range closers {
+		wg.Add(1)
+		go func(i int, closer func(context.Context) error) {
+			defer wg.Done()
+			errs[i] = closer(ctx)
+		}(i, closer)
+	}
+	wg.Wait()
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
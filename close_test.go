@@ -0,0 +1,61 @@
+package dataloaders
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestCloseDrainsBatches(t *testing.T) {
+	clock := NewFakeClock(time.Now())
+	l := NewTyped(echoFetcher, WithClock(clock))
+	ctx := context.Background()
+	thunk := l.LoadThunk(ctx, 1)
+	// the pending batch is dispatched without advancing the clock
+	if err := l.Close(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if v, err := thunk(); err != nil || v != 1 {
+		t.Fatalf("got %d, %v", v, err)
+	}
+	if _, err := l.Load(ctx, 2); !errors.Is(err, ErrClosed) {
+		t.Fatalf("load after Close: got %v, want %v", err, ErrClosed)
+	}
+}
+
+func TestCloseCanceled(t *testing.T) {
+	fetching := make(chan struct{})
+	l := NewTyped(func(ctx context.Context, keys []int) ([]int, []error) {
+		close(fetching)
+		<-ctx.Done()
+		return nil, []error{ctx.Err()}
+	})
+	thunk := l.LoadThunk(context.Background(), 1)
+	<-fetching
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := l.Close(ctx); !errors.Is(err, context.Canceled) {
+		t.Fatalf("got %v, want %v", err, context.Canceled)
+	}
+	// the fetch context of the remaining batch is canceled
+	if _, err := thunk(); !errors.Is(err, context.Canceled) {
+		t.Fatalf("got %v, want %v", err, context.Canceled)
+	}
+}
+
+func TestAttrClose(t *testing.T) {
+	db := newAccountDB(&account{ID: 1, Email: "a"})
+	l := db.loader()
+	ctx := context.Background()
+	l.Load(ctx, "id", 1)
+	if err := l.Close(ctx); err != nil {
+		t.Fatal(err)
+	}
+	// initialized and uninitialized loaders are closed
+	for attribute, key := range map[Attribute]Key{"id": 2, "email": "a"} {
+		if _, err := l.Load(ctx, attribute, key); !errors.Is(err, ErrClosed) {
+			t.Fatalf("%s: got %v, want %v", attribute, err, ErrClosed)
+		}
+	}
+}
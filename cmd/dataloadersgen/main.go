@@ -0,0 +1,179 @@
+// Command dataloadersgen generates strongly-typed wrappers around the untyped DataLoader,
+// so they can still be used with attribute DataLoaders.
+//
+// Usage:
+//
+// 	//go:generate go run github.com/robinbraemer/dataloaders/cmd/dataloadersgen UserByIDLoader int *github.com/example/model.User
+//
+// generates userbyidloader_gen.go in the current directory declaring
+//
+// 	type UserByIDLoader struct{ *dataloaders.DataLoader }
+// 	func NewUserByIDLoader(fetch func(ctx context.Context, keys []int) ([]*model.User, []error), opts ...dataloaders.Option) *UserByIDLoader
+// 	func WrapUserByIDLoader(l *dataloaders.DataLoader) *UserByIDLoader
+//
+// with typed Load, LoadAll, LoadThunk, Prime, ForcePrime and Clear methods.
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"go/format"
+	"os"
+	"path"
+	"strings"
+	"text/template"
+)
+
+func main() {
+	pkg := flag.String("package", os.Getenv("GOPACKAGE"), "the package of the generated file, defaults to $GOPACKAGE")
+	out := flag.String("out", "", "the generated file, defaults to <lowercase name>_gen.go")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "usage: dataloadersgen [flags] <name> <key type> <value type>\n")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if flag.NArg() != 3 || *pkg == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+	name := flag.Arg(0)
+	if *out == "" {
+		*out = strings.ToLower(name) + "_gen.go"
+	}
+	if err := generate(*out, *pkg, name, flag.Arg(1), flag.Arg(2)); err != nil {
+		fmt.Fprintf(os.Stderr, "dataloadersgen: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// generate writes the wrapper named name for the key and value types to filename.
+func generate(filename, pkg, name, key, value string) error {
+	imports := map[string]bool{}
+	data := struct {
+		Package string
+		Name    string
+		Key     string
+		Value   string
+		Imports []string
+	}{
+		Package: pkg,
+		Name:    name,
+		Key:     parseType(key, imports),
+		Value:   parseType(value, imports),
+	}
+	for imp := range imports {
+		data.Imports = append(data.Imports, imp)
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return fmt.Errorf("executing template: %w", err)
+	}
+	src, err := format.Source(buf.Bytes())
+	if err != nil {
+		return fmt.Errorf("formatting generated code: %w", err)
+	}
+	return os.WriteFile(filename, src, 0o644)
+}
+
+// parseType returns the Go syntax of a type like *github.com/example/model.User
+// and adds the package of a qualified type to imports.
+func parseType(typ string, imports map[string]bool) string {
+	// the type modifiers, e.g. * or []
+	prefix := typ[:len(typ)-len(strings.TrimLeft(typ, "*[]"))]
+	typ = typ[len(prefix):]
+	dot := strings.LastIndex(typ, ".")
+	if dot < 0 || dot < strings.LastIndex(typ, "/") {
+		return prefix + typ
+	}
+	importPath := typ[:dot]
+	imports[importPath] = true
+	return prefix + path.Base(importPath) + "." + typ[dot+1:]
+}
+
+var tmpl = template.Must(template.New("loader").Parse(`// Code generated by github.com/robinbraemer/dataloaders/cmd/dataloadersgen, DO NOT EDIT.
+
+package {{ .Package }}
+
+import (
+	"context"
+
+	"github.com/robinbraemer/dataloaders"
+{{- range .Imports }}
+	{{ printf "%q" . }}
+{{- end }}
+)
+
+// {{ .Name }} loads {{ .Value }} values by {{ .Key }} keys
+// using an untyped DataLoader, which can also be used in attribute DataLoaders.
+type {{ .Name }} struct {
+	*dataloaders.DataLoader
+}
+
+// New{{ .Name }} creates a {{ .Name }} loading the values with fetch.
+func New{{ .Name }}(fetch func(ctx context.Context, keys []{{ .Key }}) ([]{{ .Value }}, []error), opts ...dataloaders.Option) *{{ .Name }} {
+	return Wrap{{ .Name }}(dataloaders.New(func(ctx context.Context, keys []dataloaders.Key) ([]dataloaders.Value, []error) {
+		typedKeys := make([]{{ .Key }}, len(keys))
+		for i, key := range keys {
+			typedKeys[i] = key.({{ .Key }})
+		}
+		values, errs := fetch(ctx, typedKeys)
+		untyped := make([]dataloaders.Value, len(values))
+		for i, value := range values {
+			untyped[i] = value
+		}
+		return untyped, errs
+	}, opts...))
+}
+
+// Wrap{{ .Name }} wraps an untyped DataLoader loading {{ .Value }} values by {{ .Key }} keys.
+func Wrap{{ .Name }}(l *dataloaders.DataLoader) *{{ .Name }} {
+	return &{{ .Name }}{DataLoader: l}
+}
+
+// Load loads the value at key.
+func (l *{{ .Name }}) Load(ctx context.Context, key {{ .Key }}, opts ...dataloaders.LoadOption) ({{ .Value }}, error) {
+	return l.LoadThunk(ctx, key, opts...)()
+}
+
+// LoadThunk returns a function that when called will block waiting for the value at key.
+func (l *{{ .Name }}) LoadThunk(ctx context.Context, key {{ .Key }}, opts ...dataloaders.LoadOption) func() ({{ .Value }}, error) {
+	thunk := l.DataLoader.LoadThunk(ctx, key, opts...)
+	return func() ({{ .Value }}, error) {
+		value, err := thunk()
+		typed, _ := value.({{ .Value }})
+		return typed, err
+	}
+}
+
+// LoadAll loads the values at keys.
+func (l *{{ .Name }}) LoadAll(ctx context.Context, keys []{{ .Key }}, opts ...dataloaders.LoadOption) ([]{{ .Value }}, []error) {
+	untypedKeys := make([]dataloaders.Key, len(keys))
+	for i, key := range keys {
+		untypedKeys[i] = key
+	}
+	values, errs := l.DataLoader.LoadAll(ctx, untypedKeys, opts...)
+	typed := make([]{{ .Value }}, len(values))
+	for i, value := range values {
+		typed[i], _ = value.({{ .Value }})
+	}
+	return typed, errs
+}
+
+// Prime the cache with the provided key and value, see dataloaders.DataLoader.Prime.
+func (l *{{ .Name }}) Prime(key {{ .Key }}, value {{ .Value }}) bool {
+	return l.DataLoader.Prime(key, value)
+}
+
+// ForcePrime the cache with the provided key and value, see dataloaders.DataLoader.ForcePrime.
+func (l *{{ .Name }}) ForcePrime(key {{ .Key }}, value {{ .Value }}) bool {
+	return l.DataLoader.ForcePrime(key, value)
+}
+
+// Clear the value at key from the cache, if it exists.
+func (l *{{ .Name }}) Clear(key {{ .Key }}) *{{ .Name }} {
+	l.DataLoader.Clear(key)
+	return l
+}
+`))
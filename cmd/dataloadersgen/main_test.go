@@ -0,0 +1,49 @@
+package main
+
+import (
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestParseType(t *testing.T) {
+	for typ, want := range map[string]string{
+		"int":                              "int",
+		"[]string":                         "[]string",
+		"*github.com/example/model.User":   "*model.User",
+		"[]*github.com/example/model.User": "[]*model.User",
+	} {
+		imports := map[string]bool{}
+		if got := parseType(typ, imports); got != want {
+			t.Fatalf("%s: got %s, want %s", typ, got, want)
+		}
+		if strings.Contains(typ, "/") != imports["github.com/example/model"] {
+			t.Fatalf("%s: got imports %v", typ, imports)
+		}
+	}
+}
+
+func TestGenerate(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "userbyidloader_gen.go")
+	if err := generate(filename, "loaders", "UserByIDLoader", "int", "*github.com/example/model.User"); err != nil {
+		t.Fatal(err)
+	}
+	f, err := parser.ParseFile(token.NewFileSet(), filename, nil, parser.ImportsOnly)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var imports []string
+	for _, imp := range f.Imports {
+		imports = append(imports, imp.Path.Value)
+	}
+	if f.Name.Name != "loaders" || !strings.Contains(strings.Join(imports, " "), `"github.com/example/model"`) {
+		t.Fatalf("generated package %s importing %v", f.Name.Name, imports)
+	}
+	src, _ := os.ReadFile(filename)
+	if !strings.Contains(string(src), "func (l *UserByIDLoader) Load(ctx context.Context, key int, opts ...dataloaders.LoadOption) (*model.User, error)") {
+		t.Fatalf("generated no typed Load method:\n%s", src)
+	}
+}
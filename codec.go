@@ -0,0 +1,29 @@
+package dataloaders
+
+import "encoding/json"
+
+// Codec serializes values for external cache backends, e.g. Redis.
+// Implementations must be safe for concurrent use.
+type Codec[V any] interface {
+	// Marshal encodes the value.
+	Marshal(value V) ([]byte, error)
+	// Unmarshal decodes a value encoded by Marshal.
+	Unmarshal(data []byte) (V, error)
+}
+
+// JSONCodec returns a Codec encoding values as JSON.
+func JSONCodec[V any]() Codec[V] {
+	return jsonCodec[V]{}
+}
+
+type jsonCodec[V any] struct{}
+
+func (jsonCodec[V]) Marshal(value V) ([]byte, error) {
+	return json.Marshal(value)
+}
+
+func (jsonCodec[V]) Unmarshal(data []byte) (V, error) {
+	var value V
+	err := json.Unmarshal(data, &value)
+	return value, err
+}
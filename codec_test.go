@@ -0,0 +1,18 @@
+package dataloaders
+
+import "testing"
+
+func TestJSONCodec(t *testing.T) {
+	c := JSONCodec[*account]()
+	data, err := c.Marshal(&account{ID: 1, Email: "a"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	a, err := c.Unmarshal(data)
+	if err != nil || a.ID != 1 || a.Email != "a" {
+		t.Fatalf("got %+v, %v", a, err)
+	}
+	if _, err := c.Unmarshal([]byte("{")); err == nil {
+		t.Fatal("decoded invalid JSON")
+	}
+}
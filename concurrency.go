@@ -0,0 +1,42 @@
+package dataloaders
+
+import "context"
+
+// WithMaxConcurrentBatches limits the number of batches fetched at once to n,
+// e.g. the sub batches of a LoadAll split by WithMaxBatch.
+// Further batches wait until a running fetch returned.
+func WithMaxConcurrentBatches(n int) Option {
+	return func(o *options) {
+		o.maxConcurrent = n
+	}
+}
+
+// semaphore limits concurrent fetches, a nil semaphore doesn't limit.
+type semaphore chan struct{}
+
+func newSemaphore(n int) semaphore {
+	if n <= 0 {
+		return nil
+	}
+	return make(semaphore, n)
+}
+
+// acquire blocks until a fetch may run or returns an error if ctx is done first.
+func (s semaphore) acquire(ctx context.Context) error {
+	if s == nil {
+		return nil
+	}
+	select {
+	case s <- struct{}{}:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// release frees the slot of a fetch acquired before.
+func (s semaphore) release() {
+	if s != nil {
+		<-s
+	}
+}
@@ -0,0 +1,101 @@
+package dataloaders
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+)
+
+// concurrencyFetcher records the most keys fetched at once.
+type concurrencyFetcher struct {
+	mu       sync.Mutex
+	fetching int
+	max      int
+}
+
+func (f *concurrencyFetcher) fetch(ctx context.Context, keys []int) ([]int, []error) {
+	f.mu.Lock()
+	f.fetching += len(keys)
+	if f.fetching > f.max {
+		f.max = f.fetching
+	}
+	f.mu.Unlock()
+	time.Sleep(20 * time.Millisecond)
+	f.mu.Lock()
+	f.fetching -= len(keys)
+	f.mu.Unlock()
+	return keys, nil
+}
+
+func TestWithoutBatchingLoadAllConcurrent(t *testing.T) {
+	f := &concurrencyFetcher{}
+	l := NewTyped(f.fetch, WithoutBatching(), WithMaxConcurrentBatches(2))
+	keys := []int{1, 2, 3, 4, 5}
+	values, errs := l.LoadAll(context.Background(), keys)
+	for i, key := range keys {
+		if errs[i] != nil || values[i] != key {
+			t.Fatalf("key %d: got %d, %v", key, values[i], errs[i])
+		}
+	}
+	if f.max != 2 {
+		t.Fatalf("fetched at most %d keys at once, want 2", f.max)
+	}
+}
+
+func TestWithoutBatchingLoadAllChanConcurrent(t *testing.T) {
+	f := &concurrencyFetcher{}
+	l := NewTyped(f.fetch, WithoutBatching(), WithMaxConcurrentBatches(2))
+	for r := range l.LoadAllChan(context.Background(), []int{1, 2, 3, 4, 5}) {
+		if r.Err != nil || r.Value != r.Key {
+			t.Fatalf("key %d: got %d, %v", r.Key, r.Value, r.Err)
+		}
+	}
+	if f.max != 2 {
+		t.Fatalf("fetched at most %d keys at once, want 2", f.max)
+	}
+}
+
+func TestMaxConcurrentBatches(t *testing.T) {
+	f := &concurrencyFetcher{}
+	l := NewTyped(func(ctx context.Context, keys []int) ([]int, []error) {
+		// count every batch once
+		f.fetch(ctx, keys[:1])
+		return keys, nil
+	}, WithMaxBatch(1), WithMaxConcurrentBatches(2))
+	if _, errs := l.LoadAll(context.Background(), []int{1, 2, 3, 4}); anyError(errs) {
+		t.Fatal(errs)
+	}
+	if f.max != 2 {
+		t.Fatalf("fetched at most %d batches at once, want 2", f.max)
+	}
+}
+
+func TestMaxConcurrentBatchesCanceled(t *testing.T) {
+	fetching := make(chan struct{})
+	release := make(chan struct{})
+	f := newCountingFetcher(func(keys []int) ([]int, []error) {
+		close(fetching)
+		<-release
+		return echo(keys)
+	})
+	l := NewTyped(f.fetcher, WithMaxBatch(1), WithMaxConcurrentBatches(1))
+	first := l.LoadThunk(context.Background(), 1)
+	<-fetching
+
+	// waits for the running fetch until canceled
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if _, err := l.Load(ctx, 2); err != context.DeadlineExceeded {
+		t.Fatalf("got %v, want %v", err, context.DeadlineExceeded)
+	}
+	// the batch is canceled by its caller's context watcher
+	time.Sleep(10 * time.Millisecond)
+	close(release)
+	if _, err := first(); err != nil {
+		t.Fatal(err)
+	}
+	if n := f.fetches(2); n != 0 {
+		t.Fatalf("fetched canceled key %d times, want 0", n)
+	}
+}
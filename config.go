@@ -0,0 +1,156 @@
+package dataloaders
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// Config configures the loaders of an ObjAttrDataLoader, e.g. decoded from JSON or YAML,
+// so batching and caching can be tuned without recompiling:
+//
+// 	defaults:
+// 	  wait: 2ms
+// 	objects:
+// 	  account:
+// 	    defaults:
+// 	      maxBatch: 100
+// 	    attributes:
+// 	      email:
+// 	        ttl: 5m
+// 	        cacheSize: 10000
+type Config struct {
+	// The configuration of all loaders.
+	Defaults LoaderConfig `json:"defaults" yaml:"defaults"`
+	// The configuration of the loaders of object types by name.
+	Objects map[string]ObjectConfig `json:"objects" yaml:"objects"`
+}
+
+// ObjectConfig configures the loaders of an object type.
+type ObjectConfig struct {
+	// The configuration of all attributes, overriding Config.Defaults.
+	Defaults LoaderConfig `json:"defaults" yaml:"defaults"`
+	// The configuration of the loaders of attributes by name, overriding Defaults.
+	Attributes map[string]LoaderConfig `json:"attributes" yaml:"attributes"`
+}
+
+// LoaderConfig configures a DataLoader. Zero fields inherit the outer configuration
+// or the defaults of the DataLoader.
+type LoaderConfig struct {
+	// See WithMaxBatch.
+	MaxBatch int `json:"maxBatch" yaml:"maxBatch"`
+	// See WithWait.
+	Wait Duration `json:"wait" yaml:"wait"`
+	// See WithTTL.
+	TTL Duration `json:"ttl" yaml:"ttl"`
+	// See WithCacheSize.
+	CacheSize int `json:"cacheSize" yaml:"cacheSize"`
+}
+
+// Duration is a time.Duration encoded as string like "16ms" or "5m".
+type Duration time.Duration
+
+func (d Duration) MarshalText() ([]byte, error) {
+	return []byte(time.Duration(d).String()), nil
+}
+
+func (d *Duration) UnmarshalText(text []byte) error {
+	parsed, err := time.ParseDuration(string(text))
+	if err != nil {
+		return err
+	}
+	*d = Duration(parsed)
+	return nil
+}
+
+// ParseConfig decodes the JSON configuration, rejecting unknown fields.
+func ParseConfig(data []byte) (*Config, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	c := &Config{}
+	if err := dec.Decode(c); err != nil {
+		return nil, fmt.Errorf("decoding dataloaders config: %w", err)
+	}
+	return c, nil
+}
+
+// Fetchers are the fetchers of attributes by object type and attribute name, see Config.Factory.
+type Fetchers map[string]map[string]Fetcher
+
+// Factory returns a Factory building the loaders of all fetchers configured by c.
+// The registrations are applied afterwards, e.g. to add propagators.
+// Returns an error if c configures an object type or attribute without fetcher.
+func (c *Config) Factory(fetchers Fetchers, registrations ...Registration) (Factory, error) {
+	for _, objectType := range sortedKeys(c.Objects) {
+		attrs, exists := fetchers[objectType]
+		if !exists {
+			return nil, fmt.Errorf("dataloaders config: no fetchers for object type '%s'", objectType)
+		}
+		for _, attribute := range sortedKeys(c.Objects[objectType].Attributes) {
+			if _, exists := attrs[attribute]; !exists {
+				return nil, fmt.Errorf("dataloaders config: no fetcher for attribute '%s' of object type '%s'", attribute, objectType)
+			}
+		}
+	}
+	b := NewRegistryBuilder()
+	for _, objectType := range sortedKeys(fetchers) {
+		o := b.Object(objectType)
+		object := c.Objects[objectType]
+		attrs := fetchers[objectType]
+		for _, attribute := range sortedKeys(attrs) {
+			cfg := c.Defaults.merge(object.Defaults).merge(object.Attributes[attribute])
+			o.Attr(attribute, attrs[attribute], cfg.options()...)
+		}
+	}
+	for _, register := range registrations {
+		register(b)
+	}
+	return b.Build, nil
+}
+
+// merge returns c with the non-zero fields of override.
+func (c LoaderConfig) merge(override LoaderConfig) LoaderConfig {
+	if override.MaxBatch != 0 {
+		c.MaxBatch = override.MaxBatch
+	}
+	if override.Wait != 0 {
+		c.Wait = override.Wait
+	}
+	if override.TTL != 0 {
+		c.TTL = override.TTL
+	}
+	if override.CacheSize != 0 {
+		c.CacheSize = override.CacheSize
+	}
+	return c
+}
+
+// options returns the options of the non-zero fields.
+func (c LoaderConfig) options() []Option {
+	var opts []Option
+	if c.MaxBatch != 0 {
+		opts = append(opts, WithMaxBatch(c.MaxBatch))
+	}
+	if c.Wait != 0 {
+		opts = append(opts, WithWait(time.Duration(c.Wait)))
+	}
+	if c.TTL != 0 {
+		opts = append(opts, WithTTL(time.Duration(c.TTL)))
+	}
+	if c.CacheSize != 0 {
+		opts = append(opts, WithCacheSize(c.CacheSize))
+	}
+	return opts
+}
+
+// sortedKeys returns the keys of m in ascending order.
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,59 @@
+package dataloaders
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+const testConfig = `{
+	"defaults": {"wait": "2ms", "maxBatch": 50},
+	"objects": {
+		"account": {
+			"defaults": {"maxBatch": 100},
+			"attributes": {"email": {"ttl": "5m", "cacheSize": 10}}
+		}
+	}
+}`
+
+func TestParseConfig(t *testing.T) {
+	c, err := ParseConfig([]byte(testConfig))
+	if err != nil {
+		t.Fatal(err)
+	}
+	object := c.Objects["account"]
+	// every level overrides the non-zero fields of the outer one
+	got := c.Defaults.merge(object.Defaults).merge(object.Attributes["email"])
+	want := LoaderConfig{MaxBatch: 100, Wait: Duration(2 * time.Millisecond), TTL: Duration(5 * time.Minute), CacheSize: 10}
+	if got != want {
+		t.Fatalf("merged config %+v, want %+v", got, want)
+	}
+	if _, err := ParseConfig([]byte(`{"default": {}}`)); err == nil {
+		t.Fatal("parsed config with unknown field")
+	}
+	if _, err := ParseConfig([]byte(`{"defaults": {"wait": "soon"}}`)); err == nil {
+		t.Fatal("parsed config with invalid duration")
+	}
+}
+
+func TestConfigFactory(t *testing.T) {
+	c, err := ParseConfig([]byte(testConfig))
+	if err != nil {
+		t.Fatal(err)
+	}
+	db := newAccountDB(&account{ID: 1, Email: "a"})
+	if _, err := c.Factory(Fetchers{"account": {"id": db.fetcher("id")}}); err == nil {
+		t.Fatal("got factory without the fetcher of a configured attribute")
+	}
+	if _, err := c.Factory(Fetchers{}); err == nil {
+		t.Fatal("got factory without the fetchers of a configured object type")
+	}
+	factory, err := c.Factory(Fetchers{"account": {"id": db.fetcher("id"), "email": db.fetcher("email")}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	l := factory()
+	if v, err := l.Load(context.Background(), "account", "email", "a"); err != nil || v.(*account).ID != 1 {
+		t.Fatalf("got %v, %v", v, err)
+	}
+}
@@ -0,0 +1,39 @@
+package dataloaders
+
+import "context"
+
+// Factory creates a new ObjAttrDataLoader with empty caches, e.g. one per request.
+// RegistryBuilder.Build is a Factory.
+type Factory func() *ObjAttrDataLoader
+
+// NewContext returns a copy of ctx carrying a new ObjAttrDataLoader created by the factory.
+func (f Factory) NewContext(ctx context.Context) context.Context {
+	return NewContext(ctx, f())
+}
+
+// Registration adds object types and their attributes to a RegistryBuilder,
+// e.g. provided by the modules of an application to a dependency injection container.
+type Registration func(b *RegistryBuilder)
+
+// NewFactory returns a Factory building the loaders added by the registrations.
+// The registrations are applied once, in order.
+func NewFactory(registrations ...Registration) Factory {
+	b := NewRegistryBuilder()
+	for _, register := range registrations {
+		register(b)
+	}
+	return b.Build
+}
+
+type loaderKey struct{}
+
+// NewContext returns a copy of ctx carrying the ObjAttrDataLoader, see FromContext.
+func NewContext(ctx context.Context, l *ObjAttrDataLoader) context.Context {
+	return context.WithValue(ctx, loaderKey{}, l)
+}
+
+// FromContext returns the ObjAttrDataLoader carried by ctx, see NewContext.
+func FromContext(ctx context.Context) (*ObjAttrDataLoader, bool) {
+	l, ok := ctx.Value(loaderKey{}).(*ObjAttrDataLoader)
+	return l, ok
+}
@@ -0,0 +1,28 @@
+package dataloaders
+
+import (
+	"context"
+	"testing"
+)
+
+func TestFactoryContext(t *testing.T) {
+	db := newAccountDB(&account{ID: 1, Email: "a"})
+	factory := NewFactory(func(b *RegistryBuilder) {
+		b.Object("account").Attr("id", db.fetcher("id"))
+	})
+	if _, ok := FromContext(context.Background()); ok {
+		t.Fatal("got a loader from a context without one")
+	}
+	ctx := factory.NewContext(context.Background())
+	l, ok := FromContext(ctx)
+	if !ok {
+		t.Fatal("no loader in the context")
+	}
+	l.Load(ctx, "account", "id", 1)
+	// every context gets new loaders
+	other, _ := FromContext(factory.NewContext(context.Background()))
+	other.Load(ctx, "account", "id", 1)
+	if n := db.fetches("id"); n != 2 {
+		t.Fatalf("fetched id %d times, want once per context", n)
+	}
+}
@@ -0,0 +1,113 @@
+package dataloaders
+
+import "context"
+
+// CountFetcher is the count fetch function of the untyped CountLoader.
+type CountFetcher = TypedCountFetcher[Key]
+
+// TypedCountFetcher counts the values belonging to a batch of keys,
+// e.g. from the rows of a `SELECT post_id, COUNT(*) ... WHERE post_id IN (...) GROUP BY post_id` query.
+// Keys missing in the map count 0.
+type TypedCountFetcher[K comparable] func(ctx context.Context, keys []K) (map[K]int, error)
+
+// Fetcher converts the count fetch function into a Fetcher
+// returning the counts in the order of the keys.
+// An error fails the whole batch.
+func (f TypedCountFetcher[K]) Fetcher() TypedFetcher[K, int] {
+	return func(ctx context.Context, keys []K) ([]int, []error) {
+		m, err := f(ctx, keys)
+		if err != nil {
+			return nil, []error{err}
+		}
+		counts := make([]int, len(keys))
+		for i, key := range keys {
+			counts[i] = m[key]
+		}
+		return counts, nil
+	}
+}
+
+// CountLoader is the untyped count loader.
+type CountLoader = TypedCountLoader[Key]
+
+// TypedCountLoader is a DataLoader batching and caching counts by key,
+// e.g. the total counts of GraphQL connections.
+type TypedCountLoader[K comparable] struct {
+	*TypedDataLoader[K, int]
+}
+
+// NewCountLoader creates a CountLoader configured by the given options.
+func NewCountLoader(fetch CountFetcher, opts ...Option) *CountLoader {
+	return NewTypedCountLoader(fetch, opts...)
+}
+
+// NewTypedCountLoader creates a CountLoader with typed keys configured by the given options.
+func NewTypedCountLoader[K comparable](fetch TypedCountFetcher[K], opts ...Option) *TypedCountLoader[K] {
+	return &TypedCountLoader[K]{NewTyped(fetch.Fetcher(), opts...)}
+}
+
+// Count loads the count of key.
+func (l *TypedCountLoader[K]) Count(ctx context.Context, key K) (int, error) {
+	return l.Load(ctx, key)
+}
+
+// CountAll loads the counts of keys.
+func (l *TypedCountLoader[K]) CountAll(ctx context.Context, keys []K) ([]int, []error) {
+	return l.LoadAll(ctx, keys)
+}
+
+// ExistsFetcher is the exists fetch function of the untyped ExistsLoader.
+type ExistsFetcher = TypedExistsFetcher[Key]
+
+// TypedExistsFetcher returns which keys of a batch exist,
+// e.g. from the rows of a `SELECT id ... WHERE id IN (...)` query.
+type TypedExistsFetcher[K comparable] func(ctx context.Context, keys []K) (existing []K, err error)
+
+// Fetcher converts the exists fetch function into a Fetcher
+// returning whether the keys exist in the order of the keys.
+// An error fails the whole batch.
+func (f TypedExistsFetcher[K]) Fetcher() TypedFetcher[K, bool] {
+	return func(ctx context.Context, keys []K) ([]bool, []error) {
+		existing, err := f(ctx, keys)
+		if err != nil {
+			return nil, []error{err}
+		}
+		m := make(map[K]struct{}, len(existing))
+		for _, key := range existing {
+			m[key] = struct{}{}
+		}
+		exists := make([]bool, len(keys))
+		for i, key := range keys {
+			_, exists[i] = m[key]
+		}
+		return exists, nil
+	}
+}
+
+// ExistsLoader is the untyped exists loader.
+type ExistsLoader = TypedExistsLoader[Key]
+
+// TypedExistsLoader is a DataLoader batching and caching whether keys exist.
+type TypedExistsLoader[K comparable] struct {
+	*TypedDataLoader[K, bool]
+}
+
+// NewExistsLoader creates an ExistsLoader configured by the given options.
+func NewExistsLoader(fetch ExistsFetcher, opts ...Option) *ExistsLoader {
+	return NewTypedExistsLoader(fetch, opts...)
+}
+
+// NewTypedExistsLoader creates an ExistsLoader with typed keys configured by the given options.
+func NewTypedExistsLoader[K comparable](fetch TypedExistsFetcher[K], opts ...Option) *TypedExistsLoader[K] {
+	return &TypedExistsLoader[K]{NewTyped(fetch.Fetcher(), opts...)}
+}
+
+// Exists loads whether key exists.
+func (l *TypedExistsLoader[K]) Exists(ctx context.Context, key K) (bool, error) {
+	return l.Load(ctx, key)
+}
+
+// ExistsAll loads whether the keys exist.
+func (l *TypedExistsLoader[K]) ExistsAll(ctx context.Context, keys []K) ([]bool, []error) {
+	return l.LoadAll(ctx, keys)
+}
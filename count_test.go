@@ -0,0 +1,36 @@
+package dataloaders
+
+import (
+	"context"
+	"fmt"
+	"testing"
+)
+
+func TestCountLoader(t *testing.T) {
+	l := NewTypedCountLoader(func(ctx context.Context, keys []int) (map[int]int, error) {
+		return map[int]int{1: 5}, nil
+	}, WithSynchronous())
+	ctx := context.Background()
+	// keys missing in the map count 0
+	counts, errs := l.CountAll(ctx, []int{1, 2})
+	if fmt.Sprint(counts) != "[5 0]" || anyError(errs) {
+		t.Fatalf("got %v, %v", counts, errs)
+	}
+	if n, err := l.Count(ctx, 1); err != nil || n != 5 {
+		t.Fatalf("got %d, %v", n, err)
+	}
+}
+
+func TestExistsLoader(t *testing.T) {
+	l := NewTypedExistsLoader(func(ctx context.Context, keys []int) ([]int, error) {
+		return []int{2}, nil
+	}, WithSynchronous())
+	ctx := context.Background()
+	exists, errs := l.ExistsAll(ctx, []int{1, 2})
+	if fmt.Sprint(exists) != "[false true]" || anyError(errs) {
+		t.Fatalf("got %v, %v", exists, errs)
+	}
+	if ok, err := l.Exists(ctx, 1); err != nil || ok {
+		t.Fatalf("got %t, %v", ok, err)
+	}
+}
@@ -1,38 +1,218 @@
 package dataloaders
 
 import (
+	"context"
+	"errors"
+	"expvar"
+	"fmt"
+	"reflect"
+	"runtime/debug"
 	"sync"
+	"sync/atomic"
 	"time"
 )
 
-func NewDataLoader(maxBatch int, wait time.Duration, fetch Fetcher) *DataLoader {
-	return &DataLoader{
-		maxBatch: maxBatch,
-		wait:     wait,
-		fetch:    fetch,
+// New creates a DataLoader configured by the given options.
+func New(fetch Fetcher, opts ...Option) *DataLoader {
+	return NewTyped(fetch, opts...)
+}
+
+// NewTyped creates a DataLoader with typed keys and values configured by the given options.
+func NewTyped[K comparable, V any](fetch TypedFetcher[K, V], opts ...Option) *TypedDataLoader[K, V] {
+	o := newOptions(opts)
+	cache := newCache[K, V](o)
+	l := &TypedDataLoader[K, V]{
+		maxBatch: o.maxBatch,
+		wait:     o.wait,
+		fetch:    sortFetcher(wrapFetcher(fetch, o), o),
+		cache:    cache,
+		onPanic:  o.onPanic,
+		strict:   o.strict,
+		notFound: o.notFound,
+		keyFunc:  newKeyFunc[K](o),
+		valueID:  newValueID[V](o),
+		errorTTL: o.errorTTL,
+		retries:  o.retries,
+		backoff:  o.backoff,
+		swr:      o.staleWhileRevalidate,
+		breaker:  o.breaker,
+		limiter:  o.limiter,
+		fetching: newSemaphore(o.maxConcurrent),
+	}
+	l.unbatched = o.unbatched
+	l.synchronous = o.synchronous
+	l.keyedErrors = o.keyedErrors
+	l.singleflight = o.singleflight
+	l.skipNil = o.skipNil
+	l.transform = newTransform[K, V](o)
+	l.cloner = newCloner[V](o)
+	l.hedgeDelay = o.hedgeDelay
+	l.adaptiveWait = o.adaptiveWait
+	l.maxPending, l.pendingPolicy = o.maxPending, o.pendingPolicy
+	l.clock = o.clock
+	l.debug = o.debug
+	l.staleCache, _ = cache.(TypedStaleCache[K, V])
+	l.l2 = writeBehindCache(cache)
+	if l.swr && l.staleCache == nil {
+		panic(fmt.Sprintf("dataloaders: stale-while-revalidate requires a TypedStaleCache (e.g. WithTTL), got %T", cache))
+	}
+	if hooks := newHooks[K, V](o); len(hooks) != 0 {
+		l.hooks.Store(&hooks)
 	}
+	l.invalidator = subscribe(l, o)
+	if e, ok := cache.(Expirer); ok && o.janitor > 0 {
+		l.janitor = startJanitor(e, o.janitor, o.clock, o.logger)
+	}
+	if o.expvarName != "" {
+		publishExpvar(o.expvarName, expvar.Func(func() interface{} { return l.Stats() }))
+	}
+	return l
+}
+
+// NewDataLoader creates a DataLoader with positional batch settings.
+// It is kept for compatibility, use New instead.
+func NewDataLoader(maxBatch int, wait time.Duration, fetch Fetcher, opts ...Option) *DataLoader {
+	return NewTypedDataLoader(maxBatch, wait, fetch, opts...)
+}
+
+// NewTypedDataLoader creates a DataLoader with typed keys and values and positional batch settings.
+// It is kept for compatibility, use NewTyped instead.
+func NewTypedDataLoader[K comparable, V any](maxBatch int, wait time.Duration, fetch TypedFetcher[K, V], opts ...Option) *TypedDataLoader[K, V] {
+	return NewTyped(fetch, append([]Option{WithMaxBatch(maxBatch), WithWait(wait)}, opts...)...)
 }
 
+// DataLoader is the untyped DataLoader used by the attribute
+// and object attribute DataLoaders.
+type DataLoader = TypedDataLoader[Key, Value]
+
 // Key concept by facebook's data loader https://github.com/facebook/dataloader.
 // Golang implementation inspired by https://github.com/vektah/dataloaden.
-type DataLoader struct {
+type TypedDataLoader[K comparable, V any] struct {
 	// this method provides the data for the loader
-	fetch Fetcher
+	fetch TypedFetcher[K, V]
 
 	// how long to done before sending a batch
 	wait time.Duration
+	// tunes wait by the arrival rate of keys, may be nil, see WithAdaptiveWait
+	adaptiveWait *adaptiveWait
 
 	// this will limit the maximum number of keys to send in one batch, 0 = no limit
 	maxBatch int
 
+	// fetch every key immediately in the loading goroutine, see WithoutBatching
+	unbatched bool
+	// fetch batches in the goroutine waiting for them, see WithSynchronous
+	synchronous bool
+
+	// wrap errors in *KeyedFetchError, see WithKeyedErrors
+	keyedErrors bool
+
+	// the source of time for batch waits and cached errors
+	clock Clock
+
+	// called when the fetcher panicked, may be nil
+	onPanic func(err *FetchPanicError)
+
+	// fail the whole batch if the fetcher returned an invalid number of values or errors
+	strict bool
+
+	// how keys the fetcher returned ErrNotFound for are handled
+	notFound NotFoundPolicy
+
+	// maps keys to the identity used for caching and deduplication, may be nil
+	keyFunc func(K) K
+
+	// applied to fetched values before caching them, may be nil, see WithTransform
+	transform func(K, V) V
+	// copies the values returned by loads, may be nil, see WithCloner
+	cloner func(V) V
+
+	// returns the identity of values, may be nil, see WithValueID
+	valueID func(V) interface{}
+	// lazily created keys of the cached values by value identity
+	valueKeys map[interface{}]map[K]struct{}
+	// lazily created value identities by key
+	keyValues map[K]interface{}
+
+	// lazily created keys by tag, see PrimeTagged
+	tagKeys map[string]map[K]struct{}
+	// lazily created tags by key
+	keyTags map[K][]string
+
+	// publishes and subscribes cleared keys, may be nil
+	invalidator *invalidator[K]
+	// evicts expired values in the background, may be nil
+	janitor *Janitor
+
+	// the registered hooks, replaced on registration
+	hooks atomic.Pointer[[]TypedHooks[K, V]]
+
+	// the counters returned by Stats
+	stats stats
+	// whether Dump includes the batched keys, see WithDebug
+	debug bool
+
 	// INTERNAL
 
-	// lazily created cache
-	cache map[Key]Value
+	// the loaded values
+	cache TypedCache[K, V]
+	// the cache if it writes to a second level cache after l.mu is released, otherwise nil
+	l2 *tieredCache[K, V]
+	// don't cache nil values, see WithSkipNilCache
+	skipNil bool
+
+	// the cache if it keeps expired values, otherwise nil
+	staleCache TypedStaleCache[K, V]
+	// serve expired values while refetching them
+	swr bool
+
+	// lazily created errors cached for keys, see NotFoundCache and WithErrorCache
+	negatives map[K]negative
+
+	// how long per-key fetch errors are cached, 0 = not cached
+	errorTTL time.Duration
+
+	// how often and when to retry batches the fetcher failed as a whole
+	retries int
+	backoff Backoff
+
+	// when to send a slow batch to the fetcher again, 0 = never, see WithHedging
+	hedgeDelay time.Duration
+
+	// fails batches fast after consecutive failures, may be nil
+	breaker *circuitBreaker
+
+	// limits the batches sent to the fetcher, may be nil
+	limiter RateLimiter
+
+	// limits the concurrent fetches, may be nil
+	fetching semaphore
+
+	// lazily created batch positions of the keys not yet fetched,
+	// so concurrent loads of a key share a single result
+	inflight map[K]flight[K, V]
+	// join fetches of keys across batches and clears, see WithSingleflight
+	singleflight bool
+	// lazily created batch positions of all keys in batches not yet done, including cleared keys
+	flights map[K]flight[K, V]
 
 	// the current batch. keys will continue to be collected until timeout is hit,
 	// then everything will be sent to the fetch method and out to the listeners
-	batch *batch
+	batch *batch[K, V]
+
+	// lazily created batches not yet done, see Close
+	running map[*batch[K, V]]struct{}
+	// the number of keys of the running batches
+	pendingKeys int
+	// the limit of pendingKeys, 0 = no limit, see WithMaxPending
+	maxPending    int
+	pendingPolicy PendingPolicy
+	// lazily created, closed once pendingKeys decreased
+	drained chan struct{}
+	// waits for the running batches
+	wg sync.WaitGroup
+	// whether Close was called, only set with mu held
+	closed atomic.Bool
 
 	// mutex to prevent races
 	mu sync.Mutex
@@ -41,88 +221,324 @@ type DataLoader struct {
 type Key interface{}
 type Value interface{}
 
-type Fetcher func(keys []Key) ([]Value, []error)
+// ErrNotFound is returned by fetchers for keys having no value.
+// How the DataLoader handles it is configured by WithNotFound.
+var ErrNotFound = errors.New("not found")
+
+// NotFoundPolicy controls how a DataLoader handles keys
+// the fetcher returned ErrNotFound for.
+type NotFoundPolicy int
+
+const (
+	// NotFoundNil loads missing keys as zero values without error and caches them (default).
+	NotFoundNil NotFoundPolicy = iota
+	// NotFoundError returns ErrNotFound for missing keys and fetches them again on the next load.
+	NotFoundError
+	// NotFoundCache returns ErrNotFound for missing keys and caches the negative result
+	// until the key is cleared or primed.
+	NotFoundCache
+)
+
+// Fetcher is the fetch function of the untyped DataLoader.
+type Fetcher = TypedFetcher[Key, Value]
 
-type batch struct {
+// TypedFetcher loads the values for a batch of keys.
+// The context is canceled once every caller waiting for the batch gave up
+// (e.g. their request was canceled or their deadline exceeded).
+type TypedFetcher[K comparable, V any] func(ctx context.Context, keys []K) ([]V, []error)
+
+type batch[K comparable, V any] struct {
 	// batched keys collected until batch timeout
-	keys    []Key
-	data    []Value
+	keys []K
+	// position of each key in keys by its identity
+	index   map[K]int
+	data    []V
 	error   []error
 	closing bool
 	done    chan struct{}
+	// whether the rate limiter already admitted the batch
+	admitted bool
+	// ends the batch after the wait duration
+	timer Timer
+	// ends the batch once in synchronous mode, see run
+	once sync.Once
+
+	// base of the context passed to the fetcher, detached from the callers' cancellation.
+	// Never reassigned, so it's read without l.mu, e.g. while waiting for the rate limiter
+	ctx    context.Context
+	cancel context.CancelFunc
+	// number of callers whose context is not yet done
+	waiting int
+	// stops the context watchers of the callers
+	stops []func() bool
+	// the contexts of all callers, see CallerContexts
+	callers []context.Context
+	// the metadata of the loads, see BatchMeta
+	meta []interface{}
+}
+
+// negative is an error cached for a key.
+type negative struct {
+	err error
+	// zero if the error doesn't expire
+	expires time.Time
+}
+
+// flight is the position of a key in a batch.
+type flight[K comparable, V any] struct {
+	batch *batch[K, V]
+	pos   int
 }
 
 // Load a user by key, batching and caching will be applied automatically
-func (l *DataLoader) Load(key Key) (Value, error) {
-	return l.LoadThunk(key)()
+func (l *TypedDataLoader[K, V]) Load(ctx context.Context, key K, opts ...LoadOption) (V, error) {
+	return l.LoadThunk(ctx, key, opts...)()
+}
+
+// LoadImmediate loads the key like Load, but without waiting for the batch wait duration,
+// e.g. for latency-critical lookups (see Immediate).
+func (l *TypedDataLoader[K, V]) LoadImmediate(ctx context.Context, key K, opts ...LoadOption) (V, error) {
+	return l.Load(ctx, key, append(opts[:len(opts):len(opts)], Immediate())...)
+}
+
+// LoadWithMeta loads the key like Load and attaches meta to its batch (see WithMeta).
+func (l *TypedDataLoader[K, V]) LoadWithMeta(ctx context.Context, key K, meta interface{}, opts ...LoadOption) (V, error) {
+	return l.Load(ctx, key, append(opts[:len(opts):len(opts)], WithMeta(meta))...)
 }
 
 // LoadThunk returns a function that when called will block waiting for a user.
 // This method should be used if you want one goroutine to make requests to many
 // different data loaders without blocking until the thunk is called.
-func (l *DataLoader) LoadThunk(key Key) func() (Value, error) {
+// The thunk returns ctx.Err() as soon as ctx is done.
+func (l *TypedDataLoader[K, V]) LoadThunk(ctx context.Context, key K, opts ...LoadOption) func() (V, error) {
+	o := newLoadOptions(opts)
+	ctx, release := o.context(ctx)
+	thunk := l.loadThunk(ctx, key, o)
+	return func() (V, error) {
+		defer release()
+		return thunk()
+	}
+}
+
+func (l *TypedDataLoader[K, V]) loadThunk(ctx context.Context, key K, o *loadOptions) func() (V, error) {
+	l.hookLoad(ctx, key)
+	return l.lookup(ctx, key, l.id(key), o)
+}
+
+// lookup returns the thunk of the key with identity id from the cache or a batch.
+func (l *TypedDataLoader[K, V]) lookup(ctx context.Context, key, id K, o *loadOptions) func() (V, error) {
+	if l.closed.Load() {
+		return func() (V, error) {
+			var zero V
+			return zero, ErrClosed
+		}
+	}
+	// fast path for fresh values not requiring l.mu, the cache is safe for concurrent use
+	if !o.bypassCache() {
+		if it, ok := l.fresh(id); ok {
+			l.stats.hits.Add(1)
+			l.hookCacheHit(ctx, key)
+			return func() (V, error) {
+				return l.clone(it), nil
+			}
+		}
+	}
 	l.mu.Lock()
-	if it, ok := l.cache[key]; ok {
+	if l.closed.Load() {
+		l.mu.Unlock()
+		return func() (V, error) {
+			var zero V
+			return zero, ErrClosed
+		}
+	} else if o.bypassCache() {
+		// skip the cache lookups
+	} else if err, ok := l.negative(id); ok {
+		l.mu.Unlock()
+		l.stats.hits.Add(1)
+		l.hookCacheHit(ctx, key)
+		return func() (V, error) {
+			var zero V
+			return zero, l.keyed(key, err)
+		}
+	} else if it, ok := l.cached(ctx, key, id); ok {
 		l.mu.Unlock()
-		return func() (Value, error) {
-			return it, nil
+		l.stats.hits.Add(1)
+		l.hookCacheHit(ctx, key)
+		return func() (V, error) {
+			return l.clone(it), nil
+		}
+	}
+	f, ok := l.inflight[id]
+	// bypassing loads may only join a batch not yet fetching
+	enqueued := !ok || o.bypassCache() && f.batch != l.batch
+	if enqueued {
+		if outstanding, ok := l.outstanding(id); ok {
+			f, enqueued = outstanding, false
+		}
+	}
+	if enqueued && l.maxPending > 0 && l.pendingKeys >= l.maxPending {
+		switch l.pendingPolicy {
+		case PendingReject:
+			l.mu.Unlock()
+			return func() (V, error) {
+				var zero V
+				return zero, ErrTooManyPending
+			}
+		case PendingSpill:
+			l.dispatch()
+		default:
+			drained := l.drainedChan()
+			l.mu.Unlock()
+			select {
+			case <-drained:
+				return l.lookup(ctx, key, id, o)
+			case <-ctx.Done():
+				return func() (V, error) {
+					var zero V
+					return zero, ctx.Err()
+				}
+			}
 		}
 	}
-	if l.batch == nil {
-		l.batch = &batch{done: make(chan struct{})}
+	if enqueued {
+		f = l.enqueue(ctx, key, id, !o.noCache, o.immediate)
+	} else if o.immediate && f.batch == l.batch {
+		// no waiting for more keys of the batch the key is collected in
+		l.dispatch()
+	}
+	batch, pos := f.batch, f.pos
+	batch.watch(l, ctx)
+	if o.meta != nil {
+		batch.meta = append(batch.meta, o.meta)
 	}
-	batch := l.batch
-	pos := batch.keyIndex(l, key)
 	l.mu.Unlock()
+	l.stats.misses.Add(1)
+	l.hookCacheMiss(ctx, key)
+	if enqueued && l.unbatched && !o.concurrent {
+		batch.run(l)
+	} else if enqueued && (o.immediate || l.unbatched) && !l.synchronous {
+		go batch.run(l)
+	}
 
-	return func() (Value, error) {
-		<-batch.done
-
-		var data Value
-		if pos < len(batch.data) {
-			data = batch.data[pos]
+	return func() (V, error) {
+		if l.synchronous {
+			batch.fetchNow(l)
 		}
-
-		var err error
-		// its convenient to be able to return a single error for everything
-		if len(batch.error) == 1 {
-			err = batch.error[0]
-		} else if batch.error != nil {
-			err = batch.error[pos]
+		select {
+		case <-batch.done:
+			return l.fetched(batch, key, pos)
+		default:
 		}
-
-		if err == nil {
-			l.mu.Lock()
-			l.unsafeSet(key, data)
-			l.mu.Unlock()
+		select {
+		case <-batch.done:
+			return l.fetched(batch, key, pos)
+		case <-ctx.Done():
+			var zero V
+			return zero, ctx.Err()
 		}
-
-		return data, err
 	}
 }
 
 // LoadAll fetches many keys at once. It will be broken into appropriate sized
-// sub batches depending on how the loader is configured
-func (l *DataLoader) LoadAll(keys []Key) ([]Value, []error) {
-	results := make([]func() (Value, error), len(keys))
+// sub batches depending on how the loader is configured.
+// Every full sub batch is fetched as soon as it is full, concurrently with the others,
+// so the number of parallel fetches is only limited by WithMaxConcurrentBatches.
+func (l *TypedDataLoader[K, V]) LoadAll(ctx context.Context, keys []K, opts ...LoadOption) ([]V, []error) {
+	return l.LoadAllThunk(ctx, keys, opts...)()
+}
+
+// LoadAllThunk returns a function that when called will block waiting for all keys.
+// The keys are added to the batches immediately, so many keys can be requested
+// from different data loaders before blocking on any of them.
+// Repeated keys are loaded only once.
+func (l *TypedDataLoader[K, V]) LoadAllThunk(ctx context.Context, keys []K, opts ...LoadOption) func() ([]V, []error) {
+	o := newLoadOptions(opts)
+	o.concurrent = true
+	ctx, release := o.context(ctx)
+	// repeated keys are loaded once and their result fanned out to every position
+	results := make([]func() (V, error), 0, len(keys))
+	positions := make([]int, len(keys))
+	unique := make(map[K]int, len(keys))
 
 	for i, key := range keys {
-		results[i] = l.LoadThunk(key)
+		id := l.id(key)
+		pos, ok := unique[id]
+		if !ok {
+			pos = len(results)
+			unique[id] = pos
+			results = append(results, l.loadThunk(ctx, key, o))
+		}
+		positions[i] = pos
 	}
 
-	values := make([]Value, len(keys))
-	errors := make([]error, len(keys))
-	for i, thunk := range results {
-		values[i], errors[i] = thunk()
+	return func() ([]V, []error) {
+		defer release()
+		uniqueValues := make([]V, len(results))
+		uniqueErrors := make([]error, len(results))
+		for i, thunk := range results {
+			uniqueValues[i], uniqueErrors[i] = thunk()
+		}
+		values := make([]V, len(keys))
+		errors := make([]error, len(keys))
+		for i, pos := range positions {
+			values[i], errors[i] = uniqueValues[pos], uniqueErrors[pos]
+		}
+		return values, errors
 	}
-	return values, errors
+}
+
+// Refresh loads the key from the fetcher even if it is cached and caches the new value.
+// Other loads of the key keep getting the old value until the new one is fetched.
+func (l *TypedDataLoader[K, V]) Refresh(ctx context.Context, key K) (V, error) {
+	return l.Load(ctx, key, ForceRefresh())
+}
+
+// RefreshMany is like Refresh for many keys at once.
+func (l *TypedDataLoader[K, V]) RefreshMany(ctx context.Context, keys []K) ([]V, []error) {
+	return l.LoadAll(ctx, keys, ForceRefresh())
+}
+
+// Dispatch sends the current batch to the fetcher immediately
+// instead of waiting for the batch timeout.
+// In synchronous mode all pending batches are fetched before Dispatch returns.
+func (l *TypedDataLoader[K, V]) Dispatch() {
+	l.mu.Lock()
+	pending := l.dispatch()
+	l.mu.Unlock()
+	for _, b := range pending {
+		b.run(l)
+	}
+}
+
+// dispatch sends the current batch to the fetcher.
+// In synchronous mode it returns the batches not yet fetched for the caller to run instead.
+// Must be called with l.mu held.
+func (l *TypedDataLoader[K, V]) dispatch() []*batch[K, V] {
+	if b := l.batch; b != nil && !b.closing {
+		b.closing = true
+		if b.timer != nil {
+			b.timer.Stop()
+		}
+		l.batch = nil
+		if !l.synchronous {
+			go b.end(l)
+		}
+	}
+	if !l.synchronous {
+		return nil
+	}
+	pending := make([]*batch[K, V], 0, len(l.running))
+	for b := range l.running {
+		pending = append(pending, b)
+	}
+	return pending
 }
 
 // Prime the cache with the provided key and value.
 // If the key already exists, no change is made
 // and false is returned. Returns true if forced.
 // (To forcefully prime the cache, use l.ForcePrime.)
-func (l *DataLoader) Prime(key Key, value Value) bool {
+func (l *TypedDataLoader[K, V]) Prime(key K, value V) bool {
 	return l.prime(key, value, false)
 }
 
@@ -130,70 +546,352 @@ func (l *DataLoader) Prime(key Key, value Value) bool {
 // If the key already exists, no change is made
 // and false is returned. Returns true if forced.
 // (To not forcefully prime the cache, use l.Prime.)
-func (l *DataLoader) ForcePrime(key Key, value Value) bool {
+func (l *TypedDataLoader[K, V]) ForcePrime(key K, value V) bool {
 	return l.prime(key, value, true)
 }
 
-func (l *DataLoader) prime(key Key, value Value, forcePrime bool) bool {
+// PrimeMany primes the cache with all provided keys and values
+// under a single lock acquisition. Keys that already exist are not changed.
+// Returns the number of primed keys.
+func (l *TypedDataLoader[K, V]) PrimeMany(values map[K]V) int {
+	defer l.flushL2()
 	l.mu.Lock()
 	defer l.mu.Unlock()
 
-	primeIt := forcePrime
-
-	if !primeIt {
-		var found bool
-		if _, found = l.cache[key]; !found {
-			primeIt = true
+	var n int
+	for key, value := range values {
+		if l.unsafePrime(key, value, false) {
+			n++
 		}
-	} else {
-		l.unsafeSet(key, value)
 	}
-	return primeIt
+	return n
+}
+
+// PrimeWithTTL primes the cache with the provided key and value like Prime,
+// but lets the value expire after ttl instead of the TTL set with WithTTL, 0 = never.
+// The cache must support per-key TTLs (see TypedTTLCache) as the default cache does,
+// PrimeWithTTL panics for a cache set with WithCache not supporting them.
+func (l *TypedDataLoader[K, V]) PrimeWithTTL(key K, value V, ttl time.Duration) bool {
+	cache := l.cache
+	if l.l2 != nil {
+		cache = l.l2.l1
+	}
+	if _, ok := cache.(TypedTTLCache[K, V]); !ok {
+		panic(fmt.Sprintf("dataloaders: PrimeWithTTL requires a TypedTTLCache (e.g. WithTTL), got %T", cache))
+	}
+	defer l.flushL2()
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	id := l.id(key)
+	if l.has(id) {
+		return false
+	}
+	delete(l.negatives, id)
+	setWithTTL(l.cache, id, value, ttl)
+	l.indexValue(id, value)
+	l.stats.primes.Add(1)
+	return true
+}
+
+func (l *TypedDataLoader[K, V]) prime(key K, value V, forcePrime bool) bool {
+	defer l.flushL2()
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.unsafePrime(key, value, forcePrime)
+}
+
+func (l *TypedDataLoader[K, V]) unsafePrime(key K, value V, forcePrime bool) bool {
+	id := l.id(key)
+	if !forcePrime && l.has(id) {
+		return false
+	}
+	delete(l.negatives, id)
+	l.cache.Set(id, value)
+	l.indexValue(id, value)
+	l.stats.primes.Add(1)
+	return true
 }
 
 // Clear the value at key from the cache, if it exists
-func (l *DataLoader) Clear(key Key) *DataLoader {
+func (l *TypedDataLoader[K, V]) Clear(key K) *TypedDataLoader[K, V] {
+	l.clear(key)
+	l.invalidator.publishKey(key)
+	return l
+}
+
+func (l *TypedDataLoader[K, V]) clear(key K) {
+	defer l.flushL2()
 	l.mu.Lock()
 	defer l.mu.Unlock()
-	delete(l.cache, key)
+	id := l.id(key)
+	l.cache.Delete(id)
+	l.unindexValue(id)
+	l.untag(id)
+	delete(l.negatives, id)
+	// don't cache the result of a fetch in flight
+	delete(l.inflight, id)
+	l.stats.clears.Add(1)
+}
+
+// ClearAll clears the entire cache
+func (l *TypedDataLoader[K, V]) ClearAll() *TypedDataLoader[K, V] {
+	l.clearAll()
+	l.invalidator.publishAll()
 	return l
 }
 
-func (l *DataLoader) unsafeSet(key Key, value Value) {
-	if l.cache == nil {
-		l.cache = map[Key]Value{}
+func (l *TypedDataLoader[K, V]) clearAll() {
+	defer l.flushL2()
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.cache.Clear()
+	l.negatives = nil
+	l.inflight = nil
+	l.valueKeys, l.keyValues = nil, nil
+	l.tagKeys, l.keyTags = nil, nil
+	l.stats.clears.Add(1)
+}
+
+type callersKey struct{}
+
+// CallerContexts returns the contexts of all callers waiting for the batch
+// of the fetch context passed to a Fetcher, e.g. to link their trace spans.
+// The first context is the one the fetch context was derived from.
+func CallerContexts(ctx context.Context) []context.Context {
+	callers, _ := ctx.Value(callersKey{}).([]context.Context)
+	return callers
+}
+
+type metaKey struct{}
+
+// BatchMeta returns the metadata attached with WithMeta by the loads of the batch
+// of the fetch context passed to a Fetcher, in the order the loads joined the batch.
+func BatchMeta(ctx context.Context) []interface{} {
+	meta, _ := ctx.Value(metaKey{}).([]interface{})
+	return meta
+}
+
+// negative returns the error cached at id, if not expired.
+// Must be called with l.mu held.
+func (l *TypedDataLoader[K, V]) negative(id K) (error, bool) {
+	n, ok := l.negatives[id]
+	if !ok {
+		return nil, false
+	}
+	if !n.expires.IsZero() && !l.clock.Now().Before(n.expires) {
+		delete(l.negatives, id)
+		return nil, false
+	}
+	return n.err, true
+}
+
+// setNegative caches an error at id instead of its cached value.
+// Must be called with l.mu held.
+func (l *TypedDataLoader[K, V]) setNegative(id K, n negative) {
+	l.cache.Delete(id)
+	l.unindexValue(id)
+	if l.negatives == nil {
+		l.negatives = map[K]negative{}
+	}
+	l.negatives[id] = n
+}
+
+// has returns true if a value is cached at id. The second level cache of WithL2Cache
+// is not read, as has is called with l.mu held.
+func (l *TypedDataLoader[K, V]) has(id K) bool {
+	cache := l.cache
+	if l.l2 != nil {
+		cache = l.l2.l1
+	}
+	_, found := cache.Get(id)
+	return found
+}
+
+// flushL2 writes the values cached with l.mu held to the second level cache of WithL2Cache.
+// Must be called without l.mu held.
+func (l *TypedDataLoader[K, V]) flushL2() {
+	if l.l2 != nil {
+		l.l2.flush()
 	}
-	l.cache[key] = value
 }
 
-// keyIndex will return the location of the key in the batch, if its not found
-// it will add the key to the batch
-func (b *batch) keyIndex(l *DataLoader, key Key) int {
-	for i, existingKey := range b.keys {
-		if key == existingKey {
-			return i
+// cacheableError returns false for errors caused by the callers
+// rather than the fetched data source.
+func cacheableError(err error) bool {
+	return !errors.Is(err, context.Canceled) && !errors.Is(err, context.DeadlineExceeded)
+}
+
+// fresh returns the cached value at id if it is not expired.
+// A key never has both a cached value and a cached error, so l.mu is not required.
+func (l *TypedDataLoader[K, V]) fresh(id K) (V, bool) {
+	if l.staleCache == nil {
+		return l.cache.Get(id)
+	}
+	v, stale, ok := l.staleCache.GetStale(id)
+	return v, ok && !stale
+}
+
+// cached returns the cached value at id. Expired values are returned while being
+// refetched in stale-while-revalidate mode or while the circuit breaker is open.
+// Without a stale cache the value was already looked up by fresh, so the cache,
+// which may be remote (see WithL2Cache), is not read again with l.mu held.
+// Must be called with l.mu held.
+func (l *TypedDataLoader[K, V]) cached(ctx context.Context, key, id K) (V, bool) {
+	if l.staleCache == nil {
+		var zero V
+		return zero, false
+	}
+	cache := l.staleCache
+	if l.l2 != nil {
+		// the second level cache was read by fresh already
+		cache = l.l2.l1.(TypedStaleCache[K, V])
+	}
+	v, stale, ok := cache.GetStale(id)
+	switch {
+	case !ok || !stale:
+		return v, ok
+	case l.swr:
+		l.revalidate(ctx, key, id)
+		return v, true
+	case l.breaker != nil && l.breaker.isOpen():
+		return v, true
+	}
+	var zero V
+	return zero, false
+}
+
+// enqueue adds the key to the current batch. If cache is true the result
+// will be cached and concurrent loads of the key join the batch.
+// If alone is true or batching is disabled, the key is added to a new batch the caller must run.
+// Must be called with l.mu held.
+func (l *TypedDataLoader[K, V]) enqueue(ctx context.Context, key, id K, cache, alone bool) flight[K, V] {
+	var f flight[K, V]
+	if l.unbatched || alone {
+		// a closed batch of just this key the caller must end
+		b := l.start(ctx)
+		b.closing = true
+		b.keys = append(b.keys, key)
+		b.index[id] = 0
+		l.pendingKeys++
+		f = flight[K, V]{batch: b}
+	} else {
+		if l.batch == nil {
+			l.batch = l.start(ctx)
 		}
+		// keyIndex resets l.batch once the batch is full
+		b := l.batch
+		f = flight[K, V]{batch: b, pos: b.keyIndex(l, key, id)}
+	}
+	l.takeOff(id, f)
+	if cache {
+		if l.inflight == nil {
+			l.inflight = map[K]flight[K, V]{}
+		}
+		l.inflight[id] = f
+	}
+	return f
+}
+
+// start creates a running batch.
+// Must be called with l.mu held.
+func (l *TypedDataLoader[K, V]) start(ctx context.Context) *batch[K, V] {
+	b := newBatch[K, V](ctx)
+	if l.running == nil {
+		l.running = map[*batch[K, V]]struct{}{}
+	}
+	l.running[b] = struct{}{}
+	l.wg.Add(1)
+	return b
+}
+
+// revalidate fetches the stale or prefetched key in the background unless it is already fetched.
+// Must be called with l.mu held.
+func (l *TypedDataLoader[K, V]) revalidate(ctx context.Context, key, id K) {
+	_, ok := l.inflight[id]
+	if _, outstanding := l.outstanding(id); !ok && !outstanding {
+		// in synchronous mode the batch is fetched by Dispatch, Close or the next waiting load
+		if f := l.enqueue(ctx, key, id, true, false); l.unbatched && !l.synchronous {
+			go f.batch.run(l)
+		}
+	}
+}
+
+// id returns the identity of key used for caching and deduplication.
+func (l *TypedDataLoader[K, V]) id(key K) K {
+	if l.keyFunc != nil {
+		return l.keyFunc(key)
+	}
+	return key
+}
+
+// newBatch creates a batch whose fetch context keeps the values of ctx
+// but is only canceled once all callers are done.
+func newBatch[K comparable, V any](ctx context.Context) *batch[K, V] {
+	b := &batch[K, V]{index: map[K]int{}, done: make(chan struct{})}
+	b.ctx, b.cancel = context.WithCancel(context.WithoutCancel(ctx))
+	return b
+}
+
+// watch registers a caller waiting for the batch. The fetch context
+// is canceled when the contexts of all waiting callers are done.
+// Must be called with l.mu held.
+func (b *batch[K, V]) watch(l *TypedDataLoader[K, V], ctx context.Context) {
+	b.waiting++
+	b.callers = append(b.callers, ctx)
+	b.stops = append(b.stops, context.AfterFunc(ctx, func() {
+		l.mu.Lock()
+		defer l.mu.Unlock()
+		b.waiting--
+		if b.waiting == 0 {
+			b.cancel()
+		}
+	}))
+}
+
+// keyIndex will return the location of the key with identity id in the batch,
+// if its not found it will add the key to the batch
+func (b *batch[K, V]) keyIndex(l *TypedDataLoader[K, V], key, id K) int {
+	if i, ok := b.index[id]; ok {
+		return i
 	}
 
 	pos := len(b.keys)
 	b.keys = append(b.keys, key)
-	if pos == 0 {
-		go b.startTimer(l)
+	b.index[id] = pos
+	l.pendingKeys++
+	wait := l.wait
+	if l.adaptiveWait != nil {
+		l.adaptiveWait.arrive(l.clock.Now())
+		wait = l.adaptiveWait.wait()
+	}
+	if pos == 0 && !l.synchronous {
+		b.timer = l.clock.AfterFunc(wait, func() { b.timeout(l) })
 	}
 
 	if l.maxBatch != 0 && pos >= l.maxBatch-1 {
 		if !b.closing {
 			b.closing = true
+			if b.timer != nil {
+				b.timer.Stop()
+			}
 			l.batch = nil
-			go b.end(l)
+			if !l.synchronous {
+				go b.end(l)
+			}
 		}
 	}
 
 	return pos
 }
 
-func (b *batch) startTimer(l *DataLoader) {
-	time.Sleep(l.wait)
+// timeout ends the batch once the wait duration passed.
+func (b *batch[K, V]) timeout(l *TypedDataLoader[K, V]) {
+	// keep collecting keys while waiting for the rate limiter
+	var admitted bool
+	if l.limiter != nil {
+		admitted = l.limiter.Wait(b.ctx) == nil
+	}
+
 	l.mu.Lock()
 
 	// we must have hit a batch limit and are already finalizing this batch
@@ -202,13 +900,242 @@ func (b *batch) startTimer(l *DataLoader) {
 		return
 	}
 
+	b.closing = true
+	b.admitted = admitted
 	l.batch = nil
 	l.mu.Unlock()
 
 	b.end(l)
 }
 
-func (b *batch) end(l *DataLoader) {
-	b.data, b.error = l.fetch(b.keys)
+// fetchNow closes the batch for new keys and fetches it in the calling goroutine,
+// unless it is already fetched. Used in synchronous mode.
+func (b *batch[K, V]) fetchNow(l *TypedDataLoader[K, V]) {
+	l.mu.Lock()
+	if l.batch == b {
+		b.closing = true
+		l.batch = nil
+	}
+	l.mu.Unlock()
+	b.run(l)
+}
+
+// run ends the batch unless it is already ended or being ended by run.
+// Batches ended by run must not be ended otherwise.
+func (b *batch[K, V]) run(l *TypedDataLoader[K, V]) {
+	b.once.Do(func() { b.end(l) })
+}
+
+func (b *batch[K, V]) end(l *TypedDataLoader[K, V]) {
+	l.mu.Lock()
+	ctx := context.WithValue(b.ctx, callersKey{}, b.callers)
+	ctx = context.WithValue(ctx, metaKey{}, b.meta)
+	b.callers, b.meta = nil, nil
+	l.mu.Unlock()
+
+	data, errs, duration := b.fetch(l, ctx)
+	invalid := validateFetch(len(b.keys), len(data), errs)
+	if invalid != nil && l.strict {
+		data, errs, invalid = nil, []error{invalid}, nil
+	}
+	stops := b.finish(l, data, errs, invalid)
 	close(b.done)
+	l.flushL2()
+
+	l.hookBatchDone(ctx, b.keys, duration, errs)
+
+	for _, stop := range stops {
+		stop()
+	}
+	b.cancel()
+
+	l.mu.Lock()
+	delete(l.running, b)
+	l.drain(len(b.keys))
+	l.mu.Unlock()
+	l.wg.Done()
+}
+
+// fetch sends the batch to the fetcher once admitted by the rate limiter,
+// the concurrent fetch limit and the circuit breaker.
+// ctx is the fetch context of the batch carrying its callers and metadata.
+// Returns the fetched values and errors and the fetch duration.
+func (b *batch[K, V]) fetch(l *TypedDataLoader[K, V], ctx context.Context) ([]V, []error, time.Duration) {
+	if err := b.admit(l, ctx); err != nil {
+		return nil, []error{err}, 0
+	}
+	if err := l.fetching.acquire(ctx); err != nil {
+		return nil, []error{err}, 0
+	}
+	defer l.fetching.release()
+	if l.breaker != nil && !l.breaker.allow() {
+		return nil, []error{ErrCircuitOpen}, 0
+	}
+
+	l.hookBatchDispatch(ctx, b.keys)
+	start := l.clock.Now()
+	data, errs := l.fetchWithRetry(ctx, b.keys)
+	if l.breaker != nil {
+		l.breaker.record(batchError(errs))
+	}
+	// only batches that reached the fetcher count
+	l.stats.batches.Add(1)
+	l.stats.batchedKeys.Add(uint64(len(b.keys)))
+	if anyError(errs) {
+		l.stats.fetchErrors.Add(1)
+	}
+	return data, errs, l.clock.Now().Sub(start)
+}
+
+// finish stores the result of every key of the batch for the waiting callers
+// and caches it exactly once. Returns the context watchers to stop.
+func (b *batch[K, V]) finish(l *TypedDataLoader[K, V], data []V, errs []error, invalid error) []func() bool {
+	b.data = make([]V, len(b.keys))
+	b.error = make([]error, len(b.keys))
+	for pos, key := range b.keys {
+		value, err := result(pos, data, errs, invalid)
+		if err == nil && l.transform != nil {
+			value, err = l.safeTransform(key, value)
+		}
+		if errors.Is(err, ErrNotFound) && l.notFound == NotFoundNil {
+			var zero V
+			value, err = zero, nil
+		}
+		b.data[pos], b.error[pos] = value, err
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for pos, key := range b.keys {
+		id := l.id(key)
+		value, err := b.data[pos], b.error[pos]
+		l.land(id, b)
+
+		// the key was cleared while fetching
+		if f, ok := l.inflight[id]; !ok || f.batch != b {
+			continue
+		}
+		delete(l.inflight, id)
+		switch {
+		case err == nil && l.skipNil && isNil(value):
+			// returned but fetched again by the next load
+		case err == nil:
+			delete(l.negatives, id)
+			l.cache.Set(id, value)
+			l.indexValue(id, value)
+		case errors.Is(err, ErrNotFound) && l.notFound == NotFoundCache:
+			l.setNegative(id, negative{err: err})
+		case l.errorTTL > 0 && keyError(pos, b.keys, errs) && cacheableError(err):
+			l.setNegative(id, negative{err: err, expires: l.clock.Now().Add(l.errorTTL)})
+		}
+	}
+
+	stops := b.stops
+	b.stops = nil
+	return stops
+}
+
+// result returns the value and error fetched for the key at pos.
+func result[V any](pos int, data []V, errs []error, invalid error) (V, error) {
+	var value V
+	if pos < len(data) {
+		value = data[pos]
+	}
+
+	var err error
+	// its convenient to be able to return a single error for everything
+	if len(errs) == 1 {
+		err = errs[0]
+	} else if pos < len(errs) {
+		err = errs[pos]
+	}
+
+	// don't silently return a zero value for positions the fetcher didn't return
+	if err == nil && invalid != nil && (pos >= len(data) || len(errs) > 1 && pos >= len(errs)) {
+		err = invalid
+	}
+	return value, err
+}
+
+// keyError returns true if the fetcher returned an error for the key at pos
+// in a list of one error per key. Single errors are errors of the whole batch (see batchError),
+// like the errors the DataLoader fails batches with itself, e.g. ErrCircuitOpen or *FetchPanicError.
+func keyError[K any](pos int, keys []K, errs []error) bool {
+	return len(errs) == len(keys) && batchError(errs) == nil && errs[pos] != nil
+}
+
+// isNil returns true if value is a nil pointer, interface, map, slice, channel or function.
+func isNil(value interface{}) bool {
+	if value == nil {
+		return true
+	}
+	switch v := reflect.ValueOf(value); v.Kind() {
+	case reflect.Ptr, reflect.Interface, reflect.Map, reflect.Slice, reflect.Chan, reflect.Func:
+		return v.IsNil()
+	}
+	return false
+}
+
+// anyError returns true if any of errs is non-nil.
+func anyError(errs []error) bool {
+	for _, err := range errs {
+		if err != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// validateFetch returns a *FetchResultError if the fetcher didn't return one value per key
+// and either no, one or one error per key. A single non-nil error fails the whole batch
+// and allows any number of values.
+func validateFetch(keys, values int, errs []error) error {
+	if len(errs) == 1 && errs[0] != nil {
+		return nil
+	}
+	if values == keys && (len(errs) <= 1 || len(errs) == keys) {
+		return nil
+	}
+	return &FetchResultError{Keys: keys, Values: values, Errors: len(errs)}
+}
+
+// safeFetch calls the fetcher and converts a panic into an error for the whole batch.
+func (l *TypedDataLoader[K, V]) safeFetch(ctx context.Context, keys []K) (data []V, errs []error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err := &FetchPanicError{Recovered: r, Stack: debug.Stack()}
+			data, errs = nil, []error{err}
+			if l.onPanic != nil {
+				l.onPanic(err)
+			}
+		}
+	}()
+	return l.fetch(ctx, keys)
+}
+
+// Occurs when the fetcher panics while loading a batch.
+// Every key of the batch gets this error.
+type FetchPanicError struct {
+	// The value passed to panic.
+	Recovered interface{}
+	// The stack trace of the panicking goroutine.
+	Stack []byte
+}
+
+func (e *FetchPanicError) Error() string {
+	return fmt.Sprintf("fetcher panicked: %v", e.Recovered)
+}
+
+// Occurs when the fetcher returned a number of values or errors not matching the keys.
+type FetchResultError struct {
+	// The number of keys passed to the fetcher.
+	Keys int
+	// The number of values returned by the fetcher.
+	Values int
+	// The number of errors returned by the fetcher.
+	Errors int
+}
+
+func (e *FetchResultError) Error() string {
+	return fmt.Sprintf("fetcher returned %d values and %d errors for %d keys", e.Values, e.Errors, e.Keys)
 }
@@ -0,0 +1,413 @@
+package dataloaders
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+)
+
+// countingFetcher counts the fetched keys and records the batches of fetch.
+type countingFetcher struct {
+	mu      sync.Mutex
+	fetched map[int]int
+	batches [][]int
+	fetch   func(keys []int) ([]int, []error)
+}
+
+func newCountingFetcher(fetch func(keys []int) ([]int, []error)) *countingFetcher {
+	return &countingFetcher{fetched: map[int]int{}, fetch: fetch}
+}
+
+// echo returns the keys as values.
+func echo(keys []int) ([]int, []error) {
+	return keys, nil
+}
+
+// echoFetcher is the fetcher of echo.
+func echoFetcher(ctx context.Context, keys []int) ([]int, []error) {
+	return echo(keys)
+}
+
+func (f *countingFetcher) fetcher(ctx context.Context, keys []int) ([]int, []error) {
+	f.mu.Lock()
+	for _, key := range keys {
+		f.fetched[key]++
+	}
+	f.batches = append(f.batches, append([]int(nil), keys...))
+	f.mu.Unlock()
+	return f.fetch(keys)
+}
+
+func (f *countingFetcher) fetches(key int) int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.fetched[key]
+}
+
+// batchSizes returns the number of keys of every fetched batch.
+func (f *countingFetcher) batchSizes() []int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	sizes := make([]int, len(f.batches))
+	for i, keys := range f.batches {
+		sizes[i] = len(keys)
+	}
+	return sizes
+}
+
+type ctxKey struct{}
+
+func TestFetchContextValues(t *testing.T) {
+	var got interface{}
+	l := NewTyped(func(ctx context.Context, keys []int) ([]int, []error) {
+		got = ctx.Value(ctxKey{})
+		return keys, nil
+	})
+	ctx := context.WithValue(context.Background(), ctxKey{}, "request")
+	if _, err := l.Load(ctx, 1); err != nil {
+		t.Fatal(err)
+	}
+	if got != "request" {
+		t.Fatalf("fetch context carried %v, want the value of the load", got)
+	}
+}
+
+func TestLoadCanceled(t *testing.T) {
+	fetching := make(chan struct{})
+	l := NewTyped(func(ctx context.Context, keys []int) ([]int, []error) {
+		close(fetching)
+		<-ctx.Done()
+		return nil, []error{ctx.Err()}
+	})
+	ctx, cancel := context.WithCancel(context.Background())
+	thunk := l.LoadThunk(ctx, 1)
+	<-fetching
+	// the fetch context is canceled once the only waiting load is
+	cancel()
+	if _, err := thunk(); !errors.Is(err, context.Canceled) {
+		t.Fatalf("got %v, want %v", err, context.Canceled)
+	}
+}
+
+func TestBatchesAndCachesKeys(t *testing.T) {
+	clock := NewFakeClock(time.Now())
+	f := newCountingFetcher(echo)
+	l := NewTyped(f.fetcher, WithClock(clock))
+	ctx := context.Background()
+	thunks := map[int]func() (int, error){}
+	for _, key := range []int{1, 2, 3, 2} {
+		thunks[key] = l.LoadThunk(ctx, key)
+	}
+	clock.Advance(defaultWait)
+	for key, thunk := range thunks {
+		if v, err := thunk(); err != nil || v != key {
+			t.Fatalf("key %d: got %d, %v", key, v, err)
+		}
+	}
+	if v, err := l.Load(ctx, 2); err != nil || v != 2 {
+		t.Fatalf("cached key: got %d, %v", v, err)
+	}
+	if sizes := f.batchSizes(); len(sizes) != 1 || sizes[0] != 3 {
+		t.Fatalf("batch sizes %v, want [3]", sizes)
+	}
+}
+
+func TestUntypedDataLoader(t *testing.T) {
+	l := New(func(ctx context.Context, keys []Key) ([]Value, []error) {
+		values := make([]Value, len(keys))
+		for i, key := range keys {
+			values[i] = fmt.Sprint("v", key)
+		}
+		return values, nil
+	})
+	values, errs := l.LoadAll(context.Background(), []Key{1, "a"})
+	if anyError(errs) || values[0] != "v1" || values[1] != "va" {
+		t.Fatalf("got %v, %v", values, errs)
+	}
+}
+
+func TestFetchPanic(t *testing.T) {
+	var handled *FetchPanicError
+	l := NewTyped(func(ctx context.Context, keys []int) ([]int, []error) {
+		panic("boom")
+	}, WithOnPanic(func(err *FetchPanicError) { handled = err }))
+	_, errs := l.LoadAll(context.Background(), []int{1, 2})
+	for i, err := range errs {
+		var panicErr *FetchPanicError
+		if !errors.As(err, &panicErr) || panicErr.Recovered != "boom" || len(panicErr.Stack) == 0 {
+			t.Fatalf("key %d: got %v, want a *FetchPanicError", i+1, err)
+		}
+	}
+	if handled == nil {
+		t.Fatal("panic handler not called")
+	}
+}
+
+func TestFetchResultError(t *testing.T) {
+	short := func(keys []int) ([]int, []error) {
+		return keys[:1], nil
+	}
+	for name, strict := range map[string]bool{"lenient": false, "strict": true} {
+		t.Run(name, func(t *testing.T) {
+			opts := []Option{WithSynchronous()}
+			if strict {
+				opts = append(opts, WithStrict())
+			}
+			l := NewTyped(newCountingFetcher(short).fetcher, opts...)
+			values, errs := l.LoadAll(context.Background(), []int{1, 2})
+			var resultErr *FetchResultError
+			if !errors.As(errs[1], &resultErr) || resultErr.Keys != 2 || resultErr.Values != 1 {
+				t.Fatalf("missing value: got %v, want a *FetchResultError", errs[1])
+			}
+			if strict && !errors.As(errs[0], &resultErr) {
+				t.Fatalf("returned value: got %v, want a *FetchResultError", errs[0])
+			}
+			if !strict && (errs[0] != nil || values[0] != 1) {
+				t.Fatalf("returned value: got %d, %v", values[0], errs[0])
+			}
+		})
+	}
+}
+
+func TestNotFoundPolicies(t *testing.T) {
+	missing := func(keys []int) ([]int, []error) {
+		return make([]int, len(keys)), []error{ErrNotFound}
+	}
+	for policy, want := range map[NotFoundPolicy]struct {
+		err     error
+		fetches int
+	}{
+		NotFoundNil:   {nil, 1},
+		NotFoundError: {ErrNotFound, 2},
+		NotFoundCache: {ErrNotFound, 1},
+	} {
+		t.Run(policy.String(), func(t *testing.T) {
+			f := newCountingFetcher(missing)
+			l := NewTyped(f.fetcher, WithNotFound(policy), WithSynchronous())
+			for i := 0; i < 2; i++ {
+				if v, err := l.Load(context.Background(), 1); !errors.Is(err, want.err) || v != 0 {
+					t.Fatalf("load %d: got %d, %v, want %v", i, v, err, want.err)
+				}
+			}
+			if n := f.fetches(1); n != want.fetches {
+				t.Fatalf("fetched missing key %d times, want %d", n, want.fetches)
+			}
+		})
+	}
+}
+
+func TestDispatch(t *testing.T) {
+	f := newCountingFetcher(echo)
+	// the clock never ends the batch
+	l := NewTyped(f.fetcher, WithClock(NewFakeClock(time.Now())))
+	thunk := l.LoadThunk(context.Background(), 1)
+	l.Dispatch()
+	if v, err := thunk(); err != nil || v != 1 {
+		t.Fatalf("got %d, %v", v, err)
+	}
+}
+
+func TestClearAll(t *testing.T) {
+	f := newCountingFetcher(echo)
+	l := NewTyped(f.fetcher, WithSynchronous())
+	ctx := context.Background()
+	l.LoadAll(ctx, []int{1, 2})
+	l.ClearAll()
+	l.LoadAll(ctx, []int{1, 2})
+	if f.fetches(1) != 2 || f.fetches(2) != 2 {
+		t.Fatalf("fetched keys %d and %d times, want 2", f.fetches(1), f.fetches(2))
+	}
+}
+
+func TestPrimeMany(t *testing.T) {
+	f := newCountingFetcher(echo)
+	l := NewTyped(f.fetcher, WithSynchronous())
+	l.Prime(1, 1)
+	if n := l.PrimeMany(map[int]int{1: 10, 2: 20}); n != 1 {
+		t.Fatalf("primed %d keys, want 1", n)
+	}
+	values, _ := l.LoadAll(context.Background(), []int{1, 2})
+	if values[0] != 1 || values[1] != 20 || f.fetches(1)+f.fetches(2) != 0 {
+		t.Fatalf("got %v, want the primed values without fetching", values)
+	}
+}
+
+func TestErrorCacheKeyErrors(t *testing.T) {
+	errOdd := errors.New("odd")
+	f := newCountingFetcher(func(keys []int) ([]int, []error) {
+		errs := make([]error, len(keys))
+		for i, key := range keys {
+			if key%2 == 1 {
+				errs[i] = errOdd
+			}
+		}
+		return keys, errs
+	})
+	l := NewTyped(f.fetcher, WithErrorCache(time.Minute))
+	ctx := context.Background()
+	for i := 0; i < 2; i++ {
+		_, errs := l.LoadAll(ctx, []int{1, 2})
+		if !errors.Is(errs[0], errOdd) || errs[1] != nil {
+			t.Fatalf("load %d: got errors %v", i, errs)
+		}
+	}
+	if n := f.fetches(1); n != 1 {
+		t.Fatalf("fetched failed key %d times, want 1", n)
+	}
+}
+
+func TestErrorCacheSkipsBatchErrors(t *testing.T) {
+	errDown := errors.New("down")
+	for name, fetch := range map[string]func(keys []int) ([]int, []error){
+		"error": func(keys []int) ([]int, []error) { return nil, []error{errDown} },
+		"panic": func(keys []int) ([]int, []error) { panic(errDown) },
+	} {
+		t.Run(name, func(t *testing.T) {
+			f := newCountingFetcher(fetch)
+			l := NewTyped(f.fetcher, WithErrorCache(time.Minute))
+			ctx := context.Background()
+			for i := 0; i < 2; i++ {
+				if _, err := l.Load(ctx, 1); err == nil {
+					t.Fatalf("load %d: no error", i)
+				}
+			}
+			if n := f.fetches(1); n != 2 {
+				t.Fatalf("fetched key %d times, want 2", n)
+			}
+		})
+	}
+}
+
+func TestErrorCacheSkipsCircuitOpen(t *testing.T) {
+	errDown := errors.New("down")
+	f := newCountingFetcher(func(keys []int) ([]int, []error) { return nil, []error{errDown} })
+	l := NewTyped(f.fetcher, WithErrorCache(time.Minute), WithCircuitBreaker(1, time.Hour))
+	ctx := context.Background()
+	if _, err := l.Load(ctx, 1); !errors.Is(err, errDown) {
+		t.Fatalf("got %v, want %v", err, errDown)
+	}
+	if _, err := l.Load(ctx, 2); !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("got %v, want %v", err, ErrCircuitOpen)
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if n := len(l.negatives); n != 0 {
+		t.Fatalf("cached %d errors, want 0", n)
+	}
+}
+
+func TestLoadJoinsInFlightFetch(t *testing.T) {
+	fetching, release := make(chan struct{}), make(chan struct{})
+	f := newCountingFetcher(func(keys []int) ([]int, []error) {
+		close(fetching)
+		<-release
+		return keys, nil
+	})
+	l := NewTyped(f.fetcher, WithWait(time.Millisecond))
+	ctx := context.Background()
+	first := l.LoadThunk(ctx, 1)
+	<-fetching
+	// the key is not cached yet, but the second load shares the running fetch
+	second := l.LoadThunk(ctx, 1)
+	close(release)
+	for _, thunk := range []func() (int, error){first, second} {
+		if v, err := thunk(); err != nil || v != 1 {
+			t.Fatalf("got %d, %v", v, err)
+		}
+	}
+	if n := f.fetches(1); n != 1 {
+		t.Fatalf("fetched key %d times, want 1", n)
+	}
+}
+
+func TestLoadAllThunk(t *testing.T) {
+	clock := NewFakeClock(time.Now())
+	fetched := make(chan struct{})
+	f := newCountingFetcher(func(keys []int) ([]int, []error) {
+		close(fetched)
+		return keys, nil
+	})
+	l := NewTyped(f.fetcher, WithClock(clock))
+	thunk := l.LoadAllThunk(context.Background(), []int{1, 2, 1})
+	// the keys are batched before the thunk is called
+	clock.Advance(defaultWait)
+	select {
+	case <-fetched:
+	case <-time.After(time.Second):
+		t.Fatal("keys not fetched before calling the thunk")
+	}
+	values, errs := thunk()
+	if anyError(errs) || fmt.Sprint(values) != "[1 2 1]" {
+		t.Fatalf("got %v, %v", values, errs)
+	}
+	if sizes := f.batchSizes(); len(sizes) != 1 || sizes[0] != 2 {
+		t.Fatalf("batch sizes %v, want [2]", sizes)
+	}
+}
+
+func TestRefresh(t *testing.T) {
+	f := &versionFetcher{}
+	l := NewTyped(f.fetcher, WithSynchronous())
+	ctx := context.Background()
+	l.Load(ctx, 1)
+	if v, err := l.Refresh(ctx, 1); err != nil || v != 2 {
+		t.Fatalf("refreshed: got %d, %v", v, err)
+	}
+	if v, err := l.Load(ctx, 1); err != nil || v != 2 {
+		t.Fatalf("refreshed value not cached: got %d, %v", v, err)
+	}
+	values, errs := l.RefreshMany(ctx, []int{1, 2})
+	if anyError(errs) || fmt.Sprint(values) != "[3 3]" {
+		t.Fatalf("refreshed many: got %v, %v", values, errs)
+	}
+}
+
+func TestEarlyDispatchStopsTimer(t *testing.T) {
+	clock := NewFakeClock(time.Now())
+	l := NewTyped(echoFetcher, WithClock(clock), WithMaxBatch(2))
+	ctx := context.Background()
+	thunk := l.LoadThunk(ctx, 1)
+	if n := clock.Timers(); n != 1 {
+		t.Fatalf("%d timers of the batch, want 1", n)
+	}
+	l.Dispatch()
+	thunk()
+	// the full batch is sent without waiting
+	l.LoadAll(ctx, []int{2, 3})
+	if n := clock.Timers(); n != 0 {
+		t.Fatalf("%d timers left after dispatching early, want 0", n)
+	}
+}
+
+func TestRepeatedKeysDontFillBatch(t *testing.T) {
+	clock := NewFakeClock(time.Now())
+	f := newCountingFetcher(echo)
+	l := NewTyped(f.fetcher, WithClock(clock), WithMaxBatch(2))
+	ctx := context.Background()
+	var thunks []func() (int, error)
+	for _, key := range []int{1, 1, 1, 2} {
+		thunks = append(thunks, l.LoadThunk(ctx, key))
+	}
+	for _, thunk := range thunks {
+		thunk()
+	}
+	if fmt.Sprint(f.batches) != "[[1 2]]" {
+		t.Fatalf("fetched batches %v, want [[1 2]]", f.batches)
+	}
+}
+
+func TestLoadAllRepeatedKeysWithoutCache(t *testing.T) {
+	f := newCountingFetcher(echo)
+	l := NewTyped(f.fetcher, WithSynchronous())
+	values, errs := l.LoadAll(context.Background(), []int{1, 1, 2, 1, 2}, NoCache())
+	if anyError(errs) || fmt.Sprint(values) != "[1 1 2 1 2]" {
+		t.Fatalf("got %v, %v", values, errs)
+	}
+	// repeated keys are fetched once even without the cache deduplicating them
+	if f.fetches(1) != 1 || f.fetches(2) != 1 || l.Stats().Misses != 2 {
+		t.Fatalf("fetched keys %d and %d times with %d misses, want once", f.fetches(1), f.fetches(2), l.Stats().Misses)
+	}
+}
@@ -0,0 +1,45 @@
+// Package dataloaderscodec provides dataloaders.Codec implementations
+// for MessagePack and Protocol Buffers, see also dataloaders.JSONCodec.
+package dataloaderscodec
+
+import (
+	"github.com/robinbraemer/dataloaders"
+	"github.com/vmihailenco/msgpack/v5"
+	"google.golang.org/protobuf/proto"
+)
+
+// MsgpackCodec returns a Codec encoding values as MessagePack.
+func MsgpackCodec[V any]() dataloaders.Codec[V] {
+	return msgpackCodec[V]{}
+}
+
+type msgpackCodec[V any] struct{}
+
+func (msgpackCodec[V]) Marshal(value V) ([]byte, error) {
+	return msgpack.Marshal(value)
+}
+
+func (msgpackCodec[V]) Unmarshal(data []byte) (V, error) {
+	var value V
+	err := msgpack.Unmarshal(data, &value)
+	return value, err
+}
+
+// ProtoCodec returns a Codec encoding generated protobuf messages, e.g. *pb.User.
+func ProtoCodec[V proto.Message]() dataloaders.Codec[V] {
+	return protoCodec[V]{}
+}
+
+type protoCodec[V proto.Message] struct{}
+
+func (protoCodec[V]) Marshal(value V) ([]byte, error) {
+	return proto.Marshal(value)
+}
+
+func (protoCodec[V]) Unmarshal(data []byte) (V, error) {
+	// the message type is known even from a nil message
+	var zero V
+	value := zero.ProtoReflect().Type().New().Interface().(V)
+	err := proto.Unmarshal(data, value)
+	return value, err
+}
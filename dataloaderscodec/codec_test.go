@@ -0,0 +1,35 @@
+package dataloaderscodec
+
+import (
+	"testing"
+
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+type user struct {
+	ID   int
+	Name string
+}
+
+func TestMsgpackCodec(t *testing.T) {
+	c := MsgpackCodec[user]()
+	data, err := c.Marshal(user{ID: 1, Name: "a"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if u, err := c.Unmarshal(data); err != nil || u != (user{ID: 1, Name: "a"}) {
+		t.Fatalf("got %+v, %v", u, err)
+	}
+}
+
+func TestProtoCodec(t *testing.T) {
+	c := ProtoCodec[*wrapperspb.StringValue]()
+	data, err := c.Marshal(wrapperspb.String("a"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the message is created from the type parameter
+	if v, err := c.Unmarshal(data); err != nil || v.GetValue() != "a" {
+		t.Fatalf("got %v, %v", v, err)
+	}
+}
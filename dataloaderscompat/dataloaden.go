@@ -0,0 +1,71 @@
+package dataloaderscompat
+
+import (
+	"context"
+
+	"github.com/robinbraemer/dataloaders"
+)
+
+// FromDataloaden converts the fetch function of a dataloaden loader config into a fetcher.
+// The fetch context is not passed on.
+func FromDataloaden[K comparable, V any](fetch func(keys []K) ([]V, []error)) dataloaders.TypedFetcher[K, V] {
+	return func(ctx context.Context, keys []K) ([]V, []error) {
+		return fetch(keys)
+	}
+}
+
+// DataloadenLoader is implemented by loaders generated by dataloaden.
+type DataloadenLoader[K comparable, V any] interface {
+	LoadAll(keys []K) ([]V, []error)
+}
+
+// FromDataloadenLoader returns a fetcher loading the keys with the dataloaden loader.
+func FromDataloadenLoader[K comparable, V any](l DataloadenLoader[K, V]) dataloaders.TypedFetcher[K, V] {
+	return func(ctx context.Context, keys []K) ([]V, []error) {
+		return l.LoadAll(keys)
+	}
+}
+
+// Dataloaden wraps a DataLoader with the API of loaders generated by dataloaden,
+// which don't take a context.
+type Dataloaden[K comparable, V any] struct {
+	l *dataloaders.TypedDataLoader[K, V]
+	// passed to all loads
+	ctx context.Context
+}
+
+// ToDataloaden wraps the DataLoader, loading with ctx.
+func ToDataloaden[K comparable, V any](ctx context.Context, l *dataloaders.TypedDataLoader[K, V]) *Dataloaden[K, V] {
+	return &Dataloaden[K, V]{l: l, ctx: ctx}
+}
+
+// Load a value by key, batching and caching will be applied automatically.
+func (d *Dataloaden[K, V]) Load(key K) (V, error) {
+	return d.l.Load(d.ctx, key)
+}
+
+// LoadThunk returns a function that when called will block waiting for the value.
+func (d *Dataloaden[K, V]) LoadThunk(key K) func() (V, error) {
+	return d.l.LoadThunk(d.ctx, key)
+}
+
+// LoadAll fetches many keys at once.
+func (d *Dataloaden[K, V]) LoadAll(keys []K) ([]V, []error) {
+	return d.l.LoadAll(d.ctx, keys)
+}
+
+// LoadAllThunk returns a function that when called will block waiting for the values.
+func (d *Dataloaden[K, V]) LoadAllThunk(keys []K) func() ([]V, []error) {
+	return d.l.LoadAllThunk(d.ctx, keys)
+}
+
+// Prime the cache with the provided key and value.
+// If the key already exists, no change is made and false is returned.
+func (d *Dataloaden[K, V]) Prime(key K, value V) bool {
+	return d.l.Prime(key, value)
+}
+
+// Clear the value at key from the cache, if it exists.
+func (d *Dataloaden[K, V]) Clear(key K) {
+	d.l.Clear(key)
+}
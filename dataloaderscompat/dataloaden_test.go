@@ -0,0 +1,32 @@
+package dataloaderscompat
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/robinbraemer/dataloaders"
+)
+
+func TestDataloadenAdapters(t *testing.T) {
+	l := dataloaders.NewTyped(FromDataloaden(func(keys []int) ([]int, []error) {
+		return double(context.Background(), keys)
+	}), dataloaders.WithSynchronous())
+	d := ToDataloaden(context.Background(), l)
+	if v, err := d.Load(1); err != nil || v != 2 {
+		t.Fatalf("got %d, %v", v, err)
+	}
+	if !d.Prime(3, 7) || d.Prime(3, 8) {
+		t.Fatal("primed key not reported once")
+	}
+	// a DataLoader backed by the dataloaden loader
+	back := dataloaders.NewTyped(FromDataloadenLoader[int, int](d), dataloaders.WithSynchronous())
+	values, errs := back.LoadAll(context.Background(), []int{2, 3})
+	if fmt.Sprint(values) != "[4 7]" || errs[0] != nil || errs[1] != nil {
+		t.Fatalf("got %v, %v", values, errs)
+	}
+	d.Clear(3)
+	if v, _ := d.Load(3); v != 6 {
+		t.Fatalf("got %d, want the cleared key refetched", v)
+	}
+}
@@ -0,0 +1,90 @@
+// Package dataloaderscompat adapts loaders of github.com/graph-gophers/dataloader
+// and github.com/vektah/dataloaden to DataLoaders and vice versa, e.g. to migrate incrementally.
+package dataloaderscompat
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/graph-gophers/dataloader/v7"
+	"github.com/robinbraemer/dataloaders"
+)
+
+// FromBatchFunc converts a graph-gophers batch function into a fetcher.
+func FromBatchFunc[K comparable, V any](batch dataloader.BatchFunc[K, V]) dataloaders.TypedFetcher[K, V] {
+	return func(ctx context.Context, keys []K) ([]V, []error) {
+		results := batch(ctx, keys)
+		values := make([]V, len(results))
+		errs := make([]error, len(results))
+		for i, r := range results {
+			if r != nil {
+				values[i], errs[i] = r.Data, r.Error
+			}
+		}
+		return values, errs
+	}
+}
+
+// FromGraphGophers returns a fetcher loading the keys with the graph-gophers loader.
+func FromGraphGophers[K comparable, V any](l dataloader.Interface[K, V]) dataloaders.TypedFetcher[K, V] {
+	return func(ctx context.Context, keys []K) ([]V, []error) {
+		return l.LoadMany(ctx, keys)()
+	}
+}
+
+// ToBatchFunc converts a fetcher into a graph-gophers batch function.
+func ToBatchFunc[K comparable, V any](fetch dataloaders.TypedFetcher[K, V]) dataloader.BatchFunc[K, V] {
+	return func(ctx context.Context, keys []K) []*dataloader.Result[V] {
+		values, errs := fetch(ctx, keys)
+		results := make([]*dataloader.Result[V], len(keys))
+		for i := range keys {
+			r := &dataloader.Result[V]{}
+			if i < len(values) {
+				r.Data = values[i]
+			}
+			// a single error is returned for all keys
+			if len(errs) == 1 {
+				r.Error = errs[0]
+			} else if i < len(errs) {
+				r.Error = errs[i]
+			}
+			if r.Error == nil && i >= len(values) {
+				r.Error = fmt.Errorf("fetcher returned no value for key %v", keys[i])
+			}
+			results[i] = r
+		}
+		return results
+	}
+}
+
+// ToGraphGophers returns a graph-gophers loader backed by the DataLoader.
+func ToGraphGophers[K comparable, V any](l *dataloaders.TypedDataLoader[K, V]) dataloader.Interface[K, V] {
+	return &graphGophers[K, V]{l: l}
+}
+
+type graphGophers[K comparable, V any] struct {
+	l *dataloaders.TypedDataLoader[K, V]
+}
+
+func (g *graphGophers[K, V]) Load(ctx context.Context, key K) dataloader.Thunk[V] {
+	return g.l.LoadThunk(ctx, key)
+}
+
+func (g *graphGophers[K, V]) LoadMany(ctx context.Context, keys []K) dataloader.ThunkMany[V] {
+	return g.l.LoadAllThunk(ctx, keys)
+}
+
+func (g *graphGophers[K, V]) Clear(ctx context.Context, key K) dataloader.Interface[K, V] {
+	g.l.Clear(key)
+	return g
+}
+
+func (g *graphGophers[K, V]) ClearAll() dataloader.Interface[K, V] {
+	g.l.ClearAll()
+	return g
+}
+
+func (g *graphGophers[K, V]) Prime(ctx context.Context, key K, value V) dataloader.Interface[K, V] {
+	g.l.Prime(key, value)
+	return g
+}
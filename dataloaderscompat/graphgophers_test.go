@@ -0,0 +1,62 @@
+package dataloaderscompat
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/graph-gophers/dataloader/v7"
+	"github.com/robinbraemer/dataloaders"
+)
+
+func double(ctx context.Context, keys []int) ([]int, []error) {
+	values := make([]int, len(keys))
+	for i, key := range keys {
+		values[i] = key * 2
+	}
+	return values, nil
+}
+
+func TestBatchFuncRoundTrip(t *testing.T) {
+	fetch := FromBatchFunc(ToBatchFunc(double))
+	values, errs := fetch(context.Background(), []int{1, 2})
+	if fmt.Sprint(values) != "[2 4]" || errs[0] != nil || errs[1] != nil {
+		t.Fatalf("got %v, %v", values, errs)
+	}
+}
+
+func TestToBatchFuncErrors(t *testing.T) {
+	errDown := errors.New("down")
+	results := ToBatchFunc(func(ctx context.Context, keys []int) ([]int, []error) {
+		return nil, []error{errDown}
+	})(context.Background(), []int{1, 2})
+	// a single error fails every key
+	for i, r := range results {
+		if r.Error != errDown {
+			t.Fatalf("key %d: got %v, want %v", i, r.Error, errDown)
+		}
+	}
+	results = ToBatchFunc(func(ctx context.Context, keys []int) ([]int, []error) {
+		return []int{1}, nil
+	})(context.Background(), []int{1, 2})
+	if results[0].Error != nil || results[1].Error == nil {
+		t.Fatalf("got %v and %v, want an error for the key without value", results[0].Error, results[1].Error)
+	}
+}
+
+func TestGraphGophersAdapters(t *testing.T) {
+	l := dataloaders.NewTyped(double, dataloaders.WithSynchronous())
+	var gg dataloader.Interface[int, int] = ToGraphGophers(l)
+	ctx := context.Background()
+	if v, err := gg.Load(ctx, 1)(); err != nil || v != 2 {
+		t.Fatalf("got %d, %v", v, err)
+	}
+	gg.Prime(ctx, 3, 7)
+	// a DataLoader backed by the graph-gophers loader
+	back := dataloaders.NewTyped(FromGraphGophers(gg), dataloaders.WithSynchronous())
+	values, errs := back.LoadAll(ctx, []int{2, 3})
+	if fmt.Sprint(values) != "[4 7]" || errs[0] != nil || errs[1] != nil {
+		t.Fatalf("got %v, %v", values, errs)
+	}
+}
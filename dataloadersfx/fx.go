@@ -0,0 +1,35 @@
+// Package dataloadersfx provides the per-request loader factory in uber/fx applications,
+// built from the registrations provided by the modules of the application:
+//
+// 	fx.New(
+// 		dataloadersfx.Module,
+// 		dataloadersfx.Provide(func(db *sql.DB) dataloaders.Registration {
+// 			return func(b *dataloaders.RegistryBuilder) {
+// 				b.Object("account").Attr("id", fetchAccountsByID(db))
+// 			}
+// 		}),
+// 		fx.Invoke(func(factory dataloaders.Factory) { ... }),
+// 	)
+package dataloadersfx
+
+import (
+	"github.com/robinbraemer/dataloaders"
+	"go.uber.org/fx"
+)
+
+// Group is the value group of the provided registrations.
+const Group = "dataloaders"
+
+// Module provides the dataloaders.Factory built from all registrations of Group.
+var Module = fx.Module("dataloaders",
+	fx.Provide(fx.Annotate(newFactory, fx.ParamTags(`group:"`+Group+`"`))),
+)
+
+// Provide provides the dataloaders.Registration returned by constructor to Group.
+func Provide(constructor interface{}) fx.Option {
+	return fx.Provide(fx.Annotate(constructor, fx.ResultTags(`group:"`+Group+`"`)))
+}
+
+func newFactory(registrations []dataloaders.Registration) dataloaders.Factory {
+	return dataloaders.NewFactory(registrations...)
+}
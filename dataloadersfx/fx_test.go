@@ -0,0 +1,38 @@
+package dataloadersfx
+
+import (
+	"context"
+	"testing"
+
+	"github.com/robinbraemer/dataloaders"
+	"go.uber.org/fx"
+	"go.uber.org/fx/fxtest"
+)
+
+// registration returns the registration of the object type loading nil values.
+func registration(objectType dataloaders.ObjectType) func() dataloaders.Registration {
+	return func() dataloaders.Registration {
+		return func(b *dataloaders.RegistryBuilder) {
+			b.Object(objectType).Attr("id", func(ctx context.Context, keys []dataloaders.Key) ([]dataloaders.Value, []error) {
+				return make([]dataloaders.Value, len(keys)), nil
+			})
+		}
+	}
+}
+
+func TestModule(t *testing.T) {
+	var factory dataloaders.Factory
+	app := fxtest.New(t,
+		Module,
+		Provide(registration("account")),
+		Provide(registration("payment")),
+		fx.Populate(&factory),
+	)
+	app.RequireStart().RequireStop()
+	l := factory()
+	for _, objectType := range []dataloaders.ObjectType{"account", "payment"} {
+		if _, err := l.Load(context.Background(), objectType, "id", 1); err != nil {
+			t.Fatalf("%s: %v", objectType, err)
+		}
+	}
+}
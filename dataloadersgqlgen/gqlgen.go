@@ -0,0 +1,77 @@
+// Package dataloadersgqlgen wires request-scoped DataLoaders into gqlgen servers.
+// The loadergen sub-package is a gqlgen plugin generating typed load functions
+// for the objects of a schema using this package.
+package dataloadersgqlgen
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/robinbraemer/dataloaders"
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+// ErrNoLoaders is returned for loads with a context not carrying an ObjAttrDataLoader.
+var ErrNoLoaders = errors.New("no dataloaders in context")
+
+// AroundOperations carries a new ObjAttrDataLoader created by the factory in the context
+// of every operation (see dataloaders.FromContext):
+//
+// 	srv := handler.NewDefaultServer(schema)
+// 	srv.AroundOperations(dataloadersgqlgen.AroundOperations(factory))
+//
+// The loader is closed once the last response of the operation was returned,
+// like by dataloaders.Middleware, for subscriptions once they ended.
+func AroundOperations(factory dataloaders.Factory) graphql.OperationMiddleware {
+	return func(ctx context.Context, next graphql.OperationHandler) graphql.ResponseHandler {
+		l := factory()
+		op := graphql.GetOperationContext(ctx).Operation
+		subscription := op != nil && op.Operation == ast.Subscription
+		responses := next(dataloaders.NewContext(ctx, l))
+		var once sync.Once
+		return func(ctx context.Context) *graphql.Response {
+			resp := responses(ctx)
+			// deferred fragments are returned in further responses
+			if resp == nil || !subscription && (resp.HasNext == nil || !*resp.HasNext) {
+				once.Do(func() { l.Close(context.WithoutCancel(ctx)) })
+			}
+			return resp
+		}
+	}
+}
+
+// For returns the ObjAttrDataLoader carried by ctx or ErrNoLoaders.
+func For(ctx context.Context) (*dataloaders.ObjAttrDataLoader, error) {
+	l, ok := dataloaders.FromContext(ctx)
+	if !ok {
+		return nil, ErrNoLoaders
+	}
+	return l, nil
+}
+
+// Load loads the value at key at attribute of the Go type T
+// using the loaders carried by ctx, see dataloaders.LoadAs.
+func Load[T any](ctx context.Context, attribute dataloaders.Attribute, key dataloaders.Key) (T, error) {
+	l, err := For(ctx)
+	if err != nil {
+		var zero T
+		return zero, err
+	}
+	return dataloaders.LoadAs[T](ctx, l, attribute, key)
+}
+
+// LoadAll loads the values at keys at attribute of the Go type T
+// using the loaders carried by ctx, see dataloaders.LoadAllAs.
+func LoadAll[T any](ctx context.Context, attribute dataloaders.Attribute, keys []dataloaders.Key) ([]T, []error) {
+	l, err := For(ctx)
+	if err != nil {
+		errs := make([]error, len(keys))
+		for i := range errs {
+			errs[i] = err
+		}
+		return make([]T, len(keys)), errs
+	}
+	return dataloaders.LoadAllAs[T](ctx, l, attribute, keys)
+}
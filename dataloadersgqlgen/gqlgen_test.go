@@ -0,0 +1,78 @@
+package dataloadersgqlgen
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/robinbraemer/dataloaders"
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+type user struct{}
+
+func TestLoadAllWithoutLoaders(t *testing.T) {
+	values, errs := LoadAll[user](context.Background(), "id", []dataloaders.Key{1, 2})
+	if len(values) != 2 || len(errs) != 2 {
+		t.Fatalf("got %d values and %d errors, want 2", len(values), len(errs))
+	}
+	for i, err := range errs {
+		if !errors.Is(err, ErrNoLoaders) {
+			t.Fatalf("key %d: got %v, want %v", i, err, ErrNoLoaders)
+		}
+	}
+}
+
+// operation runs an operation of the type through AroundOperations
+// returning the given number of responses and the context of the operation.
+func operation(operation ast.Operation, responses int) (graphql.ResponseHandler, context.Context) {
+	ctx := graphql.WithOperationContext(context.Background(), &graphql.OperationContext{
+		Operation: &ast.OperationDefinition{Operation: operation},
+	})
+	var opCtx context.Context
+	handler := AroundOperations(func() *dataloaders.ObjAttrDataLoader {
+		return dataloaders.NewObjAttrDataLoader(nil)
+	})(ctx, func(ctx context.Context) graphql.ResponseHandler {
+		opCtx = ctx
+		return func(ctx context.Context) *graphql.Response {
+			if responses == 0 {
+				return nil
+			}
+			responses--
+			return &graphql.Response{}
+		}
+	})
+	return handler, opCtx
+}
+
+// closed returns true if the loader carried by ctx is closed.
+func closed(ctx context.Context) bool {
+	_, err := Load[user](ctx, "id", 1)
+	return errors.Is(err, dataloaders.ErrClosed)
+}
+
+func TestAroundOperationsClosesLoader(t *testing.T) {
+	handler, ctx := operation(ast.Query, 1)
+	if closed(ctx) {
+		t.Fatal("loader closed before the response")
+	}
+	handler(ctx)
+	if !closed(ctx) {
+		t.Fatal("loader not closed after the response")
+	}
+}
+
+func TestAroundOperationsClosesSubscriptionLoader(t *testing.T) {
+	handler, ctx := operation(ast.Subscription, 2)
+	for i := 0; i < 2; i++ {
+		handler(ctx)
+		if closed(ctx) {
+			t.Fatalf("loader closed after response %d of the subscription", i)
+		}
+	}
+	handler(ctx)
+	if !closed(ctx) {
+		t.Fatal("loader not closed after the subscription ended")
+	}
+}
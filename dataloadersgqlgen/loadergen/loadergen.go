@@ -0,0 +1,146 @@
+// Package loadergen is a gqlgen plugin generating typed load functions for the objects of a schema.
+//
+// For an object User it generates
+//
+// 	func LoadUser(ctx context.Context, attribute dataloaders.Attribute, key dataloaders.Key) (*model.User, error)
+// 	func LoadAllUser(ctx context.Context, attribute dataloaders.Attribute, keys []dataloaders.Key) ([]*model.User, []error)
+//
+// loading from the loaders carried by the context (see dataloadersgqlgen.AroundOperations).
+// The object types must be registered by their Go type, see dataloaders.ObjectOf.
+//
+// The plugin is added when running gqlgen with api.Generate:
+//
+// 	api.Generate(cfg, api.AddPlugin(loadergen.New("graph/loaders/loaders_gen.go", "loaders")))
+package loadergen
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"go/types"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/template"
+
+	"github.com/99designs/gqlgen/codegen"
+	"github.com/99designs/gqlgen/plugin"
+)
+
+// New creates the plugin writing the load functions to filename in the package named packageName.
+// Functions are generated for the named objects or for all objects if none are named.
+func New(filename, packageName string, objects ...string) plugin.Plugin {
+	return &Plugin{filename: filename, packageName: packageName, objects: objects}
+}
+
+// Plugin generates typed load functions, see New.
+type Plugin struct {
+	filename    string
+	packageName string
+	// the objects to generate load functions for, all if empty
+	objects []string
+}
+
+var (
+	_ plugin.Plugin        = (*Plugin)(nil)
+	_ plugin.CodeGenerator = (*Plugin)(nil)
+)
+
+// Name returns the name of the plugin.
+func (p *Plugin) Name() string {
+	return "dataloaders"
+}
+
+// GenerateCode writes the load functions of the objects.
+func (p *Plugin) GenerateCode(data *codegen.Data) error {
+	imports := map[string]string{}
+	qualifier := func(pkg *types.Package) string {
+		imports[pkg.Path()] = pkg.Name()
+		return pkg.Name()
+	}
+
+	var objects []object
+	for _, o := range data.Objects {
+		if !p.generate(o) {
+			continue
+		}
+		objects = append(objects, object{
+			Name: o.Name,
+			Type: types.TypeString(o.Reference(), qualifier),
+		})
+	}
+	sort.Slice(objects, func(i, j int) bool { return objects[i].Name < objects[j].Name })
+
+	paths := make([]string, 0, len(imports))
+	for path := range imports {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	var buf bytes.Buffer
+	err := tmpl.Execute(&buf, struct {
+		Package string
+		Imports []string
+		Objects []object
+	}{p.packageName, paths, objects})
+	if err != nil {
+		return fmt.Errorf("executing template: %w", err)
+	}
+	src, err := format.Source(buf.Bytes())
+	if err != nil {
+		return fmt.Errorf("formatting generated code: %w", err)
+	}
+	if err = os.MkdirAll(filepath.Dir(p.filename), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(p.filename, src, 0o644)
+}
+
+// generate returns true if load functions are generated for the object.
+func (p *Plugin) generate(o *codegen.Object) bool {
+	if o.Root || o.Type == nil || strings.HasPrefix(o.Name, "__") {
+		return false
+	}
+	if len(p.objects) == 0 {
+		return true
+	}
+	for _, name := range p.objects {
+		if name == o.Name {
+			return true
+		}
+	}
+	return false
+}
+
+type object struct {
+	// the name of the GraphQL object
+	Name string
+	// the qualified Go type of the loaded values
+	Type string
+}
+
+var tmpl = template.Must(template.New("loaders").Parse(`// Code generated by github.com/robinbraemer/dataloaders/dataloadersgqlgen/loadergen, DO NOT EDIT.
+
+package {{ .Package }}
+
+import (
+	"context"
+
+	"github.com/robinbraemer/dataloaders"
+	"github.com/robinbraemer/dataloaders/dataloadersgqlgen"
+{{- range .Imports }}
+	{{ printf "%q" . }}
+{{- end }}
+)
+{{ range .Objects }}
+// Load{{ .Name }} loads the {{ .Name }} at key by attribute using the loaders carried by ctx.
+func Load{{ .Name }}(ctx context.Context, attribute dataloaders.Attribute, key dataloaders.Key) ({{ .Type }}, error) {
+	return dataloadersgqlgen.Load[{{ .Type }}](ctx, attribute, key)
+}
+
+// LoadAll{{ .Name }} loads the {{ .Name }}s at keys by attribute using the loaders carried by ctx.
+func LoadAll{{ .Name }}(ctx context.Context, attribute dataloaders.Attribute, keys []dataloaders.Key) ([]{{ .Type }}, []error) {
+	return dataloadersgqlgen.LoadAll[{{ .Type }}](ctx, attribute, keys)
+}
+{{ end }}`))
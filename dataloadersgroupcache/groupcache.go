@@ -0,0 +1,65 @@
+// Package dataloadersgroupcache connects DataLoaders with groupcache.
+//
+// A groupcache group can't be used as DataLoader cache, since values can't be set or deleted.
+// Instead the DataLoaders of all instances fetch through the group (see Fetcher),
+// which loads every key once on its owner using singleflight, caches it there
+// and replicates hot keys. The owner loads its keys through a DataLoader (see Getter),
+// batching the keys loaded by groupcache concurrently into a single fetch:
+//
+// 	var group *groupcache.Group
+// 	owner := dataloaders.NewTyped(fetchUsers)
+// 	group = groupcache.NewGroup("users", 64<<20, dataloadersgroupcache.Getter(owner, codec))
+// 	users := dataloaders.NewTyped(dataloadersgroupcache.Fetcher[string](group, codec))
+package dataloadersgroupcache
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/golang/groupcache"
+	"github.com/robinbraemer/dataloaders"
+)
+
+// Fetcher returns a fetcher getting the keys, formatted with fmt.Sprint, from the group concurrently
+// and decoding the values with codec.
+func Fetcher[K comparable, V any](group *groupcache.Group, codec dataloaders.Codec[V]) dataloaders.TypedFetcher[K, V] {
+	return func(ctx context.Context, keys []K) ([]V, []error) {
+		values := make([]V, len(keys))
+		errs := make([]error, len(keys))
+		var wg sync.WaitGroup
+		for i, key := range keys {
+			wg.Add(1)
+			go func(i int, key K) {
+				defer wg.Done()
+				var data []byte
+				if err := group.Get(ctx, fmt.Sprint(key), groupcache.AllocatingByteSliceSink(&data)); err != nil {
+					errs[i] = err
+					return
+				}
+				if values[i], errs[i] = codec.Unmarshal(data); errs[i] != nil {
+					errs[i] = fmt.Errorf("decoding value at key %v: %w", key, errs[i])
+				}
+			}(i, key)
+		}
+		wg.Wait()
+		return values, errs
+	}
+}
+
+// Getter returns a groupcache getter loading the keys with the DataLoader
+// and encoding the values with codec.
+// The DataLoader should not cache the values for long, groupcache caches them instead.
+func Getter[V any](l *dataloaders.TypedDataLoader[string, V], codec dataloaders.Codec[V]) groupcache.Getter {
+	return groupcache.GetterFunc(func(ctx context.Context, key string, dest groupcache.Sink) error {
+		value, err := l.Load(ctx, key)
+		if err != nil {
+			return err
+		}
+		data, err := codec.Marshal(value)
+		if err != nil {
+			return fmt.Errorf("encoding value at key %s: %w", key, err)
+		}
+		return dest.SetBytes(data)
+	})
+}
@@ -0,0 +1,35 @@
+package dataloadersgroupcache
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/golang/groupcache"
+	"github.com/robinbraemer/dataloaders"
+)
+
+func TestFetcherAndGetter(t *testing.T) {
+	codec := dataloaders.JSONCodec[string]()
+	var fetched []string
+	owner := dataloaders.NewTyped(func(ctx context.Context, keys []string) ([]string, []error) {
+		fetched = append(fetched, keys...)
+		values := make([]string, len(keys))
+		for i, key := range keys {
+			values[i] = "user " + key
+		}
+		return values, nil
+	}, dataloaders.WithNoCache())
+	group := groupcache.NewGroup("users", 1<<20, Getter(owner, codec))
+	users := dataloaders.NewTyped(Fetcher[int](group, codec), dataloaders.WithSynchronous())
+	ctx := context.Background()
+	values, errs := users.LoadAll(ctx, []int{1, 2})
+	if fmt.Sprint(values) != "[user 1 user 2]" || errs[0] != nil || errs[1] != nil {
+		t.Fatalf("got %v, %v", values, errs)
+	}
+	// groupcache caches the values for other loaders
+	dataloaders.NewTyped(Fetcher[int](group, codec)).Load(ctx, 1)
+	if len(fetched) != 2 {
+		t.Fatalf("fetched %v, want every key once", fetched)
+	}
+}
@@ -0,0 +1,73 @@
+package dataloadersgrpc
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/robinbraemer/dataloaders"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Fetcher returns a fetcher calling a batch RPC with the keys of a batch,
+// e.g. a function building the request of the keys and calling client.GetUsers.
+// The response is mapped to the values and errors of the keys by decode,
+// keys missing in both maps get dataloaders.ErrNotFound (see dataloaders.WithNotFound),
+// as do keys with an error of code NotFound.
+// The RPC gets the latest deadline of the contexts waiting for the batch, if all have one.
+func Fetcher[K comparable, V any, Resp any](
+	call func(ctx context.Context, keys []K, opts ...grpc.CallOption) (Resp, error),
+	decode func(resp Resp) (values map[K]V, errs map[K]error),
+	opts ...grpc.CallOption,
+) dataloaders.TypedFetcher[K, V] {
+	return func(ctx context.Context, keys []K) ([]V, []error) {
+		if deadline, ok := latestDeadline(dataloaders.CallerContexts(ctx)); ok {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithDeadline(ctx, deadline)
+			defer cancel()
+		}
+		resp, err := call(ctx, keys, opts...)
+		if err != nil {
+			return nil, []error{err}
+		}
+		m, errm := decode(resp)
+		values := make([]V, len(keys))
+		var errs []error
+		for i, key := range keys {
+			v, ok := m[key]
+			err, failed := errm[key]
+			switch {
+			case failed && status.Code(err) == codes.NotFound:
+				err = fmt.Errorf("%w: %w", dataloaders.ErrNotFound, err)
+			case failed:
+			case ok:
+				values[i] = v
+				continue
+			default:
+				err = dataloaders.ErrNotFound
+			}
+			if errs == nil {
+				errs = make([]error, len(keys))
+			}
+			errs[i] = err
+		}
+		return values, errs
+	}
+}
+
+// latestDeadline returns the latest deadline of the contexts and false if any has none.
+func latestDeadline(ctxs []context.Context) (time.Time, bool) {
+	var latest time.Time
+	for _, ctx := range ctxs {
+		deadline, ok := ctx.Deadline()
+		if !ok {
+			return time.Time{}, false
+		}
+		if deadline.After(latest) {
+			latest = deadline
+		}
+	}
+	return latest, len(ctxs) != 0
+}
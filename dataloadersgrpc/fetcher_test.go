@@ -0,0 +1,61 @@
+package dataloadersgrpc
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/robinbraemer/dataloaders"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// usersResponse is the response of a batch RPC.
+type usersResponse struct {
+	users  map[int]string
+	errors map[int]error
+}
+
+func TestFetcher(t *testing.T) {
+	errInvalid := status.Error(codes.InvalidArgument, "invalid")
+	var deadline time.Time
+	call := func(ctx context.Context, keys []int, opts ...grpc.CallOption) (usersResponse, error) {
+		deadline, _ = ctx.Deadline()
+		return usersResponse{
+			users:  map[int]string{1: "a"},
+			errors: map[int]error{2: status.Error(codes.NotFound, "gone"), 3: errInvalid},
+		}, nil
+	}
+	decode := func(resp usersResponse) (map[int]string, map[int]error) {
+		return resp.users, resp.errors
+	}
+	l := dataloaders.NewTyped(Fetcher(call, decode), dataloaders.WithSynchronous(), dataloaders.WithNotFound(dataloaders.NotFoundError))
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	values, errs := l.LoadAll(ctx, []int{1, 2, 3, 4})
+	if values[0] != "a" || errs[0] != nil {
+		t.Fatalf("got %q, %v", values[0], errs[0])
+	}
+	// keys with code NotFound or missing in the response are not found
+	if !errors.Is(errs[1], dataloaders.ErrNotFound) || !errors.Is(errs[3], dataloaders.ErrNotFound) || errs[2] != errInvalid {
+		t.Fatalf("got errors %v", errs)
+	}
+	if want, _ := ctx.Deadline(); !deadline.Equal(want) {
+		t.Fatalf("called with deadline %v, want the one of the caller %v", deadline, want)
+	}
+}
+
+func TestFetcherCallError(t *testing.T) {
+	errUnavailable := status.Error(codes.Unavailable, "unavailable")
+	fetch := Fetcher(func(ctx context.Context, keys []int, opts ...grpc.CallOption) (usersResponse, error) {
+		return usersResponse{}, errUnavailable
+	}, func(resp usersResponse) (map[int]string, map[int]error) {
+		return resp.users, resp.errors
+	})
+	// a failed call fails the whole batch
+	if _, errs := fetch(context.Background(), []int{1, 2}); len(errs) != 1 || errs[0] != errUnavailable {
+		t.Fatalf("got %v", errs)
+	}
+}
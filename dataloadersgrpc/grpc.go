@@ -0,0 +1,44 @@
+// Package dataloadersgrpc provides gRPC server interceptors carrying request-scoped DataLoaders
+// and fetchers calling batch RPCs.
+package dataloadersgrpc
+
+import (
+	"context"
+
+	"github.com/robinbraemer/dataloaders"
+	"google.golang.org/grpc"
+)
+
+// UnaryServerInterceptor carries a new ObjAttrDataLoader created by the factory
+// in the context of every unary call (see dataloaders.FromContext).
+// The loader is closed once the handler returned, waiting for its batches still being fetched.
+func UnaryServerInterceptor(factory dataloaders.Factory) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		l := factory()
+		defer l.Close(context.WithoutCancel(ctx))
+		return handler(dataloaders.NewContext(ctx, l), req)
+	}
+}
+
+// StreamServerInterceptor carries a new ObjAttrDataLoader created by the factory
+// in the context of every stream, see UnaryServerInterceptor.
+func StreamServerInterceptor(factory dataloaders.Factory) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		l := factory()
+		defer l.Close(context.WithoutCancel(ss.Context()))
+		return handler(srv, &serverStream{
+			ServerStream: ss,
+			ctx:          dataloaders.NewContext(ss.Context(), l),
+		})
+	}
+}
+
+// serverStream is a grpc.ServerStream with another context.
+type serverStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *serverStream) Context() context.Context {
+	return s.ctx
+}
@@ -0,0 +1,62 @@
+package dataloadersgrpc
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/robinbraemer/dataloaders"
+	"google.golang.org/grpc"
+)
+
+func newFactory() dataloaders.Factory {
+	return dataloaders.NewRegistryBuilder().Object("user").Attr("id", func(ctx context.Context, keys []dataloaders.Key) ([]dataloaders.Value, []error) {
+		return make([]dataloaders.Value, len(keys)), nil
+	}).Build
+}
+
+// loadAfterCall returns the error of loading from the loader of a finished call.
+func loadAfterCall(l *dataloaders.ObjAttrDataLoader) error {
+	_, err := l.Load(context.Background(), "user", "id", 1)
+	return err
+}
+
+func TestUnaryServerInterceptor(t *testing.T) {
+	var l *dataloaders.ObjAttrDataLoader
+	_, err := UnaryServerInterceptor(newFactory())(context.Background(), nil, &grpc.UnaryServerInfo{},
+		func(ctx context.Context, req interface{}) (interface{}, error) {
+			l, _ = dataloaders.FromContext(ctx)
+			return nil, nil
+		})
+	if err != nil || l == nil {
+		t.Fatalf("got %v, want a loader in the call context", err)
+	}
+	if err := loadAfterCall(l); !errors.Is(err, dataloaders.ErrClosed) {
+		t.Fatalf("got %v, want %v after the call", err, dataloaders.ErrClosed)
+	}
+}
+
+// contextStream is a grpc.ServerStream of a context.
+type contextStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *contextStream) Context() context.Context {
+	return s.ctx
+}
+
+func TestStreamServerInterceptor(t *testing.T) {
+	var l *dataloaders.ObjAttrDataLoader
+	err := StreamServerInterceptor(newFactory())(nil, &contextStream{ctx: context.Background()}, &grpc.StreamServerInfo{},
+		func(srv interface{}, ss grpc.ServerStream) error {
+			l, _ = dataloaders.FromContext(ss.Context())
+			return nil
+		})
+	if err != nil || l == nil {
+		t.Fatalf("got %v, want a loader in the stream context", err)
+	}
+	if err := loadAfterCall(l); !errors.Is(err, dataloaders.ErrClosed) {
+		t.Fatalf("got %v, want %v after the stream", err, dataloaders.ErrClosed)
+	}
+}
@@ -0,0 +1,63 @@
+// Package dataloadersmongo builds fetchers finding the documents of a batch of keys
+// with a single $in query using the MongoDB Go driver.
+// Documents are mapped back to their keys, keys without document get dataloaders.ErrNotFound (see dataloaders.WithNotFound).
+package dataloadersmongo
+
+import (
+	"context"
+
+	"github.com/robinbraemer/dataloaders"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+// Fetcher returns a fetcher finding the documents of coll whose field is in the keys of a batch
+// and matching filter, may be nil. Every document is decoded into a value and mapped to its key by key.
+func Fetcher[K comparable, V any](coll *mongo.Collection, field string, filter bson.D, key func(value V) K, opts ...options.Lister[options.FindOptions]) dataloaders.TypedFetcher[K, V] {
+	return dataloaders.TypedMapFetcher[K, V](func(ctx context.Context, keys []K) (map[K]V, error) {
+		values, err := find[K, V](ctx, coll, field, filter, keys, opts)
+		if err != nil {
+			return nil, err
+		}
+		m := make(map[K]V, len(values))
+		for _, v := range values {
+			m[key(v)] = v
+		}
+		return m, nil
+	}).Fetcher()
+}
+
+// GroupFetcher is like Fetcher, but groups the documents by key, see dataloaders.GroupLoader.
+func GroupFetcher[K comparable, V any](coll *mongo.Collection, field string, filter bson.D, key func(value V) K, opts ...options.Lister[options.FindOptions]) dataloaders.TypedFetcher[K, []V] {
+	return dataloaders.TypedGroupFetcher[K, V](func(ctx context.Context, keys []K) (map[K][]V, error) {
+		values, err := find[K, V](ctx, coll, field, filter, keys, opts)
+		if err != nil {
+			return nil, err
+		}
+		m := make(map[K][]V, len(keys))
+		for _, v := range values {
+			k := key(v)
+			m[k] = append(m[k], v)
+		}
+		return m, nil
+	}).Fetcher()
+}
+
+// find finds and decodes the documents of the keys.
+func find[K comparable, V any](ctx context.Context, coll *mongo.Collection, field string, filter bson.D, keys []K, opts []options.Lister[options.FindOptions]) ([]V, error) {
+	cursor, err := coll.Find(ctx, inFilter(field, filter, keys), opts...)
+	if err != nil {
+		return nil, err
+	}
+	var values []V
+	err = cursor.All(ctx, &values)
+	return values, err
+}
+
+// inFilter returns the filter of the documents whose field is one of the keys and matching filter.
+func inFilter[K comparable](field string, filter bson.D, keys []K) bson.D {
+	query := make(bson.D, 0, len(filter)+1)
+	query = append(query, bson.E{Key: field, Value: bson.D{{Key: "$in", Value: keys}}})
+	return append(query, filter...)
+}
@@ -0,0 +1,24 @@
+package dataloadersmongo
+
+import (
+	"reflect"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+func TestInFilter(t *testing.T) {
+	filter := bson.D{{Key: "deleted", Value: false}}
+	got := inFilter("_id", filter, []int{1, 2})
+	want := bson.D{
+		{Key: "_id", Value: bson.D{{Key: "$in", Value: []int{1, 2}}}},
+		{Key: "deleted", Value: false},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+	// the filter passed in is not changed
+	if len(filter) != 1 {
+		t.Fatalf("filter changed to %v", filter)
+	}
+}
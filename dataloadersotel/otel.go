@@ -0,0 +1,67 @@
+// Package dataloadersotel traces the batch fetches of DataLoaders with OpenTelemetry.
+package dataloadersotel
+
+import (
+	"context"
+
+	"github.com/robinbraemer/dataloaders"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// The name of the default tracer.
+const instrumentationName = "github.com/robinbraemer/dataloaders/dataloadersotel"
+
+// Tracer returns the tracer of the global tracer provider.
+func Tracer() trace.Tracer {
+	return otel.Tracer(instrumentationName)
+}
+
+// WrapFetcher returns a fetcher starting a span named spanName for every batch fetch.
+// The span is a child of the span of the first caller's context, links the spans of
+// all other waiting callers and records the number of keys and the fetch errors.
+func WrapFetcher[K comparable, V any](tracer trace.Tracer, spanName string, fetch dataloaders.TypedFetcher[K, V]) dataloaders.TypedFetcher[K, V] {
+	return func(ctx context.Context, keys []K) ([]V, []error) {
+		ctx, span := tracer.Start(ctx, spanName,
+			trace.WithSpanKind(trace.SpanKindInternal),
+			trace.WithLinks(callerLinks(ctx)...),
+			trace.WithAttributes(attribute.Int("dataloader.keys", len(keys))),
+		)
+		defer span.End()
+
+		values, errs := fetch(ctx, keys)
+
+		var failed int
+		for _, err := range errs {
+			if err != nil {
+				if failed == 0 {
+					span.RecordError(err)
+					span.SetStatus(codes.Error, err.Error())
+				}
+				failed++
+			}
+		}
+		if failed != 0 {
+			span.SetAttributes(attribute.Int("dataloader.errors", failed))
+		}
+		return values, errs
+	}
+}
+
+// callerLinks returns a link to the span of every caller waiting for the batch.
+func callerLinks(ctx context.Context) []trace.Link {
+	parent := trace.SpanContextFromContext(ctx).SpanID()
+	seen := map[trace.SpanID]bool{parent: true}
+	var links []trace.Link
+	for _, caller := range dataloaders.CallerContexts(ctx) {
+		sc := trace.SpanContextFromContext(caller)
+		if !sc.IsValid() || seen[sc.SpanID()] {
+			continue
+		}
+		seen[sc.SpanID()] = true
+		links = append(links, trace.Link{SpanContext: sc})
+	}
+	return links
+}
@@ -0,0 +1,47 @@
+package dataloadersotel
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/robinbraemer/dataloaders"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestWrapFetcher(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	tracer := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)).Tracer("test")
+	l := dataloaders.NewTyped(WrapFetcher(tracer, "users", func(ctx context.Context, keys []int) ([]int, []error) {
+		return keys, []error{nil, errors.New("not found")}
+	}), dataloaders.WithSynchronous())
+
+	ctx, parent := tracer.Start(context.Background(), "request")
+	l.LoadAll(ctx, []int{1, 2})
+	parent.End()
+
+	spans := recorder.Ended()
+	if len(spans) != 2 {
+		t.Fatalf("recorded %d spans, want 2", len(spans))
+	}
+	span := spans[0]
+	if span.Name() != "users" || span.Parent().SpanID() != parent.SpanContext().SpanID() {
+		t.Fatalf("span %q with parent %v, want users with parent %v", span.Name(), span.Parent().SpanID(), parent.SpanContext().SpanID())
+	}
+	if span.Status().Code != codes.Error {
+		t.Fatalf("span status %v, want error", span.Status().Code)
+	}
+	want := map[attribute.Key]int64{"dataloader.keys": 2, "dataloader.errors": 1}
+	for _, a := range span.Attributes() {
+		if n, ok := want[a.Key]; ok && a.Value.AsInt64() != n {
+			t.Fatalf("attribute %s is %d, want %d", a.Key, a.Value.AsInt64(), n)
+		}
+		delete(want, a.Key)
+	}
+	if len(want) != 0 {
+		t.Fatalf("missing attributes %v", want)
+	}
+}
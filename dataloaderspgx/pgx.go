@@ -0,0 +1,60 @@
+// Package dataloaderspgx builds fetchers from pgx queries selecting the rows of a batch of keys
+// with an IN clause, e.g. `SELECT id, name FROM users WHERE id IN ({keys})`.
+// Rows are mapped back to their keys, keys without rows get dataloaders.ErrNotFound (see dataloaders.WithNotFound).
+package dataloaderspgx
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/robinbraemer/dataloaders"
+	"github.com/robinbraemer/dataloaders/dataloaderssql"
+)
+
+// Querier is implemented by *pgx.Conn, pgx.Tx and *pgxpool.Pool.
+type Querier interface {
+	Query(ctx context.Context, sql string, args ...interface{}) (pgx.Rows, error)
+}
+
+// Fetcher returns a fetcher querying the rows of the keys of a batch with query,
+// whose dataloaderssql.Keys are expanded after args. Every row is scanned by scan,
+// e.g. pgx.RowToStructByName[V], and mapped to its key by key.
+func Fetcher[K comparable, V any](db Querier, query string, scan pgx.RowToFunc[V], key func(value V) K, args ...interface{}) dataloaders.TypedFetcher[K, V] {
+	return dataloaders.TypedMapFetcher[K, V](func(ctx context.Context, keys []K) (map[K]V, error) {
+		values, err := queryRows(ctx, db, query, scan, args, keys)
+		if err != nil {
+			return nil, err
+		}
+		m := make(map[K]V, len(values))
+		for _, v := range values {
+			m[key(v)] = v
+		}
+		return m, nil
+	}).Fetcher()
+}
+
+// GroupFetcher is like Fetcher, but groups the rows by key, see dataloaders.GroupLoader.
+func GroupFetcher[K comparable, V any](db Querier, query string, scan pgx.RowToFunc[V], key func(value V) K, args ...interface{}) dataloaders.TypedFetcher[K, []V] {
+	return dataloaders.TypedGroupFetcher[K, V](func(ctx context.Context, keys []K) (map[K][]V, error) {
+		values, err := queryRows(ctx, db, query, scan, args, keys)
+		if err != nil {
+			return nil, err
+		}
+		m := make(map[K][]V, len(keys))
+		for _, v := range values {
+			k := key(v)
+			m[k] = append(m[k], v)
+		}
+		return m, nil
+	}).Fetcher()
+}
+
+// queryRows queries and scans the rows of the keys.
+func queryRows[K comparable, V any](ctx context.Context, db Querier, query string, scan pgx.RowToFunc[V], args []interface{}, keys []K) ([]V, error) {
+	query = dataloaderssql.Expand(query, dataloaderssql.Dollar, len(args), len(keys))
+	rows, err := db.Query(ctx, query, dataloaderssql.Args(args, keys)...)
+	if err != nil {
+		return nil, err
+	}
+	return pgx.CollectRows(rows, scan)
+}
@@ -0,0 +1,68 @@
+package dataloaderspgx
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/robinbraemer/dataloaders"
+)
+
+// evenQuerier answers every query with one row per even argument, recording the queries.
+type evenQuerier struct {
+	queries []string
+}
+
+func (q *evenQuerier) Query(ctx context.Context, sql string, args ...interface{}) (pgx.Rows, error) {
+	q.queries = append(q.queries, sql)
+	var ids []int
+	for _, arg := range args {
+		if id := arg.(int); id%2 == 0 {
+			ids = append(ids, id)
+		}
+	}
+	return &idRows{ids: ids, pos: -1}, nil
+}
+
+// idRows are rows of an id column.
+type idRows struct {
+	ids []int
+	pos int
+}
+
+func (r *idRows) Close()                        {}
+func (r *idRows) Err() error                    { return nil }
+func (r *idRows) CommandTag() pgconn.CommandTag { return pgconn.CommandTag{} }
+func (r *idRows) FieldDescriptions() []pgconn.FieldDescription {
+	return []pgconn.FieldDescription{{Name: "id"}}
+}
+func (r *idRows) Values() ([]any, error) { return []any{r.ids[r.pos]}, nil }
+func (r *idRows) RawValues() [][]byte    { return nil }
+func (r *idRows) Conn() *pgx.Conn        { return nil }
+func (r *idRows) TypeMap() *pgtype.Map   { return pgtype.NewMap() }
+
+func (r *idRows) Next() bool {
+	r.pos++
+	return r.pos < len(r.ids)
+}
+
+func (r *idRows) Scan(dest ...any) error {
+	*dest[0].(*int) = r.ids[r.pos]
+	return nil
+}
+
+func TestFetcher(t *testing.T) {
+	q := &evenQuerier{}
+	l := dataloaders.NewTyped(Fetcher(q, "SELECT id FROM users WHERE org = $1 AND id IN ({keys})", pgx.RowTo[int], func(id int) int { return id }, 7),
+		dataloaders.WithSynchronous(), dataloaders.WithNotFound(dataloaders.NotFoundError))
+	values, errs := l.LoadAll(context.Background(), []int{1, 2, 4})
+	if values[1] != 2 || values[2] != 4 || !errors.Is(errs[0], dataloaders.ErrNotFound) || errs[1] != nil || errs[2] != nil {
+		t.Fatalf("got %v, %v", values, errs)
+	}
+	if len(q.queries) != 1 || q.queries[0] != "SELECT id FROM users WHERE org = $1 AND id IN ($2, $3, $4)" {
+		t.Fatalf("queried %q, want one query of all keys", q.queries)
+	}
+}
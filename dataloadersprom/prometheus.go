@@ -0,0 +1,129 @@
+// Package dataloadersprom exports the metrics of DataLoaders to Prometheus
+// using the DataLoader hooks.
+package dataloadersprom
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/robinbraemer/dataloaders"
+)
+
+// The labels of all metrics.
+var labels = []string{"object_type", "attribute"}
+
+// Collector holds the metrics of DataLoaders labeled by object type and attribute.
+// Register it using prometheus.MustRegister(c).
+type Collector struct {
+	hits          *prometheus.CounterVec
+	misses        *prometheus.CounterVec
+	fetchErrors   *prometheus.CounterVec
+	batchSize     *prometheus.HistogramVec
+	fetchDuration *prometheus.HistogramVec
+}
+
+var _ prometheus.Collector = (*Collector)(nil)
+
+// NewCollector creates the metrics prefixed with namespace.
+func NewCollector(namespace string) *Collector {
+	return &Collector{
+		hits: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "dataloader",
+			Name:      "cache_hits_total",
+			Help:      "Number of loaded keys found in the cache.",
+		}, labels),
+		misses: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "dataloader",
+			Name:      "cache_misses_total",
+			Help:      "Number of loaded keys not found in the cache.",
+		}, labels),
+		fetchErrors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "dataloader",
+			Name:      "fetch_errors_total",
+			Help:      "Number of batches the fetcher returned any error for.",
+		}, labels),
+		batchSize: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: "dataloader",
+			Name:      "batch_size",
+			Help:      "Number of keys per batch sent to the fetcher.",
+			Buckets:   prometheus.ExponentialBuckets(1, 2, 12),
+		}, labels),
+		fetchDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: "dataloader",
+			Name:      "fetch_duration_seconds",
+			Help:      "Duration of batch fetches.",
+			Buckets:   prometheus.DefBuckets,
+		}, labels),
+	}
+}
+
+// Describe implements prometheus.Collector.
+func (c *Collector) Describe(ch chan<- *prometheus.Desc) {
+	c.hits.Describe(ch)
+	c.misses.Describe(ch)
+	c.fetchErrors.Describe(ch)
+	c.batchSize.Describe(ch)
+	c.fetchDuration.Describe(ch)
+}
+
+// Collect implements prometheus.Collector.
+func (c *Collector) Collect(ch chan<- prometheus.Metric) {
+	c.hits.Collect(ch)
+	c.misses.Collect(ch)
+	c.fetchErrors.Collect(ch)
+	c.batchSize.Collect(ch)
+	c.fetchDuration.Collect(ch)
+}
+
+// Hooks returns the hooks recording the metrics of an untyped DataLoader
+// with the given label values. Use empty strings for unused labels.
+func (c *Collector) Hooks(objectType, attribute string) dataloaders.Hooks {
+	return NewHooks[dataloaders.Key, dataloaders.Value](c, objectType, attribute)
+}
+
+// NewHooks returns the hooks recording the metrics of a typed DataLoader
+// with the given label values.
+func NewHooks[K comparable, V any](c *Collector, objectType, attribute string) dataloaders.TypedHooks[K, V] {
+	hits := c.hits.WithLabelValues(objectType, attribute)
+	misses := c.misses.WithLabelValues(objectType, attribute)
+	fetchErrors := c.fetchErrors.WithLabelValues(objectType, attribute)
+	batchSize := c.batchSize.WithLabelValues(objectType, attribute)
+	fetchDuration := c.fetchDuration.WithLabelValues(objectType, attribute)
+	return dataloaders.TypedHooks[K, V]{
+		OnCacheHit: func(context.Context, K) {
+			hits.Inc()
+		},
+		OnCacheMiss: func(context.Context, K) {
+			misses.Inc()
+		},
+		OnBatchDispatch: func(_ context.Context, keys []K) {
+			batchSize.Observe(float64(len(keys)))
+		},
+		OnBatchDone: func(_ context.Context, _ []K, d time.Duration, _ []error) {
+			fetchDuration.Observe(d.Seconds())
+		},
+		OnFetchError: func(context.Context, []K, []error) {
+			fetchErrors.Inc()
+		},
+	}
+}
+
+// InstrumentAttrInits wraps the initializers of an AttrDataLoader
+// so every created DataLoader records its metrics labeled with objectType and its attribute.
+func (c *Collector) InstrumentAttrInits(objectType string, inits dataloaders.AttrDataLoaderInits) dataloaders.AttrDataLoaderInits {
+	instrumented := make(dataloaders.AttrDataLoaderInits, len(inits))
+	for attribute, init := range inits {
+		attribute, init := attribute, init
+		instrumented[attribute] = func() *dataloaders.DataLoader {
+			return init().AddHooks(c.Hooks(objectType, fmt.Sprint(attribute)))
+		}
+	}
+	return instrumented
+}
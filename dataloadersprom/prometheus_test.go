@@ -0,0 +1,44 @@
+package dataloadersprom
+
+import (
+	"context"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/robinbraemer/dataloaders"
+)
+
+func TestHooks(t *testing.T) {
+	c := NewCollector("test")
+	l := dataloaders.NewTyped(func(ctx context.Context, keys []int) ([]int, []error) {
+		return keys, nil
+	}, dataloaders.WithSynchronous(), dataloaders.WithHooks(NewHooks[int, int](c, "user", "id")))
+	ctx := context.Background()
+	l.LoadAll(ctx, []int{1, 2})
+	l.Load(ctx, 1)
+
+	if hits := testutil.ToFloat64(c.hits.WithLabelValues("user", "id")); hits != 1 {
+		t.Fatalf("counted %v hits, want 1", hits)
+	}
+	if misses := testutil.ToFloat64(c.misses.WithLabelValues("user", "id")); misses != 2 {
+		t.Fatalf("counted %v misses, want 2", misses)
+	}
+	if n := testutil.CollectAndCount(c, "test_dataloader_batch_size"); n != 1 {
+		t.Fatalf("collected %d batch size series, want 1", n)
+	}
+}
+
+func TestInstrumentAttrInits(t *testing.T) {
+	c := NewCollector("test")
+	l := dataloaders.NewAttrDataLoader(c.InstrumentAttrInits("user", dataloaders.AttrDataLoaderInits{
+		"id": func() *dataloaders.DataLoader {
+			return dataloaders.New(func(ctx context.Context, keys []dataloaders.Key) ([]dataloaders.Value, []error) {
+				return make([]dataloaders.Value, len(keys)), nil
+			}, dataloaders.WithSynchronous())
+		},
+	}), nil)
+	l.Load(context.Background(), "id", 1)
+	if misses := testutil.ToFloat64(c.misses.WithLabelValues("user", "id")); misses != 1 {
+		t.Fatalf("counted %v misses, want 1", misses)
+	}
+}
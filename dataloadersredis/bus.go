@@ -0,0 +1,131 @@
+package dataloadersredis
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/robinbraemer/dataloaders"
+)
+
+// BusOptions configures a Redis invalidation bus.
+type BusOptions struct {
+	// Timeout of a single publish. Defaults to 100 milliseconds.
+	Timeout time.Duration
+	// OnError is called with failed publishes and undecodable messages, may be nil.
+	OnError func(err error)
+}
+
+// InvalidationBus is a dataloaders.InvalidationBus using Redis pub/sub.
+type InvalidationBus struct {
+	client  redis.UniversalClient
+	channel string
+	opts    BusOptions
+	// identifies the messages of this instance, which are not delivered to its handlers
+	instance string
+
+	pubsub *redis.PubSub
+	done   chan struct{}
+
+	mu       sync.Mutex
+	next     int
+	handlers map[int]func(dataloaders.Invalidation)
+}
+
+var _ dataloaders.InvalidationBus = (*InvalidationBus)(nil)
+
+// message is the JSON encoded message published on the channel.
+type message struct {
+	Instance string `json:"instance"`
+	dataloaders.Invalidation
+}
+
+// NewInvalidationBus creates a bus publishing the invalidations on the Redis channel
+// and subscribes to it until Close is called.
+func NewInvalidationBus(client redis.UniversalClient, channel string, opts BusOptions) *InvalidationBus {
+	if opts.Timeout == 0 {
+		opts.Timeout = 100 * time.Millisecond
+	}
+	instance := make([]byte, 16)
+	_, _ = rand.Read(instance)
+	b := &InvalidationBus{
+		client:   client,
+		channel:  channel,
+		opts:     opts,
+		instance: hex.EncodeToString(instance),
+		pubsub:   client.Subscribe(context.Background(), channel),
+		done:     make(chan struct{}),
+		handlers: map[int]func(dataloaders.Invalidation){},
+	}
+	go b.receive()
+	return b
+}
+
+// Publish publishes the invalidation to the other instances.
+func (b *InvalidationBus) Publish(inv dataloaders.Invalidation) {
+	data, err := json.Marshal(message{Instance: b.instance, Invalidation: inv})
+	if err != nil {
+		b.error(fmt.Errorf("encoding invalidation of loader %q: %w", inv.Loader, err))
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), b.opts.Timeout)
+	defer cancel()
+	b.error(b.client.Publish(ctx, b.channel, data).Err())
+}
+
+// Subscribe calls fn with the invalidations published by other instances until unsubscribe is called.
+func (b *InvalidationBus) Subscribe(fn func(inv dataloaders.Invalidation)) (unsubscribe func()) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	id := b.next
+	b.next++
+	b.handlers[id] = fn
+	return func() {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		delete(b.handlers, id)
+	}
+}
+
+// Close unsubscribes from the channel and waits until the last message is handled.
+func (b *InvalidationBus) Close() error {
+	err := b.pubsub.Close()
+	<-b.done
+	return err
+}
+
+// receive delivers the messages of other instances to the handlers until the subscription is closed.
+func (b *InvalidationBus) receive() {
+	defer close(b.done)
+	for msg := range b.pubsub.Channel() {
+		var m message
+		if err := json.Unmarshal([]byte(msg.Payload), &m); err != nil {
+			b.error(fmt.Errorf("decoding invalidation: %w", err))
+			continue
+		}
+		if m.Instance == b.instance {
+			continue
+		}
+		b.mu.Lock()
+		handlers := make([]func(dataloaders.Invalidation), 0, len(b.handlers))
+		for _, fn := range b.handlers {
+			handlers = append(handlers, fn)
+		}
+		b.mu.Unlock()
+		for _, fn := range handlers {
+			fn(m.Invalidation)
+		}
+	}
+}
+
+// error passes a non-nil err to OnError.
+func (b *InvalidationBus) error(err error) {
+	if err != nil && b.opts.OnError != nil {
+		b.opts.OnError(err)
+	}
+}
@@ -0,0 +1,111 @@
+package dataloadersredis
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/robinbraemer/dataloaders"
+)
+
+// waitSubscribed waits until n clients subscribed to the channel.
+func waitSubscribed(t *testing.T, srv *miniredis.Miniredis, channel string, n int) {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for srv.PubSubNumSub(channel)[channel] != n {
+		if time.Now().After(deadline) {
+			t.Fatalf("got %d subscribers, want %d", srv.PubSubNumSub(channel)[channel], n)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestInvalidationBus(t *testing.T) {
+	client, srv := newClient(t)
+	a := NewInvalidationBus(client, "invalidations", BusOptions{})
+	defer a.Close()
+	b := NewInvalidationBus(client, "invalidations", BusOptions{})
+	defer b.Close()
+	waitSubscribed(t, srv, "invalidations", 2)
+
+	own := make(chan dataloaders.Invalidation, 1)
+	a.Subscribe(func(inv dataloaders.Invalidation) { own <- inv })
+	received := make(chan dataloaders.Invalidation, 1)
+	unsubscribe := b.Subscribe(func(inv dataloaders.Invalidation) { received <- inv })
+
+	a.Publish(dataloaders.Invalidation{Loader: "users", Key: []byte("1")})
+	select {
+	case inv := <-received:
+		if inv.Loader != "users" || string(inv.Key) != "1" {
+			t.Fatalf("got %+v, want key 1 of users", inv)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("invalidation not received")
+	}
+
+	unsubscribe()
+	b.Publish(dataloaders.Invalidation{Loader: "users", All: true})
+	select {
+	case inv := <-own:
+		if !inv.All {
+			t.Fatalf("got %+v, want all keys", inv)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("invalidation not received")
+	}
+	// neither delivered to the publishing instance nor to the unsubscribed handler
+	select {
+	case inv := <-own:
+		t.Fatalf("got own invalidation %+v", inv)
+	case inv := <-received:
+		t.Fatalf("got invalidation %+v after unsubscribing", inv)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func TestInvalidationBusClearsLoaders(t *testing.T) {
+	client, srv := newClient(t)
+	var fetches int
+	newLoader := func(bus *InvalidationBus) *dataloaders.TypedDataLoader[int, int] {
+		return dataloaders.NewTyped(func(ctx context.Context, keys []int) ([]int, []error) {
+			fetches++
+			return keys, nil
+		}, dataloaders.WithSynchronous(), dataloaders.WithInvalidation(bus, "users", dataloaders.JSONCodec[int]()))
+	}
+	busA := NewInvalidationBus(client, "invalidations", BusOptions{})
+	defer busA.Close()
+	busB := NewInvalidationBus(client, "invalidations", BusOptions{})
+	defer busB.Close()
+	waitSubscribed(t, srv, "invalidations", 2)
+	a, b := newLoader(busA), newLoader(busB)
+	defer a.Close(context.Background())
+	defer b.Close(context.Background())
+
+	ctx := context.Background()
+	b.Load(ctx, 1)
+	a.Clear(1)
+	// cached until the invalidation arrives
+	for deadline := time.Now().Add(time.Second); fetches < 2 && time.Now().Before(deadline); time.Sleep(time.Millisecond) {
+		if _, err := b.Load(ctx, 1); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if fetches != 2 {
+		t.Fatalf("fetched %d times, want the cleared key fetched again", fetches)
+	}
+}
+
+func TestInvalidationBusDecodingError(t *testing.T) {
+	client, srv := newClient(t)
+	errs := make(chan error, 1)
+	bus := NewInvalidationBus(client, "invalidations", BusOptions{OnError: func(err error) { errs <- err }})
+	defer bus.Close()
+	waitSubscribed(t, srv, "invalidations", 1)
+	srv.Publish("invalidations", "not json")
+	select {
+	case <-errs:
+	case <-time.After(time.Second):
+		t.Fatal("decoding error not reported")
+	}
+}
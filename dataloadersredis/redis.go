@@ -0,0 +1,163 @@
+// Package dataloadersredis provides a cache of DataLoaders stored in Redis,
+// usually used as second level cache shared by many replicas (see dataloaders.WithL2Cache),
+// and an invalidation bus clearing the keys on all replicas (see dataloaders.WithInvalidation).
+package dataloadersredis
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/robinbraemer/dataloaders"
+)
+
+// Options configures a Redis cache.
+type Options struct {
+	// Prefix of the Redis keys, the cached keys are formatted with fmt.Sprint.
+	// Required and unique per loader, e.g. "users:", as Clear deletes all keys with the prefix.
+	Prefix string
+	// TTL lets the values expire in Redis, 0 = never.
+	TTL time.Duration
+	// Timeout of a single Redis command. Defaults to 100 milliseconds.
+	Timeout time.Duration
+	// Timeout of scanning the key space in Clear and Len. Defaults to 10 seconds.
+	ScanTimeout time.Duration
+	// OnError is called with failed Redis commands and codec errors, may be nil.
+	// A failed read is a cache miss.
+	OnError func(err error)
+}
+
+// NewCache creates a cache storing the values encoded by codec in Redis.
+// It panics if opts.Prefix is empty.
+func NewCache[K comparable, V any](client redis.UniversalClient, codec dataloaders.Codec[V], opts Options) dataloaders.TypedCache[K, V] {
+	if opts.Prefix == "" {
+		panic("dataloadersredis: Options.Prefix is required, so the caches of different loaders don't share keys")
+	}
+	if opts.Timeout == 0 {
+		opts.Timeout = 100 * time.Millisecond
+	}
+	if opts.ScanTimeout == 0 {
+		opts.ScanTimeout = 10 * time.Second
+	}
+	return &cache[K, V]{client: client, codec: codec, opts: opts}
+}
+
+type cache[K comparable, V any] struct {
+	client redis.UniversalClient
+	codec  dataloaders.Codec[V]
+	opts   Options
+}
+
+func (c *cache[K, V]) Get(key K) (V, bool) {
+	ctx, cancel := c.context()
+	defer cancel()
+	var zero V
+	data, err := c.client.Get(ctx, c.key(key)).Bytes()
+	if err != nil {
+		if !errors.Is(err, redis.Nil) {
+			c.error(err)
+		}
+		return zero, false
+	}
+	v, err := c.codec.Unmarshal(data)
+	if err != nil {
+		c.error(fmt.Errorf("decoding value at key %v: %w", key, err))
+		return zero, false
+	}
+	return v, true
+}
+
+func (c *cache[K, V]) Set(key K, value V) {
+	data, err := c.codec.Marshal(value)
+	if err != nil {
+		c.error(fmt.Errorf("encoding value at key %v: %w", key, err))
+		return
+	}
+	ctx, cancel := c.context()
+	defer cancel()
+	c.error(c.client.Set(ctx, c.key(key), data, c.opts.TTL).Err())
+}
+
+func (c *cache[K, V]) Delete(key K) {
+	ctx, cancel := c.context()
+	defer cancel()
+	c.error(c.client.Del(ctx, c.key(key)).Err())
+}
+
+// Clear deletes all keys with the prefix, scanning the whole key space.
+// The keys are deleted one by one in a pipeline, as keys of different hash slots
+// can't be deleted by a single command on a cluster node.
+func (c *cache[K, V]) Clear() {
+	c.error(c.scan(func(ctx context.Context, client redis.Cmdable, keys []string) error {
+		pipe := client.Pipeline()
+		for _, key := range keys {
+			pipe.Del(ctx, key)
+		}
+		_, err := pipe.Exec(ctx)
+		return err
+	}))
+}
+
+// Len counts the keys with the prefix, scanning the whole key space.
+func (c *cache[K, V]) Len() int {
+	// the masters of a cluster are scanned concurrently
+	var n atomic.Int64
+	c.error(c.scan(func(ctx context.Context, client redis.Cmdable, keys []string) error {
+		n.Add(int64(len(keys)))
+		return nil
+	}))
+	return int(n.Load())
+}
+
+// scan calls fn with the keys with the prefix of all nodes, bounded by the scan timeout.
+// fn is called concurrently for the masters of a cluster.
+func (c *cache[K, V]) scan(fn func(ctx context.Context, client redis.Cmdable, keys []string) error) error {
+	ctx, cancel := context.WithTimeout(context.Background(), c.opts.ScanTimeout)
+	defer cancel()
+	scanNode := func(ctx context.Context, client redis.Cmdable) error {
+		iter := client.Scan(ctx, 0, c.opts.Prefix+"*", 1000).Iterator()
+		var keys []string
+		for iter.Next(ctx) {
+			keys = append(keys, iter.Val())
+			if len(keys) == 1000 {
+				if err := fn(ctx, client, keys); err != nil {
+					return err
+				}
+				keys = keys[:0]
+			}
+		}
+		if err := iter.Err(); err != nil {
+			return err
+		}
+		if len(keys) == 0 {
+			return nil
+		}
+		return fn(ctx, client, keys)
+	}
+	if cluster, ok := c.client.(*redis.ClusterClient); ok {
+		return cluster.ForEachMaster(ctx, func(ctx context.Context, client *redis.Client) error {
+			return scanNode(ctx, client)
+		})
+	}
+	return scanNode(ctx, c.client)
+}
+
+// key returns the Redis key of key.
+func (c *cache[K, V]) key(key K) string {
+	return c.opts.Prefix + fmt.Sprint(key)
+}
+
+// context returns the context of a single command.
+func (c *cache[K, V]) context() (context.Context, context.CancelFunc) {
+	return context.WithTimeout(context.Background(), c.opts.Timeout)
+}
+
+// error passes a non-nil err to OnError.
+func (c *cache[K, V]) error(err error) {
+	if err != nil && c.opts.OnError != nil {
+		c.opts.OnError(err)
+	}
+}
@@ -0,0 +1,96 @@
+package dataloadersredis
+
+import (
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/robinbraemer/dataloaders"
+)
+
+// newClient returns a client of an in-memory Redis server closed with the test.
+func newClient(t *testing.T) (*redis.Client, *miniredis.Miniredis) {
+	t.Helper()
+	srv := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: srv.Addr()})
+	t.Cleanup(func() { client.Close() })
+	return client, srv
+}
+
+func TestCache(t *testing.T) {
+	client, srv := newClient(t)
+	var errs []error
+	c := NewCache[int, string](client, dataloaders.JSONCodec[string](), Options{
+		Prefix:  "users:",
+		OnError: func(err error) { errs = append(errs, err) },
+	})
+	// a key of another loader
+	srv.Set("posts:1", "post")
+
+	c.Set(1, "a")
+	c.Set(2, "b")
+	if v, ok := c.Get(1); !ok || v != "a" {
+		t.Fatalf("got %q, %v, want a", v, ok)
+	}
+	if v, err := srv.Get("users:1"); err != nil || v != `"a"` {
+		t.Fatalf("stored %q, %v, want the JSON encoded value", v, err)
+	}
+	if n := c.Len(); n != 2 {
+		t.Fatalf("got %d values, want 2", n)
+	}
+
+	c.Delete(1)
+	if _, ok := c.Get(1); ok {
+		t.Fatal("got deleted value")
+	}
+	c.Clear()
+	if n := c.Len(); n != 0 {
+		t.Fatalf("got %d values after Clear, want 0", n)
+	}
+	if !srv.Exists("posts:1") {
+		t.Fatal("Clear deleted a key without the prefix")
+	}
+	if len(errs) != 0 {
+		t.Fatalf("got errors %v", errs)
+	}
+}
+
+func TestCacheTTL(t *testing.T) {
+	client, srv := newClient(t)
+	c := NewCache[int, string](client, dataloaders.JSONCodec[string](), Options{Prefix: "users:", TTL: time.Minute})
+	c.Set(1, "a")
+	srv.FastForward(time.Minute)
+	if _, ok := c.Get(1); ok {
+		t.Fatal("got expired value")
+	}
+}
+
+func TestCacheErrors(t *testing.T) {
+	client, srv := newClient(t)
+	var errs []error
+	c := NewCache[int, string](client, dataloaders.JSONCodec[string](), Options{
+		Prefix:  "users:",
+		OnError: func(err error) { errs = append(errs, err) },
+	})
+	srv.Set("users:1", "not json")
+	if _, ok := c.Get(1); ok || len(errs) != 1 {
+		t.Fatalf("got value %v with errors %v, want a miss reporting the decoding error", ok, errs)
+	}
+
+	srv.Close()
+	// a failed read is a miss
+	if _, ok := c.Get(2); ok || len(errs) != 2 {
+		t.Fatalf("got value %v with errors %v, want a miss reporting the failed command", ok, errs)
+	}
+}
+
+func TestNewCacheRequiresPrefix(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Fatal("no panic without prefix")
+		}
+	}()
+	client, _ := newClient(t)
+	NewCache[int, string](client, dataloaders.JSONCodec[string](), Options{})
+}
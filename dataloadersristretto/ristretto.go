@@ -0,0 +1,72 @@
+// Package dataloadersristretto provides a cache of DataLoaders backed by ristretto,
+// admitting and evicting values by their cost.
+package dataloadersristretto
+
+import (
+	"time"
+
+	"github.com/dgraph-io/ristretto/v2"
+	"github.com/robinbraemer/dataloaders"
+)
+
+// Key is a key type supported by both ristretto and DataLoaders.
+type Key interface {
+	comparable
+	ristretto.Key
+}
+
+// Options configures a ristretto cache.
+type Options[V any] struct {
+	// Cost returns the cost of a value, e.g. its size in bytes.
+	// Defaults to the Cost function of the ristretto config.
+	Cost func(value V) int64
+	// TTL lets the values expire, 0 = never.
+	TTL time.Duration
+	// Wait blocks Set until the value was admitted or rejected, so it can be got immediately.
+	// Ristretto applies sets asynchronously otherwise, which may refetch a key just loaded.
+	Wait bool
+}
+
+// NewCache creates a cache storing the values in the ristretto cache.
+// The ristretto cache may drop values on admission and eviction, they are fetched again when loaded.
+func NewCache[K Key, V any](c *ristretto.Cache[K, V], opts Options[V]) dataloaders.TypedCache[K, V] {
+	return &cache[K, V]{c: c, opts: opts}
+}
+
+type cache[K Key, V any] struct {
+	c    *ristretto.Cache[K, V]
+	opts Options[V]
+}
+
+func (c *cache[K, V]) Get(key K) (V, bool) {
+	return c.c.Get(key)
+}
+
+func (c *cache[K, V]) Set(key K, value V) {
+	// cost 0 lets ristretto calculate the cost with its config
+	var cost int64
+	if c.opts.Cost != nil {
+		cost = c.opts.Cost(value)
+	}
+	if c.c.SetWithTTL(key, value, cost, c.opts.TTL) && c.opts.Wait {
+		c.c.Wait()
+	}
+}
+
+func (c *cache[K, V]) Delete(key K) {
+	c.c.Del(key)
+}
+
+func (c *cache[K, V]) Clear() {
+	c.c.Clear()
+}
+
+// Len counts the cached values, iterating the whole cache.
+func (c *cache[K, V]) Len() int {
+	var n int
+	c.c.IterValues(func(V) bool {
+		n++
+		return false
+	})
+	return n
+}
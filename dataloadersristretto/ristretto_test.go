@@ -0,0 +1,36 @@
+package dataloadersristretto
+
+import (
+	"context"
+	"testing"
+
+	"github.com/dgraph-io/ristretto/v2"
+	"github.com/robinbraemer/dataloaders"
+)
+
+func TestCache(t *testing.T) {
+	rc, err := ristretto.NewCache(&ristretto.Config[int, int]{NumCounters: 100, MaxCost: 10, BufferItems: 64, IgnoreInternalCost: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rc.Close()
+	var fetched int
+	l := dataloaders.NewTyped(func(ctx context.Context, keys []int) ([]int, []error) {
+		fetched += len(keys)
+		return keys, nil
+	}, dataloaders.WithSynchronous(), dataloaders.WithCache[int, int](NewCache(rc, Options[int]{
+		Cost: func(int) int64 { return 1 },
+		Wait: true,
+	})))
+	ctx := context.Background()
+	l.Load(ctx, 1)
+	// the value is admitted before Set returns
+	if v, err := l.Load(ctx, 1); err != nil || v != 1 || fetched != 1 {
+		t.Fatalf("got %d, %v after fetching %d keys, want the cached value", v, err, fetched)
+	}
+	l.Clear(1)
+	l.Load(ctx, 1)
+	if fetched != 2 {
+		t.Fatalf("fetched %d keys, want the cleared key refetched", fetched)
+	}
+}
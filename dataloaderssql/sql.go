@@ -0,0 +1,108 @@
+// Package dataloaderssql builds fetchers from SQL queries selecting the rows of a batch of keys
+// with an IN clause, e.g. `SELECT id, name FROM users WHERE id IN ({keys})`.
+// Rows are mapped back to their keys, keys without rows get dataloaders.ErrNotFound (see dataloaders.WithNotFound).
+// See the packages dataloaderssqlx and dataloaderspgx for sqlx and pgx.
+package dataloaderssql
+
+import (
+	"context"
+	"database/sql"
+	"strconv"
+	"strings"
+
+	"github.com/robinbraemer/dataloaders"
+)
+
+// Keys is replaced in queries by the placeholders of the keys of a batch.
+const Keys = "{keys}"
+
+// Bind returns the placeholder of the nth argument of a query, starting at 1.
+type Bind func(n int) string
+
+// Question binds arguments with ?, e.g. for MySQL and SQLite.
+func Question(int) string {
+	return "?"
+}
+
+// Dollar binds arguments with $1, $2, ..., e.g. for PostgreSQL.
+func Dollar(n int) string {
+	return "$" + strconv.Itoa(n)
+}
+
+// Expand replaces Keys in query by the comma separated placeholders of n keys,
+// which follow the offset arguments placed before them.
+func Expand(query string, bind Bind, offset, n int) string {
+	var b strings.Builder
+	for i := 0; i < n; i++ {
+		if i != 0 {
+			b.WriteString(", ")
+		}
+		b.WriteString(bind(offset + i + 1))
+	}
+	return strings.ReplaceAll(query, Keys, b.String())
+}
+
+// Args returns args followed by the keys as query arguments.
+func Args[K any](args []interface{}, keys []K) []interface{} {
+	all := make([]interface{}, 0, len(args)+len(keys))
+	all = append(all, args...)
+	for _, key := range keys {
+		all = append(all, key)
+	}
+	return all
+}
+
+// Querier is implemented by *sql.DB, *sql.Tx and *sql.Conn.
+type Querier interface {
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+}
+
+// Fetcher returns a fetcher querying the rows of the keys of a batch with query, whose Keys are
+// expanded with bind after args. Every row is scanned by scan and mapped to its key by key.
+func Fetcher[K comparable, V any](db Querier, bind Bind, query string, scan func(rows *sql.Rows) (V, error), key func(value V) K, args ...interface{}) dataloaders.TypedFetcher[K, V] {
+	return dataloaders.TypedMapFetcher[K, V](func(ctx context.Context, keys []K) (map[K]V, error) {
+		values, err := queryRows(ctx, db, bind, query, scan, args, keys)
+		if err != nil {
+			return nil, err
+		}
+		m := make(map[K]V, len(values))
+		for _, v := range values {
+			m[key(v)] = v
+		}
+		return m, nil
+	}).Fetcher()
+}
+
+// GroupFetcher is like Fetcher, but groups the rows by key, see dataloaders.GroupLoader.
+func GroupFetcher[K comparable, V any](db Querier, bind Bind, query string, scan func(rows *sql.Rows) (V, error), key func(value V) K, args ...interface{}) dataloaders.TypedFetcher[K, []V] {
+	return dataloaders.TypedGroupFetcher[K, V](func(ctx context.Context, keys []K) (map[K][]V, error) {
+		values, err := queryRows(ctx, db, bind, query, scan, args, keys)
+		if err != nil {
+			return nil, err
+		}
+		m := make(map[K][]V, len(keys))
+		for _, v := range values {
+			k := key(v)
+			m[k] = append(m[k], v)
+		}
+		return m, nil
+	}).Fetcher()
+}
+
+// queryRows queries and scans the rows of the keys.
+func queryRows[K comparable, V any](ctx context.Context, db Querier, bind Bind, query string, scan func(rows *sql.Rows) (V, error), args []interface{}, keys []K) ([]V, error) {
+	rows, err := db.QueryContext(ctx, Expand(query, bind, len(args), len(keys)), Args(args, keys)...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var values []V
+	for rows.Next() {
+		v, err := scan(rows)
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, v)
+	}
+	return values, rows.Err()
+}
@@ -0,0 +1,129 @@
+package dataloaderssql
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"io"
+	"testing"
+
+	"github.com/robinbraemer/dataloaders"
+)
+
+func TestExpand(t *testing.T) {
+	query := "SELECT id FROM users WHERE org = $1 AND id IN ({keys})"
+	if got := Expand(query, Dollar, 1, 3); got != "SELECT id FROM users WHERE org = $1 AND id IN ($2, $3, $4)" {
+		t.Fatalf("got %s", got)
+	}
+	if got := Expand("id IN ({keys})", Question, 0, 2); got != "id IN (?, ?)" {
+		t.Fatalf("got %s", got)
+	}
+	if got := Args([]interface{}{"org"}, []int{1, 2}); fmt.Sprint(got) != "[org 1 2]" {
+		t.Fatalf("got args %v", got)
+	}
+}
+
+// echoDriver answers every query with one row per even argument, recording the queries.
+type echoDriver struct {
+	queries []string
+}
+
+func (d *echoDriver) Open(string) (driver.Conn, error) {
+	return &echoConn{d: d}, nil
+}
+
+type echoConn struct {
+	d *echoDriver
+}
+
+func (c *echoConn) Prepare(query string) (driver.Stmt, error) {
+	c.d.queries = append(c.d.queries, query)
+	return echoStmt{}, nil
+}
+
+func (c *echoConn) Close() error {
+	return nil
+}
+
+func (c *echoConn) Begin() (driver.Tx, error) {
+	return nil, errors.New("not supported")
+}
+
+type echoStmt struct{}
+
+func (echoStmt) Close() error {
+	return nil
+}
+
+func (echoStmt) NumInput() int {
+	return -1
+}
+
+func (echoStmt) Exec([]driver.Value) (driver.Result, error) {
+	return nil, errors.New("not supported")
+}
+
+func (echoStmt) Query(args []driver.Value) (driver.Rows, error) {
+	var rows []driver.Value
+	for _, arg := range args {
+		if arg.(int64)%2 == 0 {
+			rows = append(rows, arg)
+		}
+	}
+	return &echoRows{rows: rows}, nil
+}
+
+type echoRows struct {
+	rows []driver.Value
+}
+
+func (r *echoRows) Columns() []string {
+	return []string{"id"}
+}
+
+func (r *echoRows) Close() error {
+	return nil
+}
+
+func (r *echoRows) Next(dest []driver.Value) error {
+	if len(r.rows) == 0 {
+		return io.EOF
+	}
+	dest[0], r.rows = r.rows[0], r.rows[1:]
+	return nil
+}
+
+func TestFetcher(t *testing.T) {
+	d := &echoDriver{}
+	db := sql.OpenDB(connector{d})
+	defer db.Close()
+	scan := func(rows *sql.Rows) (int, error) {
+		var id int
+		err := rows.Scan(&id)
+		return id, err
+	}
+	l := dataloaders.NewTyped(Fetcher(db, Question, "SELECT id FROM users WHERE id IN ({keys})", scan, func(id int) int { return id }),
+		dataloaders.WithSynchronous(), dataloaders.WithNotFound(dataloaders.NotFoundError))
+	values, errs := l.LoadAll(context.Background(), []int{1, 2, 4})
+	if values[1] != 2 || values[2] != 4 || !errors.Is(errs[0], dataloaders.ErrNotFound) || errs[1] != nil || errs[2] != nil {
+		t.Fatalf("got %v, %v", values, errs)
+	}
+	if len(d.queries) != 1 || d.queries[0] != "SELECT id FROM users WHERE id IN (?, ?, ?)" {
+		t.Fatalf("queried %q, want one query of all keys", d.queries)
+	}
+}
+
+// connector opens connections of a driver.
+type connector struct {
+	d *echoDriver
+}
+
+func (c connector) Connect(context.Context) (driver.Conn, error) {
+	return c.d.Open("")
+}
+
+func (c connector) Driver() driver.Driver {
+	return c.d
+}
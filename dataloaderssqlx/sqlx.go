@@ -0,0 +1,64 @@
+// Package dataloaderssqlx builds fetchers from sqlx queries selecting the rows of a batch of keys
+// with an IN clause, e.g. `SELECT * FROM users WHERE id IN ({keys})`, scanning the rows into structs.
+// Rows are mapped back to their keys, keys without rows get dataloaders.ErrNotFound (see dataloaders.WithNotFound).
+package dataloaderssqlx
+
+import (
+	"context"
+	"strings"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/robinbraemer/dataloaders"
+	"github.com/robinbraemer/dataloaders/dataloaderssql"
+)
+
+// DB is implemented by *sqlx.DB and *sqlx.Tx.
+type DB interface {
+	sqlx.QueryerContext
+	Rebind(query string) string
+}
+
+// Fetcher returns a fetcher selecting the rows of the keys of a batch with query into values,
+// whose dataloaderssql.Keys are expanded after args in the bind type of db.
+// Every value is mapped to its key by key.
+func Fetcher[K comparable, V any](db DB, query string, key func(value V) K, args ...interface{}) dataloaders.TypedFetcher[K, V] {
+	return dataloaders.TypedMapFetcher[K, V](func(ctx context.Context, keys []K) (map[K]V, error) {
+		values, err := selectRows[K, V](ctx, db, query, args, keys)
+		if err != nil {
+			return nil, err
+		}
+		m := make(map[K]V, len(values))
+		for _, v := range values {
+			m[key(v)] = v
+		}
+		return m, nil
+	}).Fetcher()
+}
+
+// GroupFetcher is like Fetcher, but groups the values by key, see dataloaders.GroupLoader.
+func GroupFetcher[K comparable, V any](db DB, query string, key func(value V) K, args ...interface{}) dataloaders.TypedFetcher[K, []V] {
+	return dataloaders.TypedGroupFetcher[K, V](func(ctx context.Context, keys []K) (map[K][]V, error) {
+		values, err := selectRows[K, V](ctx, db, query, args, keys)
+		if err != nil {
+			return nil, err
+		}
+		m := make(map[K][]V, len(keys))
+		for _, v := range values {
+			k := key(v)
+			m[k] = append(m[k], v)
+		}
+		return m, nil
+	}).Fetcher()
+}
+
+// selectRows selects the rows of the keys into values.
+func selectRows[K comparable, V any](ctx context.Context, db DB, query string, args []interface{}, keys []K) ([]V, error) {
+	query = strings.ReplaceAll(query, dataloaderssql.Keys, "?")
+	query, all, err := sqlx.In(query, append(args[:len(args):len(args)], keys)...)
+	if err != nil {
+		return nil, err
+	}
+	var values []V
+	err = sqlx.SelectContext(ctx, db, &values, db.Rebind(query), all...)
+	return values, err
+}
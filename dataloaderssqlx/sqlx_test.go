@@ -0,0 +1,137 @@
+package dataloaderssqlx
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/robinbraemer/dataloaders"
+)
+
+// postDriver answers every query with two posts of every even argument, recording the queries.
+type postDriver struct {
+	queries []string
+}
+
+func (d *postDriver) Open(string) (driver.Conn, error) {
+	return &postConn{d: d}, nil
+}
+
+func (d *postDriver) Connect(context.Context) (driver.Conn, error) {
+	return d.Open("")
+}
+
+func (d *postDriver) Driver() driver.Driver {
+	return d
+}
+
+type postConn struct {
+	d *postDriver
+}
+
+func (c *postConn) Prepare(query string) (driver.Stmt, error) {
+	c.d.queries = append(c.d.queries, query)
+	return postStmt{}, nil
+}
+
+func (c *postConn) Close() error {
+	return nil
+}
+
+func (c *postConn) Begin() (driver.Tx, error) {
+	return nil, errors.New("not supported")
+}
+
+type postStmt struct{}
+
+func (postStmt) Close() error {
+	return nil
+}
+
+func (postStmt) NumInput() int {
+	return -1
+}
+
+func (postStmt) Exec([]driver.Value) (driver.Result, error) {
+	return nil, errors.New("not supported")
+}
+
+func (postStmt) Query(args []driver.Value) (driver.Rows, error) {
+	var rows [][]driver.Value
+	for _, arg := range args {
+		if arg.(int64)%2 == 0 {
+			rows = append(rows, []driver.Value{arg, int64(1)}, []driver.Value{arg, int64(2)})
+		}
+	}
+	return &postRows{rows: rows}, nil
+}
+
+type postRows struct {
+	rows [][]driver.Value
+}
+
+func (r *postRows) Columns() []string {
+	return []string{"author_id", "n"}
+}
+
+func (r *postRows) Close() error {
+	return nil
+}
+
+func (r *postRows) Next(dest []driver.Value) error {
+	if len(r.rows) == 0 {
+		return io.EOF
+	}
+	copy(dest, r.rows[0])
+	r.rows = r.rows[1:]
+	return nil
+}
+
+type post struct {
+	AuthorID int `db:"author_id"`
+	N        int `db:"n"`
+}
+
+func newDB(d *postDriver) *sqlx.DB {
+	return sqlx.NewDb(sql.OpenDB(d), "postgres")
+}
+
+func TestFetcher(t *testing.T) {
+	d := &postDriver{}
+	db := newDB(d)
+	defer db.Close()
+	l := dataloaders.NewTyped(Fetcher(db, "SELECT * FROM posts WHERE n = ? AND author_id IN ({keys})",
+		func(p post) int { return p.AuthorID }, 2),
+		dataloaders.WithSynchronous(), dataloaders.WithNotFound(dataloaders.NotFoundError))
+	values, errs := l.LoadAll(context.Background(), []int{1, 2, 4})
+	if !errors.Is(errs[0], dataloaders.ErrNotFound) || errs[1] != nil || errs[2] != nil {
+		t.Fatalf("got errors %v, want key 1 not found", errs)
+	}
+	if values[1].AuthorID != 2 || values[2].AuthorID != 4 {
+		t.Fatalf("got %v, want the posts of 2 and 4", values)
+	}
+	if len(d.queries) != 1 || d.queries[0] != "SELECT * FROM posts WHERE n = $1 AND author_id IN ($2, $3, $4)" {
+		t.Fatalf("queried %q, want one query of all keys", d.queries)
+	}
+}
+
+func TestGroupFetcher(t *testing.T) {
+	db := newDB(&postDriver{})
+	defer db.Close()
+	l := dataloaders.NewTyped(GroupFetcher(db, "SELECT * FROM posts WHERE author_id IN ({keys})",
+		func(p post) int { return p.AuthorID }),
+		dataloaders.WithSynchronous())
+	values, errs := l.LoadAll(context.Background(), []int{1, 2})
+	for _, err := range errs {
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	if len(values[0]) != 0 || len(values[1]) != 2 || values[1][0].N != 1 || values[1][1].N != 2 {
+		t.Fatalf("got %v, want no posts of 1 and two of 2", values)
+	}
+}
@@ -0,0 +1,83 @@
+package dataloaderstest
+
+import (
+	"context"
+	"sync"
+
+	"github.com/robinbraemer/dataloaders"
+)
+
+// MockDataLoader is the mock of the untyped DataLoader.
+type MockDataLoader = TypedMockDataLoader[dataloaders.Key, dataloaders.Value]
+
+// TypedMockDataLoader is a DataLoader fetching canned responses per key set with Return and Fail.
+// Keys without response get dataloaders.ErrNotFound (see dataloaders.WithNotFound).
+// Its batches are recorded to assert how keys were batched.
+type TypedMockDataLoader[K comparable, V any] struct {
+	*dataloaders.TypedDataLoader[K, V]
+	*TypedRecordingFetcher[K, V]
+
+	// the canned responses by key
+	values map[K]V
+	errs   map[K]error
+	mu     sync.Mutex
+}
+
+// NewMockDataLoader creates a mock of the untyped DataLoader configured by opts.
+func NewMockDataLoader(opts ...dataloaders.Option) *MockDataLoader {
+	return NewTypedMockDataLoader[dataloaders.Key, dataloaders.Value](opts...)
+}
+
+// NewTypedMockDataLoader creates a mock DataLoader configured by opts.
+func NewTypedMockDataLoader[K comparable, V any](opts ...dataloaders.Option) *TypedMockDataLoader[K, V] {
+	m := &TypedMockDataLoader[K, V]{
+		values: map[K]V{},
+		errs:   map[K]error{},
+	}
+	m.TypedRecordingFetcher = NewTypedRecordingFetcher(m.fetch)
+	m.TypedDataLoader = dataloaders.NewTyped(m.TypedRecordingFetcher.Fetcher(), opts...)
+	return m
+}
+
+// Return sets the value fetched for key, replacing a previous response.
+func (m *TypedMockDataLoader[K, V]) Return(key K, value V) *TypedMockDataLoader[K, V] {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.values[key] = value
+	delete(m.errs, key)
+	return m
+}
+
+// Fail sets the error fetched for key, replacing a previous response.
+func (m *TypedMockDataLoader[K, V]) Fail(key K, err error) *TypedMockDataLoader[K, V] {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.errs[key] = err
+	delete(m.values, key)
+	return m
+}
+
+// fetch returns the canned responses of the keys.
+func (m *TypedMockDataLoader[K, V]) fetch(_ context.Context, keys []K) ([]V, []error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	values := make([]V, len(keys))
+	var errs []error
+	for i, key := range keys {
+		err, failed := m.errs[key]
+		v, ok := m.values[key]
+		switch {
+		case failed:
+		case ok:
+			values[i] = v
+			continue
+		default:
+			err = dataloaders.ErrNotFound
+		}
+		if errs == nil {
+			errs = make([]error, len(keys))
+		}
+		errs[i] = err
+	}
+	return values, errs
+}
@@ -0,0 +1,24 @@
+package dataloaderstest
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/robinbraemer/dataloaders"
+)
+
+func TestMockDataLoader(t *testing.T) {
+	errDown := errors.New("down")
+	m := NewTypedMockDataLoader[int, string](dataloaders.WithSynchronous(), dataloaders.WithNotFound(dataloaders.NotFoundError))
+	m.Return(1, "a").Fail(2, errDown).Fail(3, errDown).Return(3, "c")
+	values, errs := m.LoadAll(context.Background(), []int{1, 2, 3, 4})
+	if values[0] != "a" || errs[0] != nil || errs[1] != errDown || values[2] != "c" || errs[2] != nil {
+		t.Fatalf("got %v, %v", values, errs)
+	}
+	// keys without response are not found
+	if !errors.Is(errs[3], dataloaders.ErrNotFound) {
+		t.Fatalf("got %v, want %v", errs[3], dataloaders.ErrNotFound)
+	}
+	m.AssertBatchedTogether(t, 1, 2, 3, 4)
+}
@@ -0,0 +1,150 @@
+// Package dataloaderstest provides test doubles of DataLoaders and fetchers
+// with assertions on how keys were batched.
+package dataloaderstest
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"testing"
+
+	"github.com/robinbraemer/dataloaders"
+)
+
+// RecordingFetcher is the recording fetcher of the untyped DataLoader.
+type RecordingFetcher = TypedRecordingFetcher[dataloaders.Key, dataloaders.Value]
+
+// TypedRecordingFetcher wraps a fetcher recording the keys of every batch it fetched.
+type TypedRecordingFetcher[K comparable, V any] struct {
+	fetch dataloaders.TypedFetcher[K, V]
+
+	// the keys of the fetched batches in order
+	batches [][]K
+	mu      sync.Mutex
+}
+
+// NewRecordingFetcher records the batches of the untyped fetcher.
+func NewRecordingFetcher(fetch dataloaders.Fetcher) *RecordingFetcher {
+	return NewTypedRecordingFetcher(fetch)
+}
+
+// NewTypedRecordingFetcher records the batches of the fetcher.
+func NewTypedRecordingFetcher[K comparable, V any](fetch dataloaders.TypedFetcher[K, V]) *TypedRecordingFetcher[K, V] {
+	return &TypedRecordingFetcher[K, V]{fetch: fetch}
+}
+
+// Fetcher returns the fetcher to pass to the DataLoader.
+func (r *TypedRecordingFetcher[K, V]) Fetcher() dataloaders.TypedFetcher[K, V] {
+	return func(ctx context.Context, keys []K) ([]V, []error) {
+		r.mu.Lock()
+		r.batches = append(r.batches, slices.Clone(keys))
+		r.mu.Unlock()
+		return r.fetch(ctx, keys)
+	}
+}
+
+// Batches returns the keys of the fetched batches in the order they were fetched.
+func (r *TypedRecordingFetcher[K, V]) Batches() [][]K {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	batches := make([][]K, len(r.batches))
+	for i, keys := range r.batches {
+		batches[i] = slices.Clone(keys)
+	}
+	return batches
+}
+
+// Keys returns the keys of all fetched batches in the order they were fetched.
+func (r *TypedRecordingFetcher[K, V]) Keys() []K {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var keys []K
+	for _, batch := range r.batches {
+		keys = append(keys, batch...)
+	}
+	return keys
+}
+
+// Forget forgets the recorded batches, e.g. between the steps of a test.
+func (r *TypedRecordingFetcher[K, V]) Forget() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.batches = nil
+}
+
+// AssertBatchedTogether asserts that all keys were fetched in the same batch.
+// Returns whether the assertion held.
+func (r *TypedRecordingFetcher[K, V]) AssertBatchedTogether(t testing.TB, keys ...K) bool {
+	t.Helper()
+	batches := r.Batches()
+	for _, batch := range batches {
+		if containsAll(batch, keys) {
+			return true
+		}
+	}
+	t.Errorf("dataloaderstest: keys %v were not fetched in one batch, batches: %v", keys, batches)
+	return false
+}
+
+// AssertBatches asserts that n batches were fetched.
+// Returns whether the assertion held.
+func (r *TypedRecordingFetcher[K, V]) AssertBatches(t testing.TB, n int) bool {
+	t.Helper()
+	if batches := r.Batches(); len(batches) != n {
+		t.Errorf("dataloaderstest: fetched %d batches, want %d, batches: %v", len(batches), n, batches)
+		return false
+	}
+	return true
+}
+
+// AssertFetchedOnce asserts that every key was fetched exactly once.
+// Returns whether the assertion held.
+func (r *TypedRecordingFetcher[K, V]) AssertFetchedOnce(t testing.TB, keys ...K) bool {
+	t.Helper()
+	fetched := r.Keys()
+	ok := true
+	for _, key := range keys {
+		if n := count(fetched, key); n != 1 {
+			t.Errorf("dataloaderstest: key %v fetched %d times, want once", key, n)
+			ok = false
+		}
+	}
+	return ok
+}
+
+// AssertNotFetched asserts that none of the keys was fetched,
+// e.g. because they were cached or primed.
+// Returns whether the assertion held.
+func (r *TypedRecordingFetcher[K, V]) AssertNotFetched(t testing.TB, keys ...K) bool {
+	t.Helper()
+	fetched := r.Keys()
+	ok := true
+	for _, key := range keys {
+		if n := count(fetched, key); n != 0 {
+			t.Errorf("dataloaderstest: key %v fetched %d times, want never", key, n)
+			ok = false
+		}
+	}
+	return ok
+}
+
+// containsAll returns true if batch contains all keys.
+func containsAll[K comparable](batch, keys []K) bool {
+	for _, key := range keys {
+		if !slices.Contains(batch, key) {
+			return false
+		}
+	}
+	return true
+}
+
+// count returns how often key is in keys.
+func count[K comparable](keys []K, key K) int {
+	var n int
+	for _, k := range keys {
+		if k == key {
+			n++
+		}
+	}
+	return n
+}
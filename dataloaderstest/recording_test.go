@@ -0,0 +1,60 @@
+package dataloaderstest
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/robinbraemer/dataloaders"
+)
+
+// failureRecorder is a testing.TB recording the failed assertions.
+type failureRecorder struct {
+	testing.TB
+	failures []string
+}
+
+func (r *failureRecorder) Helper() {}
+
+func (r *failureRecorder) Errorf(format string, args ...interface{}) {
+	r.failures = append(r.failures, fmt.Sprintf(format, args...))
+}
+
+func echo(ctx context.Context, keys []int) ([]int, []error) {
+	return keys, nil
+}
+
+func TestRecordingFetcher(t *testing.T) {
+	r := NewTypedRecordingFetcher(echo)
+	l := dataloaders.NewTyped(r.Fetcher(), dataloaders.WithSynchronous())
+	ctx := context.Background()
+	l.LoadAll(ctx, []int{1, 2})
+	l.Load(ctx, 3)
+	l.Load(ctx, 1)
+	if fmt.Sprint(r.Batches()) != "[[1 2] [3]]" || fmt.Sprint(r.Keys()) != "[1 2 3]" {
+		t.Fatalf("recorded batches %v and keys %v", r.Batches(), r.Keys())
+	}
+	r.AssertBatchedTogether(t, 1, 2)
+	r.AssertBatches(t, 2)
+	r.AssertFetchedOnce(t, 1, 2, 3)
+	r.AssertNotFetched(t, 4)
+
+	r.Forget()
+	if len(r.Batches()) != 0 {
+		t.Fatalf("recorded batches %v after Forget", r.Batches())
+	}
+}
+
+func TestRecordingFetcherFailedAssertions(t *testing.T) {
+	r := NewTypedRecordingFetcher(echo)
+	l := dataloaders.NewTyped(r.Fetcher(), dataloaders.WithSynchronous())
+	l.Load(context.Background(), 1)
+	l.Load(context.Background(), 2)
+	tb := &failureRecorder{TB: t}
+	if r.AssertBatchedTogether(tb, 1, 2) || r.AssertBatches(tb, 1) || r.AssertFetchedOnce(tb, 3) || r.AssertNotFetched(tb, 1) {
+		t.Fatal("failed assertion reported to hold")
+	}
+	if len(tb.failures) != 4 {
+		t.Fatalf("reported failures %q, want 4", tb.failures)
+	}
+}
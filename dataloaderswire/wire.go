@@ -0,0 +1,28 @@
+// Package dataloaderswire provides the per-request loader factory in google/wire injectors,
+// built from the registrations provided by the application:
+//
+// 	func provideRegistrations(db *sql.DB) dataloaderswire.Registrations {
+// 		return dataloaderswire.Registrations{accountRegistration(db), paymentRegistration(db)}
+// 	}
+//
+// 	func initializeServer(db *sql.DB) *Server {
+// 		wire.Build(dataloaderswire.ProviderSet, provideRegistrations, NewServer)
+// 		return nil
+// 	}
+package dataloaderswire
+
+import (
+	"github.com/google/wire"
+	"github.com/robinbraemer/dataloaders"
+)
+
+// Registrations are the registrations of all loaders, provided by the application.
+type Registrations []dataloaders.Registration
+
+// ProviderSet provides the dataloaders.Factory built from the Registrations.
+var ProviderSet = wire.NewSet(NewFactory)
+
+// NewFactory returns a dataloaders.Factory building the loaders added by the registrations.
+func NewFactory(registrations Registrations) dataloaders.Factory {
+	return dataloaders.NewFactory(registrations...)
+}
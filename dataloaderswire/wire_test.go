@@ -0,0 +1,25 @@
+package dataloaderswire
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/robinbraemer/dataloaders"
+)
+
+func TestNewFactory(t *testing.T) {
+	factory := NewFactory(Registrations{func(b *dataloaders.RegistryBuilder) {
+		b.Object("account").Attr("id", func(ctx context.Context, keys []dataloaders.Key) ([]dataloaders.Value, []error) {
+			return make([]dataloaders.Value, len(keys)), nil
+		})
+	}})
+	l := factory()
+	ctx := context.Background()
+	if _, err := l.Load(ctx, "account", "id", 1); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := l.Load(ctx, "payment", "id", 1); !errors.Is(err, &dataloaders.ObjTypeNotRegError{}) {
+		t.Fatalf("got %v, want an ObjTypeNotRegError", err)
+	}
+}
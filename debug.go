@@ -0,0 +1,263 @@
+package dataloaders
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// WithDebug enables the detailed state returned by Dump, including the keys
+// of the batches not yet done, e.g. to find out why keys were not batched together.
+// Without debug Dump only returns the summary of String, so keys don't leak into logs.
+func WithDebug() Option {
+	return func(o *options) {
+		o.debug = true
+	}
+}
+
+func (p NotFoundPolicy) String() string {
+	switch p {
+	case NotFoundNil:
+		return "nil"
+	case NotFoundError:
+		return "error"
+	case NotFoundCache:
+		return "cache"
+	}
+	return fmt.Sprintf("NotFoundPolicy(%d)", int(p))
+}
+
+func (p PendingPolicy) String() string {
+	switch p {
+	case PendingBlock:
+		return "block"
+	case PendingReject:
+		return "reject"
+	case PendingSpill:
+		return "spill"
+	}
+	return fmt.Sprintf("PendingPolicy(%d)", int(p))
+}
+
+// String returns a one line summary of the configuration and state of the DataLoader:
+//
+// 	DataLoader[int, *main.User]{wait: 16ms, maxBatch: 100, cached: 12, batching: 3, fetching: 1}
+func (l *TypedDataLoader[K, V]) String() string {
+	keys, fetching := l.Pending()
+	return fmt.Sprintf("%s{%s, cached: %d, batching: %d, fetching: %d}",
+		l.typeName(), strings.Join(l.config(), ", "), l.cache.Len(), keys, fetching)
+}
+
+// Dump returns a human-readable, multi-line dump of the configuration, cache sizes,
+// keys of the batches not yet done and counters of the DataLoader.
+// Returns String if the DataLoader was not created with WithDebug.
+func (l *TypedDataLoader[K, V]) Dump() string {
+	if !l.debug {
+		return l.String()
+	}
+	var b strings.Builder
+	l.dump(&b, "")
+	return b.String()
+}
+
+// dump writes the dump of the DataLoader with every line prefixed by indent.
+func (l *TypedDataLoader[K, V]) dump(b *strings.Builder, indent string) {
+	l.mu.Lock()
+	negatives := len(l.negatives)
+	var batching []K
+	if l.batch != nil {
+		batching = append(batching, l.batch.keys...)
+	}
+	var fetching [][]K
+	for batch := range l.running {
+		if batch != l.batch {
+			fetching = append(fetching, batch.keys)
+		}
+	}
+	closed := l.closed.Load()
+	l.mu.Unlock()
+	s := l.Stats()
+
+	fmt.Fprintf(b, "%s%s", indent, l.typeName())
+	if closed {
+		b.WriteString(" (closed)")
+	}
+	fmt.Fprintf(b, "\n%s  config: %s\n", indent, strings.Join(l.config(), ", "))
+	fmt.Fprintf(b, "%s  cache: %d values, %d errors\n", indent, l.cache.Len(), negatives)
+	fmt.Fprintf(b, "%s  batching: %d keys %v\n", indent, len(batching), batching)
+	fmt.Fprintf(b, "%s  fetching: %d batches\n", indent, len(fetching))
+	for _, keys := range fetching {
+		fmt.Fprintf(b, "%s    %d keys %v\n", indent, len(keys), keys)
+	}
+	fmt.Fprintf(b, "%s  stats: hits %d, misses %d, primes %d, clears %d, batches %d, avg batch size %.1f, fetch errors %d, hedges %d\n",
+		indent, s.Hits, s.Misses, s.Primes, s.Clears, s.Batches, s.AvgBatchSize, s.FetchErrors, s.Hedges)
+}
+
+// typeName returns the name of the DataLoader including its key and value types.
+func (l *TypedDataLoader[K, V]) typeName() string {
+	return fmt.Sprintf("DataLoader[%s, %s]", reflect.TypeOf((*K)(nil)).Elem(), reflect.TypeOf((*V)(nil)).Elem())
+}
+
+// config returns the batch settings of the DataLoader and the other settings not left at their default.
+func (l *TypedDataLoader[K, V]) config() []string {
+	config := []string{fmt.Sprintf("wait: %s", l.wait), fmt.Sprintf("maxBatch: %d", l.maxBatch)}
+	add := func(format string, args ...interface{}) {
+		config = append(config, fmt.Sprintf(format, args...))
+	}
+	if l.adaptiveWait != nil {
+		add("adaptiveWait: %s-%s", l.adaptiveWait.min, l.adaptiveWait.max)
+	}
+	if l.unbatched {
+		add("unbatched")
+	}
+	if l.synchronous {
+		add("synchronous")
+	}
+	if l.maxPending != 0 {
+		add("maxPending: %d (%s)", l.maxPending, l.pendingPolicy)
+	}
+	if l.fetching != nil {
+		add("maxConcurrent: %d", cap(l.fetching))
+	}
+	if l.strict {
+		add("strict")
+	}
+	if l.notFound != NotFoundNil {
+		add("notFound: %s", l.notFound)
+	}
+	if l.errorTTL != 0 {
+		add("errorTTL: %s", l.errorTTL)
+	}
+	if l.retries != 0 {
+		add("retries: %d", l.retries)
+	}
+	if l.hedgeDelay != 0 {
+		add("hedgeDelay: %s", l.hedgeDelay)
+	}
+	if l.breaker != nil {
+		add("circuitBreaker")
+	}
+	if l.limiter != nil {
+		add("rateLimiter")
+	}
+	if l.swr {
+		add("staleWhileRevalidate")
+	}
+	return config
+}
+
+// String returns a one line summary of the attributes of the AttrDataLoader:
+//
+// 	AttrDataLoader{email (not initialized), id: DataLoader[dataloaders.Key, dataloaders.Value]{...}}
+func (l *AttrDataLoader) String() string {
+	attributes, loaders := l.snapshot()
+	parts := make([]string, len(attributes))
+	for i, attribute := range attributes {
+		if loaders[i] == nil {
+			parts[i] = fmt.Sprintf("%v (not initialized)", attribute)
+		} else {
+			parts[i] = fmt.Sprintf("%v: %s", attribute, loaders[i])
+		}
+	}
+	return fmt.Sprintf("AttrDataLoader{%s}", strings.Join(parts, ", "))
+}
+
+// Dump returns a human-readable dump of the attributes of the AttrDataLoader
+// with the dumps of their initialized loaders, see DataLoader.Dump.
+// Loaders are not initialized by Dump.
+func (l *AttrDataLoader) Dump() string {
+	var b strings.Builder
+	l.dump(&b, "")
+	return b.String()
+}
+
+func (l *AttrDataLoader) dump(b *strings.Builder, indent string) {
+	attributes, loaders := l.snapshot()
+	fmt.Fprintf(b, "%sAttrDataLoader: %d attributes\n", indent, len(attributes))
+	for i, attribute := range attributes {
+		switch loader := loaders[i]; {
+		case loader == nil:
+			fmt.Fprintf(b, "%s  %v (not initialized)\n", indent, attribute)
+		case loader.debug:
+			fmt.Fprintf(b, "%s  %v:\n", indent, attribute)
+			loader.dump(b, indent+"    ")
+		default:
+			fmt.Fprintf(b, "%s  %v: %s\n", indent, attribute, loader)
+		}
+	}
+}
+
+// snapshot returns the registered attributes sorted by name
+// with their loaders, nil if not initialized.
+func (l *AttrDataLoader) snapshot() ([]Attribute, []*DataLoader) {
+	l.mu.Lock()
+	attributes := make([]Attribute, 0, len(l.initLoaders))
+	for attribute := range l.initLoaders {
+		attributes = append(attributes, attribute)
+	}
+	sortByName(attributes)
+	loaders := make([]*DataLoader, len(attributes))
+	for i, attribute := range attributes {
+		loaders[i] = l.loaders[attribute]
+	}
+	l.mu.Unlock()
+	return attributes, loaders
+}
+
+// String returns a one line summary of the object types of the ObjAttrDataLoader,
+// see AttrDataLoader.String.
+func (l *ObjAttrDataLoader) String() string {
+	objectTypes, loaders := l.snapshot()
+	parts := make([]string, len(objectTypes))
+	for i, objectType := range objectTypes {
+		if loaders[i] == nil {
+			parts[i] = fmt.Sprintf("%v (not initialized)", objectType)
+		} else {
+			parts[i] = fmt.Sprintf("%v: %s", objectType, loaders[i])
+		}
+	}
+	return fmt.Sprintf("ObjAttrDataLoader{%s}", strings.Join(parts, ", "))
+}
+
+// Dump returns a human-readable dump of the object types of the ObjAttrDataLoader
+// with the dumps of their initialized loaders, see AttrDataLoader.Dump.
+// Loaders are not initialized by Dump.
+func (l *ObjAttrDataLoader) Dump() string {
+	objectTypes, loaders := l.snapshot()
+	var b strings.Builder
+	fmt.Fprintf(&b, "ObjAttrDataLoader: %d object types\n", len(objectTypes))
+	for i, objectType := range objectTypes {
+		if loaders[i] == nil {
+			fmt.Fprintf(&b, "  %v (not initialized)\n", objectType)
+		} else {
+			fmt.Fprintf(&b, "  %v:\n", objectType)
+			loaders[i].dump(&b, "    ")
+		}
+	}
+	return b.String()
+}
+
+// snapshot returns the registered object types sorted by name
+// with their loaders, nil if not initialized.
+func (l *ObjAttrDataLoader) snapshot() ([]ObjectType, []*AttrDataLoader) {
+	l.mu.Lock()
+	objectTypes := make([]ObjectType, 0, len(l.initLoaders))
+	for objectType := range l.initLoaders {
+		objectTypes = append(objectTypes, objectType)
+	}
+	sortByName(objectTypes)
+	loaders := make([]*AttrDataLoader, len(objectTypes))
+	for i, objectType := range objectTypes {
+		loaders[i] = l.loaders[objectType]
+	}
+	l.mu.Unlock()
+	return objectTypes, loaders
+}
+
+// sortByName sorts attributes or object types by their formatted names.
+func sortByName[T any](names []T) {
+	sort.SliceStable(names, func(i, j int) bool {
+		return fmt.Sprint(names[i]) < fmt.Sprint(names[j])
+	})
+}
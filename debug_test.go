@@ -0,0 +1,48 @@
+package dataloaders
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDump(t *testing.T) {
+	l := NewTyped(echoFetcher, WithWait(time.Hour), WithMaxBatch(10), WithDebug())
+	l.Prime(9, 9)
+	l.LoadThunk(context.Background(), 1)
+	l.LoadThunk(context.Background(), 2)
+	defer l.Dispatch()
+
+	want := "DataLoader[int, int]{wait: 1h0m0s, maxBatch: 10, cached: 1, batching: 2, fetching: 0}"
+	if s := l.String(); s != want {
+		t.Fatalf("got %q, want %q", s, want)
+	}
+	if dump := l.Dump(); !strings.Contains(dump, "batching: 2 keys [1 2]") {
+		t.Fatalf("dump without the batched keys:\n%s", dump)
+	}
+}
+
+func TestDumpWithoutDebug(t *testing.T) {
+	l := NewTyped(echoFetcher, WithWait(time.Hour))
+	l.LoadThunk(context.Background(), 1)
+	defer l.Dispatch()
+	// no keys without WithDebug
+	if dump := l.Dump(); dump != l.String() {
+		t.Fatalf("got %q, want %q", dump, l.String())
+	}
+}
+
+func TestAttrDump(t *testing.T) {
+	l := newAccountDB().loader()
+	if _, err := l.Load(context.Background(), "id", 1); err != nil {
+		t.Fatal(err)
+	}
+	s := l.String()
+	if !strings.HasPrefix(s, "AttrDataLoader{email (not initialized), id: DataLoader[") {
+		t.Fatalf("got %q, want email not initialized and id initialized", s)
+	}
+	if dump := l.Dump(); !strings.HasPrefix(dump, "AttrDataLoader: 2 attributes\n") {
+		t.Fatalf("got dump:\n%s", dump)
+	}
+}
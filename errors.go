@@ -0,0 +1,103 @@
+package dataloaders
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// WithKeyedErrors wraps the errors the fetcher returned for a key in a *KeyedFetchError
+// carrying the key, so the failing key can be handled programmatically.
+// Attribute and object attribute DataLoaders add the attribute and object type.
+// The wrapped error is still matched by errors.Is and errors.As.
+func WithKeyedErrors() Option {
+	return func(o *options) {
+		o.keyedErrors = true
+	}
+}
+
+// Occurs when the fetcher returned an error for a key, see WithKeyedErrors.
+type KeyedFetchError struct {
+	// The object type loaded, nil if not loaded by an ObjAttrDataLoader.
+	ObjectType ObjectType
+	// The attribute loaded, nil if not loaded by an AttrDataLoader.
+	Attribute Attribute
+	// The key loaded.
+	Key Key
+	// The error returned by the fetcher.
+	Err error
+}
+
+func (e *KeyedFetchError) Error() string {
+	switch {
+	case e.ObjectType != nil:
+		return fmt.Sprintf("loading key '%v' at attribute '%v' of objectType '%v': %v", e.Key, e.Attribute, e.ObjectType, e.Err)
+	case e.Attribute != nil:
+		return fmt.Sprintf("loading key '%v' at attribute '%v': %v", e.Key, e.Attribute, e.Err)
+	default:
+		return fmt.Sprintf("loading key '%v': %v", e.Key, e.Err)
+	}
+}
+
+func (e *KeyedFetchError) Unwrap() error {
+	return e.Err
+}
+
+// IsNotRegistered returns true if no loader is registered for the requested object type,
+// attribute or name (*ObjTypeNotRegError, *AttrNotRegError and *LoaderNotRegError).
+func IsNotRegistered(err error) bool {
+	var objTypeErr *ObjTypeNotRegError
+	var attrErr *AttrNotRegError
+	var loaderErr *LoaderNotRegError
+	return errors.As(err, &objTypeErr) || errors.As(err, &attrErr) || errors.As(err, &loaderErr)
+}
+
+// IsFetchError returns true if the error was returned by the fetcher or occurred while fetching,
+// e.g. *KeyedFetchError, *FetchPanicError, *FetchResultError, ErrNotFound or ErrCircuitOpen.
+// Returns false for nil errors and errors of using the loaders wrongly:
+// unregistered loaders (see IsNotRegistered), ErrClosed, *ValueTypeError,
+// *LoaderDupRegError and *LoaderTypeError.
+func IsFetchError(err error) bool {
+	if err == nil || IsNotRegistered(err) || errors.Is(err, ErrClosed) {
+		return false
+	}
+	var valueTypeErr *ValueTypeError
+	var dupErr *LoaderDupRegError
+	var loaderTypeErr *LoaderTypeError
+	return !errors.As(err, &valueTypeErr) && !errors.As(err, &dupErr) && !errors.As(err, &loaderTypeErr)
+}
+
+// IsTimeout returns true if a deadline exceeded while loading, either of a context
+// or reported by the error itself like net.Error, e.g. by an HTTP client timeout.
+func IsTimeout(err error) bool {
+	if errors.Is(err, context.DeadlineExceeded) {
+		return true
+	}
+	var timeout interface{ Timeout() bool }
+	return errors.As(err, &timeout) && timeout.Timeout()
+}
+
+// keyed wraps the error of key in a *KeyedFetchError if enabled by WithKeyedErrors.
+func (l *TypedDataLoader[K, V]) keyed(key K, err error) error {
+	if err == nil || !l.keyedErrors {
+		return err
+	}
+	return &KeyedFetchError{Key: key, Err: err}
+}
+
+// keyedAt returns a copy of a *KeyedFetchError with the non-nil object type and attribute set.
+// Other errors are returned unchanged.
+func keyedAt(err error, objectType ObjectType, attribute Attribute) error {
+	e, ok := err.(*KeyedFetchError)
+	if !ok {
+		return err
+	}
+	c := *e
+	if objectType != nil {
+		c.ObjectType = objectType
+	}
+	if attribute != nil {
+		c.Attribute = attribute
+	}
+	return &c
+}
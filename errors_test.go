@@ -0,0 +1,78 @@
+package dataloaders
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestKeyedErrors(t *testing.T) {
+	boom := errors.New("boom")
+	l := NewObjAttrDataLoader(ObjAttrDataLoaderInits{
+		"user": func() *AttrDataLoader {
+			return NewAttrDataLoader(AttrDataLoaderInits{
+				"id": func() *DataLoader {
+					return New(func(ctx context.Context, keys []Key) ([]Value, []error) {
+						return nil, []error{boom}
+					}, WithKeyedErrors())
+				},
+			}, nil)
+		},
+	})
+	ctx := context.Background()
+
+	_, err := l.Load(ctx, "user", "id", 5)
+	var keyedErr *KeyedFetchError
+	if !errors.As(err, &keyedErr) || !errors.Is(err, boom) {
+		t.Fatalf("got %v, want a *KeyedFetchError wrapping boom", err)
+	}
+	if keyedErr.Key != 5 || keyedErr.Attribute != "id" || keyedErr.ObjectType != "user" {
+		t.Fatalf("got key %v at %v of %v, want 5 at id of user", keyedErr.Key, keyedErr.Attribute, keyedErr.ObjectType)
+	}
+
+	_, err = l.Load(ctx, "user", "email", 5)
+	if !errors.Is(err, &AttrNotRegError{Attribute: "email"}) || !errors.Is(err, &AttrNotRegError{}) {
+		t.Fatalf("got %v, want *AttrNotRegError of email", err)
+	}
+	if errors.Is(err, &AttrNotRegError{Attribute: "id"}) {
+		t.Fatalf("%v matched *AttrNotRegError of id", err)
+	}
+
+	_, err = l.Load(ctx, "post", "id", 5)
+	var objTypeErr *ObjTypeNotRegError
+	if !errors.As(err, &objTypeErr) || objTypeErr.ObjectType != "post" {
+		t.Fatalf("got %v, want *ObjTypeNotRegError of post", err)
+	}
+}
+
+func TestClassifyErrors(t *testing.T) {
+	if IsResolverError(nil) || IsFetchError(nil) || IsNotRegistered(nil) || IsTimeout(nil) {
+		t.Fatal("classified nil error")
+	}
+	notReg := fmt.Errorf("resolving: %w", NewAttrNotRegError("no email"))
+	if !IsNotRegistered(notReg) || IsResolverError(notReg) || IsFetchError(notReg) {
+		t.Fatalf("%v not classified as not registered only", notReg)
+	}
+	if IsFetchError(ErrClosed) {
+		t.Fatalf("%v classified as fetch error", ErrClosed)
+	}
+	if err := (&KeyedFetchError{Key: 1, Err: ErrNotFound}); !IsFetchError(err) {
+		t.Fatalf("%v not classified as fetch error", err)
+	}
+	if err := (&KeyedFetchError{Key: 1, Err: context.DeadlineExceeded}); !IsTimeout(err) {
+		t.Fatalf("%v not classified as timeout", err)
+	}
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(50 * time.Millisecond)
+	}))
+	defer srv.Close()
+	client := &http.Client{Timeout: time.Millisecond}
+	if _, err := client.Get(srv.URL); !IsTimeout(err) {
+		t.Fatalf("%v of HTTP client not classified as timeout", err)
+	}
+}
@@ -0,0 +1,100 @@
+package dataloaders
+
+import (
+	"expvar"
+	"fmt"
+	"sync"
+)
+
+// ExpvarName is the name of the expvar map the stats are published in,
+// see WithExpvar and Registry.PublishExpvar.
+const ExpvarName = "dataloaders"
+
+var (
+	expvarOnce sync.Once
+	expvarMap  *expvar.Map
+)
+
+// publishExpvar sets the variable at name in the published expvar map,
+// replacing the variable published before under name.
+func publishExpvar(name string, v expvar.Var) {
+	expvarOnce.Do(func() {
+		expvarMap = expvar.NewMap(ExpvarName)
+	})
+	expvarMap.Set(name, v)
+}
+
+// WithExpvar publishes the Stats of the DataLoader via expvar under name
+// in the "dataloaders" map, served at /debug/vars by the expvar handler.
+// A DataLoader created later with the same name replaces the published one,
+// so it's meant for loaders living as long as the process, see Registry.PublishExpvar otherwise.
+func WithExpvar(name string) Option {
+	return func(o *options) {
+		o.expvarName = name
+	}
+}
+
+// PublishExpvar publishes the Stats of all loaders of the Registry via expvar
+// under name in the "dataloaders" map, computed on every read, see Registry.Stats.
+// A Registry published later with the same name replaces this one.
+func (r *Registry) PublishExpvar(name string) {
+	publishExpvar(name, expvar.Func(func() interface{} {
+		return r.Stats()
+	}))
+}
+
+// Stats returns the Stats of all registered loaders by name, e.g. to export them as metrics.
+// The loaders of an AttrDataLoader are named "name.attribute" and
+// of an ObjAttrDataLoader "name.objectType.attribute". Uninitialized loaders
+// and loaders without Stats method, like untyped values, are skipped.
+func (r *Registry) Stats() map[string]Stats {
+	r.mu.RLock()
+	loaders := make(map[string]interface{}, len(r.loaders))
+	for name, loader := range r.loaders {
+		loaders[name] = loader
+	}
+	r.mu.RUnlock()
+
+	stats := make(map[string]Stats, len(loaders))
+	for name, loader := range loaders {
+		switch loader := loader.(type) {
+		case interface{ Stats() Stats }:
+			stats[name] = loader.Stats()
+		case *AttrDataLoader:
+			for attribute, s := range loader.Stats() {
+				stats[fmt.Sprintf("%s.%v", name, attribute)] = s
+			}
+		case *ObjAttrDataLoader:
+			for objectType, attributes := range loader.Stats() {
+				for attribute, s := range attributes {
+					stats[fmt.Sprintf("%s.%v.%v", name, objectType, attribute)] = s
+				}
+			}
+		}
+	}
+	return stats
+}
+
+// Stats returns the Stats of the initialized loaders by attribute.
+func (l *AttrDataLoader) Stats() map[Attribute]Stats {
+	attributes, loaders := l.snapshot()
+	stats := make(map[Attribute]Stats, len(attributes))
+	for i, attribute := range attributes {
+		if loaders[i] != nil {
+			stats[attribute] = loaders[i].Stats()
+		}
+	}
+	return stats
+}
+
+// Stats returns the Stats of the initialized loaders by object type and attribute.
+func (l *ObjAttrDataLoader) Stats() map[ObjectType]map[Attribute]Stats {
+	objectTypes, loaders := l.snapshot()
+	stats := make(map[ObjectType]map[Attribute]Stats, len(objectTypes))
+	for i, objectType := range objectTypes {
+		if loaders[i] != nil {
+			stats[objectType] = loaders[i].Stats()
+		}
+	}
+	return stats
+}
@@ -0,0 +1,62 @@
+package dataloaders
+
+import (
+	"context"
+	"encoding/json"
+	"expvar"
+	"testing"
+)
+
+func TestWithExpvar(t *testing.T) {
+	l := NewTyped(echoFetcher, WithExpvar("expvar-users"))
+	if _, err := l.Load(context.Background(), 1); err != nil {
+		t.Fatal(err)
+	}
+	var published map[string]json.RawMessage
+	if err := json.Unmarshal([]byte(expvar.Get(ExpvarName).String()), &published); err != nil {
+		t.Fatal(err)
+	}
+	var s Stats
+	if err := json.Unmarshal(published["expvar-users"], &s); err != nil {
+		t.Fatal(err)
+	}
+	if s.Misses != 1 {
+		t.Fatalf("got %+v, want 1 miss", s)
+	}
+}
+
+func TestRegistryStats(t *testing.T) {
+	db := newAccountDB()
+	users := NewTyped(echoFetcher)
+	accounts := NewRegistryBuilder().
+		Object("account").
+		Attr("id", db.fetcher("id")).
+		Attr("email", db.fetcher("email")).
+		Build()
+	ctx := context.Background()
+	users.Load(ctx, 1)
+	accounts.Load(ctx, "account", "id", 1)
+
+	r := NewRegistry()
+	r.MustRegister("users", users)
+	r.MustRegister("accounts", accounts)
+	// skipped, no Stats
+	r.MustRegister("untyped", 5)
+	stats := r.Stats()
+	if len(stats) != 2 || stats["users"].Misses != 1 || stats["accounts.account.id"].Misses != 1 {
+		t.Fatalf("got %+v, want users and accounts.account.id with 1 miss", stats)
+	}
+
+	r.PublishExpvar("expvar-registry")
+	var published map[string]json.RawMessage
+	if err := json.Unmarshal([]byte(expvar.Get(ExpvarName).String()), &published); err != nil {
+		t.Fatal(err)
+	}
+	var registry map[string]Stats
+	if err := json.Unmarshal(published["expvar-registry"], &registry); err != nil {
+		t.Fatal(err)
+	}
+	if s := registry["accounts.account.id"]; s.Misses != 1 {
+		t.Fatalf("got %+v, want 1 miss", s)
+	}
+}
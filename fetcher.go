@@ -0,0 +1,37 @@
+package dataloaders
+
+import "context"
+
+// MapFetcher is the map fetch function of the untyped DataLoader.
+type MapFetcher = TypedMapFetcher[Key, Value]
+
+// TypedMapFetcher loads the values for a batch of keys into a map by key,
+// e.g. from the rows of a `WHERE id IN (...)` query.
+// Keys missing in the map get ErrNotFound, see WithNotFound.
+type TypedMapFetcher[K comparable, V any] func(ctx context.Context, keys []K) (map[K]V, error)
+
+// Fetcher converts the map fetch function into a Fetcher
+// returning the values in the order of the keys.
+// An error fails the whole batch.
+func (f TypedMapFetcher[K, V]) Fetcher() TypedFetcher[K, V] {
+	return func(ctx context.Context, keys []K) ([]V, []error) {
+		m, err := f(ctx, keys)
+		if err != nil {
+			return nil, []error{err}
+		}
+		values := make([]V, len(keys))
+		var errs []error
+		for i, key := range keys {
+			v, ok := m[key]
+			if !ok {
+				if errs == nil {
+					errs = make([]error, len(keys))
+				}
+				errs[i] = ErrNotFound
+				continue
+			}
+			values[i] = v
+		}
+		return values, errs
+	}
+}
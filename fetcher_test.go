@@ -0,0 +1,30 @@
+package dataloaders
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestMapFetcher(t *testing.T) {
+	fetch := TypedMapFetcher[int, string](func(ctx context.Context, keys []int) (map[int]string, error) {
+		return map[int]string{1: "a", 3: "c"}, nil
+	}).Fetcher()
+	values, errs := fetch(context.Background(), []int{3, 2, 1})
+	if values[0] != "c" || values[2] != "a" || errs[0] != nil || errs[2] != nil {
+		t.Fatalf("got %v, %v, want the values in the order of the keys", values, errs)
+	}
+	if !errors.Is(errs[1], ErrNotFound) {
+		t.Fatalf("missing key: got %v, want %v", errs[1], ErrNotFound)
+	}
+}
+
+func TestMapFetcherError(t *testing.T) {
+	errDown := errors.New("down")
+	fetch := TypedMapFetcher[int, string](func(ctx context.Context, keys []int) (map[int]string, error) {
+		return nil, errDown
+	}).Fetcher()
+	if _, errs := fetch(context.Background(), []int{1, 2}); len(errs) != 1 || !errors.Is(errs[0], errDown) {
+		t.Fatalf("got %v, want the error for the whole batch", errs)
+	}
+}
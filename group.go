@@ -0,0 +1,48 @@
+package dataloaders
+
+import "context"
+
+// GroupFetcher is the group fetch function of the untyped GroupLoader.
+type GroupFetcher = TypedGroupFetcher[Key, Value]
+
+// TypedGroupFetcher loads the values belonging to a batch of keys grouped by key,
+// e.g. the comments of posts from the rows of a `WHERE post_id IN (...)` query.
+// Keys missing in the map have no values.
+type TypedGroupFetcher[K comparable, V any] func(ctx context.Context, keys []K) (map[K][]V, error)
+
+// Fetcher converts the group fetch function into a Fetcher
+// returning the values of each key in the order of the keys.
+// An error fails the whole batch.
+func (f TypedGroupFetcher[K, V]) Fetcher() TypedFetcher[K, []V] {
+	return func(ctx context.Context, keys []K) ([][]V, []error) {
+		m, err := f(ctx, keys)
+		if err != nil {
+			return nil, []error{err}
+		}
+		values := make([][]V, len(keys))
+		for i, key := range keys {
+			values[i] = m[key]
+		}
+		return values, nil
+	}
+}
+
+// GroupLoader is the untyped one-to-many loader.
+type GroupLoader = TypedGroupLoader[Key, Value]
+
+// TypedGroupLoader is a DataLoader loading many values per key,
+// batching and caching the slices of values by key.
+type TypedGroupLoader[K comparable, V any] struct {
+	*TypedDataLoader[K, []V]
+}
+
+// NewGroupLoader creates a GroupLoader configured by the given options.
+func NewGroupLoader(fetch GroupFetcher, opts ...Option) *GroupLoader {
+	return NewTypedGroupLoader(fetch, opts...)
+}
+
+// NewTypedGroupLoader creates a GroupLoader with typed keys and values configured by the given options.
+// Options depending on the value type, like WithValueID, must use the slice of values.
+func NewTypedGroupLoader[K comparable, V any](fetch TypedGroupFetcher[K, V], opts ...Option) *TypedGroupLoader[K, V] {
+	return &TypedGroupLoader[K, V]{NewTyped(fetch.Fetcher(), opts...)}
+}
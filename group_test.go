@@ -0,0 +1,37 @@
+package dataloaders
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestGroupLoader(t *testing.T) {
+	var fetched [][]int
+	l := NewTypedGroupLoader(func(ctx context.Context, keys []int) (map[int][]string, error) {
+		fetched = append(fetched, keys)
+		return map[int][]string{1: {"a", "b"}}, nil
+	}, WithSynchronous())
+	values, errs := l.LoadAll(context.Background(), []int{1, 2})
+	// keys missing in the map have no values
+	if fmt.Sprint(values) != "[[a b] []]" || values[1] != nil || anyError(errs) {
+		t.Fatalf("got %v, %v", values, errs)
+	}
+	if len(fetched) != 1 {
+		t.Fatalf("fetched %v, want one batch", fetched)
+	}
+}
+
+func TestGroupFetcherError(t *testing.T) {
+	errDown := errors.New("down")
+	l := NewTypedGroupLoader(func(ctx context.Context, keys []int) (map[int][]string, error) {
+		return nil, errDown
+	}, WithSynchronous())
+	_, errs := l.LoadAll(context.Background(), []int{1, 2})
+	for i, err := range errs {
+		if !errors.Is(err, errDown) {
+			t.Fatalf("key %d: got %v, want %v", i, err, errDown)
+		}
+	}
+}
@@ -0,0 +1,59 @@
+package dataloaders
+
+import (
+	"context"
+	"time"
+)
+
+// WithHedging sends a batch to the fetcher a second time if it didn't return within delay,
+// e.g. because it hit a slow replica, and uses the result of whichever fetch returns first.
+// The other fetch is canceled. A fetch failing the whole batch waits for the other one.
+// Hedging doubles the load on the backend for slow batches, so delay should be a high
+// percentile of the fetch latency (e.g. p95) and the fetcher must be idempotent.
+// The second fetch is neither limited by WithMaxConcurrentBatches nor by the rate limiter.
+func WithHedging(delay time.Duration) Option {
+	return func(o *options) {
+		o.hedgeDelay = delay
+	}
+}
+
+// hedgedFetch calls the fetcher, hedged if configured by WithHedging.
+func (l *TypedDataLoader[K, V]) hedgedFetch(ctx context.Context, keys []K) ([]V, []error) {
+	if l.hedgeDelay <= 0 {
+		return l.safeFetch(ctx, keys)
+	}
+	// cancels the fetch still running once one returned
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	type fetched struct {
+		data []V
+		errs []error
+	}
+	results := make(chan fetched, 2)
+	fetch := func() {
+		data, errs := l.safeFetch(ctx, keys)
+		results <- fetched{data: data, errs: errs}
+	}
+	go fetch()
+
+	hedge := make(chan struct{})
+	timer := l.clock.AfterFunc(l.hedgeDelay, func() { close(hedge) })
+	defer timer.Stop()
+
+	running := 1
+	for {
+		select {
+		case <-hedge:
+			hedge = nil
+			running++
+			l.stats.hedges.Add(1)
+			go fetch()
+		case r := <-results:
+			running--
+			if batchError(r.errs) == nil || running == 0 {
+				return r.data, r.errs
+			}
+		}
+	}
+}
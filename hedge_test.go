@@ -0,0 +1,34 @@
+package dataloaders
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestWithHedging(t *testing.T) {
+	var fetches int32
+	canceled := make(chan struct{})
+	l := NewTyped(func(ctx context.Context, keys []int) ([]int, []error) {
+		if atomic.AddInt32(&fetches, 1) == 1 {
+			// the slow replica, canceled once the hedged fetch returned
+			<-ctx.Done()
+			close(canceled)
+			return nil, []error{ctx.Err()}
+		}
+		return keys, nil
+	}, WithWait(time.Millisecond), WithHedging(20*time.Millisecond))
+
+	if v, err := l.Load(context.Background(), 3); err != nil || v != 3 {
+		t.Fatalf("got %v, %v, want 3", v, err)
+	}
+	select {
+	case <-canceled:
+	case <-time.After(time.Second):
+		t.Fatal("slow fetch not canceled")
+	}
+	if n := l.Stats().Hedges; n != 1 {
+		t.Fatalf("got %d hedges, want 1", n)
+	}
+}
@@ -0,0 +1,132 @@
+package dataloaders
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// Hooks are the hooks of the untyped DataLoader.
+type Hooks = TypedHooks[Key, Value]
+
+// TypedHooks observe the loads, cache hits and batches of a DataLoader.
+// Nil functions are skipped. Key slices passed to hooks must not be modified.
+type TypedHooks[K comparable, V any] struct {
+	// OnLoad is called for every key requested from the DataLoader.
+	OnLoad func(ctx context.Context, key K)
+	// OnCacheHit is called when a requested key was found in the cache.
+	OnCacheHit func(ctx context.Context, key K)
+	// OnCacheMiss is called when a requested key was not found in the cache
+	// and therefore added to a batch.
+	OnCacheMiss func(ctx context.Context, key K)
+	// OnBatchDispatch is called when a batch is sent to the fetcher.
+	OnBatchDispatch func(ctx context.Context, keys []K)
+	// OnBatchDone is called when the fetcher returned a batch,
+	// with the fetch duration and the errors as returned by the fetcher.
+	OnBatchDone func(ctx context.Context, keys []K, duration time.Duration, errs []error)
+	// OnFetchError is called when the fetcher returned any non-nil error for a batch.
+	OnFetchError func(ctx context.Context, keys []K, errs []error)
+
+	// Async runs the hooks in their own goroutine
+	// instead of synchronously on the loading goroutine.
+	Async bool
+}
+
+// WithHooks registers hooks on the DataLoader.
+// The key and value types of the hooks must match the ones of the DataLoader.
+func WithHooks[K comparable, V any](hooks ...TypedHooks[K, V]) Option {
+	return func(o *options) {
+		for _, h := range hooks {
+			o.hooks = append(o.hooks, h)
+		}
+	}
+}
+
+// AddHooks registers hooks on the DataLoader.
+func (l *TypedDataLoader[K, V]) AddHooks(hooks ...TypedHooks[K, V]) *TypedDataLoader[K, V] {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	var all []TypedHooks[K, V]
+	if old := l.hooks.Load(); old != nil {
+		all = append(all, *old...)
+	}
+	all = append(all, hooks...)
+	l.hooks.Store(&all)
+	return l
+}
+
+// runHooks calls fn with every registered hooks.
+func (l *TypedDataLoader[K, V]) runHooks(fn func(h *TypedHooks[K, V])) {
+	hooks := l.hooks.Load()
+	if hooks == nil {
+		return
+	}
+	for i := range *hooks {
+		h := &(*hooks)[i]
+		if h.Async {
+			go fn(h)
+		} else {
+			fn(h)
+		}
+	}
+}
+
+func (l *TypedDataLoader[K, V]) hookLoad(ctx context.Context, key K) {
+	l.runHooks(func(h *TypedHooks[K, V]) {
+		if h.OnLoad != nil {
+			h.OnLoad(ctx, key)
+		}
+	})
+}
+
+func (l *TypedDataLoader[K, V]) hookCacheHit(ctx context.Context, key K) {
+	l.runHooks(func(h *TypedHooks[K, V]) {
+		if h.OnCacheHit != nil {
+			h.OnCacheHit(ctx, key)
+		}
+	})
+}
+
+func (l *TypedDataLoader[K, V]) hookCacheMiss(ctx context.Context, key K) {
+	l.runHooks(func(h *TypedHooks[K, V]) {
+		if h.OnCacheMiss != nil {
+			h.OnCacheMiss(ctx, key)
+		}
+	})
+}
+
+func (l *TypedDataLoader[K, V]) hookBatchDispatch(ctx context.Context, keys []K) {
+	l.runHooks(func(h *TypedHooks[K, V]) {
+		if h.OnBatchDispatch != nil {
+			h.OnBatchDispatch(ctx, keys)
+		}
+	})
+}
+
+func (l *TypedDataLoader[K, V]) hookBatchDone(ctx context.Context, keys []K, duration time.Duration, errs []error) {
+	failed := anyError(errs)
+	l.runHooks(func(h *TypedHooks[K, V]) {
+		if h.OnBatchDone != nil {
+			h.OnBatchDone(ctx, keys, duration, errs)
+		}
+		if failed && h.OnFetchError != nil {
+			h.OnFetchError(ctx, keys, errs)
+		}
+	})
+}
+
+// newHooks returns the hooks configured by WithHooks and WithLogger.
+func newHooks[K comparable, V any](o *options) []TypedHooks[K, V] {
+	hooks := make([]TypedHooks[K, V], 0, len(o.hooks))
+	for _, h := range o.hooks {
+		typed, ok := h.(TypedHooks[K, V])
+		if !ok {
+			panic(fmt.Sprintf("dataloaders: hooks %T do not match the DataLoader's key and value types", h))
+		}
+		hooks = append(hooks, typed)
+	}
+	if o.logger != nil {
+		hooks = append(hooks, LogHooks[K, V](o.logger))
+	}
+	return hooks
+}
@@ -0,0 +1,63 @@
+package dataloaders
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+// hookRecorder records the calls of its hooks.
+type hookRecorder struct {
+	mu    sync.Mutex
+	calls []string
+}
+
+func (r *hookRecorder) record(call string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.calls = append(r.calls, call)
+}
+
+func (r *hookRecorder) hooks() TypedHooks[int, int] {
+	return TypedHooks[int, int]{
+		OnLoad:          func(ctx context.Context, key int) { r.record("load") },
+		OnCacheHit:      func(ctx context.Context, key int) { r.record("hit") },
+		OnCacheMiss:     func(ctx context.Context, key int) { r.record("miss") },
+		OnBatchDispatch: func(ctx context.Context, keys []int) { r.record("dispatch") },
+		OnBatchDone: func(ctx context.Context, keys []int, duration time.Duration, errs []error) {
+			r.record("done")
+		},
+		OnFetchError: func(ctx context.Context, keys []int, errs []error) { r.record("error") },
+	}
+}
+
+func TestHooks(t *testing.T) {
+	r := &hookRecorder{}
+	l := NewTyped(func(ctx context.Context, keys []int) ([]int, []error) {
+		if keys[0] == 2 {
+			return nil, []error{errors.New("down")}
+		}
+		return keys, nil
+	}, WithSynchronous(), WithHooks(r.hooks()))
+	ctx := context.Background()
+	l.Load(ctx, 1)
+	l.Load(ctx, 1)
+	l.Load(ctx, 2)
+	want := []string{"load", "miss", "dispatch", "done", "load", "hit", "load", "miss", "dispatch", "done", "error"}
+	if !reflect.DeepEqual(r.calls, want) {
+		t.Fatalf("hooks called %v, want %v", r.calls, want)
+	}
+}
+
+func TestAddHooks(t *testing.T) {
+	r := &hookRecorder{}
+	l := NewTyped(echoFetcher, WithSynchronous())
+	l.AddHooks(TypedHooks[int, int]{OnLoad: func(ctx context.Context, key int) { r.record("load") }})
+	l.Load(context.Background(), 1)
+	if !reflect.DeepEqual(r.calls, []string{"load"}) {
+		t.Fatalf("hooks called %v, want [load]", r.calls)
+	}
+}
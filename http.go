@@ -0,0 +1,19 @@
+package dataloaders
+
+import (
+	"context"
+	"net/http"
+)
+
+// Middleware returns net/http middleware carrying a new ObjAttrDataLoader created by the factory
+// in the context of every request (see FromContext).
+// The loader is closed once the handler returned, waiting for its batches still being fetched.
+func Middleware(factory Factory) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			l := factory()
+			defer l.Close(context.WithoutCancel(r.Context()))
+			next.ServeHTTP(w, r.WithContext(NewContext(r.Context(), l)))
+		})
+	}
+}
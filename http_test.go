@@ -0,0 +1,29 @@
+package dataloaders
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestMiddleware(t *testing.T) {
+	db := newAccountDB(&account{ID: 1, Email: "a"})
+	factory := NewRegistryBuilder().Object("account").Attr("id", db.fetcher("id")).Build
+	var l *ObjAttrDataLoader
+	h := Middleware(factory)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		l, _ = FromContext(r.Context())
+		if _, err := l.Load(r.Context(), "account", "id", 1); err != nil {
+			t.Error(err)
+		}
+	}))
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+	if l == nil {
+		t.Fatal("no loader in the request context")
+	}
+	// the loader is closed once the request is done
+	if _, err := l.Load(context.Background(), "account", "id", 2); !errors.Is(err, ErrClosed) {
+		t.Fatalf("got %v, want %v", err, ErrClosed)
+	}
+}
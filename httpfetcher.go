@@ -0,0 +1,135 @@
+package dataloaders
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+// HTTPFetcherOptions configures an HTTP batch fetcher.
+type HTTPFetcherOptions struct {
+	// The client sending the requests. Defaults to http.DefaultClient.
+	Client *http.Client
+	// Timeout of a single request, 0 = no limit.
+	Timeout time.Duration
+	// Request is called with every request before it is sent, e.g. to set authentication headers, may be nil.
+	Request func(req *http.Request) error
+	// Retries of a failed request, waiting Backoff between the attempts.
+	Retries int
+	Backoff Backoff
+	// Retry returns whether a failed request is retried, resp is nil on transport errors.
+	// Defaults to retrying transport errors and 429 and 5xx responses.
+	Retry func(resp *http.Response, err error) bool
+}
+
+// HTTPFetcher returns a fetcher posting the keys of a batch as JSON array to the batch endpoint at url,
+// which responds with a JSON array of the found values. Every value is mapped to its key by key,
+// keys without value get ErrNotFound (see WithNotFound).
+func HTTPFetcher[K comparable, V any](url string, key func(value V) K, opts HTTPFetcherOptions) TypedFetcher[K, V] {
+	if opts.Client == nil {
+		opts.Client = http.DefaultClient
+	}
+	if opts.Retry == nil {
+		opts.Retry = retryHTTP
+	}
+	return TypedMapFetcher[K, V](func(ctx context.Context, keys []K) (map[K]V, error) {
+		body, err := json.Marshal(keys)
+		if err != nil {
+			return nil, fmt.Errorf("encoding keys: %w", err)
+		}
+		var values []V
+		if err := postWithRetry(ctx, url, body, &values, opts); err != nil {
+			return nil, err
+		}
+		m := make(map[K]V, len(values))
+		for _, v := range values {
+			m[key(v)] = v
+		}
+		return m, nil
+	}).Fetcher()
+}
+
+// postWithRetry posts the body to url and decodes the response into out,
+// retrying failed requests as configured.
+func postWithRetry(ctx context.Context, url string, body []byte, out interface{}, opts HTTPFetcherOptions) error {
+	for attempt := 1; ; attempt++ {
+		resp, err := post(ctx, url, body, out, opts)
+		if err == nil {
+			return nil
+		}
+		if attempt > opts.Retries || ctx.Err() != nil || !opts.Retry(resp, err) {
+			return err
+		}
+		var wait time.Duration
+		if opts.Backoff != nil {
+			wait = opts.Backoff(attempt)
+		}
+		timer := time.NewTimer(wait)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return err
+		}
+	}
+}
+
+// post sends a single request and decodes a successful response into out.
+// The returned response is nil on transport errors, its body is closed.
+func post(ctx context.Context, url string, body []byte, out interface{}, opts HTTPFetcherOptions) (*http.Response, error) {
+	if opts.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, opts.Timeout)
+		defer cancel()
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+	if opts.Request != nil {
+		if err := opts.Request(req); err != nil {
+			return nil, err
+		}
+	}
+	resp, err := opts.Client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		// drain the body so the connection can be reused
+		_, _ = io.Copy(io.Discard, resp.Body)
+		return resp, &HTTPStatusError{URL: url, StatusCode: resp.StatusCode}
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return resp, fmt.Errorf("decoding response of %s: %w", url, err)
+	}
+	return resp, nil
+}
+
+// retryHTTP retries transport errors and 429 and 5xx responses.
+func retryHTTP(resp *http.Response, err error) bool {
+	if resp != nil {
+		return resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500
+	}
+	return !errors.Is(err, context.Canceled)
+}
+
+// Occurs when a batch endpoint responded with a non-2xx status.
+type HTTPStatusError struct {
+	// The URL of the endpoint.
+	URL string
+	// The status code of the response.
+	StatusCode int
+}
+
+func (e *HTTPStatusError) Error() string {
+	return fmt.Sprintf("batch endpoint %s responded with status %d", e.URL, e.StatusCode)
+}
@@ -0,0 +1,78 @@
+package dataloaders
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+type item struct {
+	ID int
+}
+
+// newItemServer returns a batch endpoint responding with an item for every key but 3,
+// requiring the X-Token header. It fails the first failures requests with 503.
+func newItemServer(failures int32) (*httptest.Server, *atomic.Int32) {
+	var requests atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if requests.Add(1) <= failures {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		if r.Header.Get("X-Token") != "secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		var keys []int
+		json.NewDecoder(r.Body).Decode(&keys)
+		var items []item
+		for _, key := range keys {
+			if key != 3 {
+				items = append(items, item{ID: key})
+			}
+		}
+		json.NewEncoder(w).Encode(items)
+	}))
+	return srv, &requests
+}
+
+func TestHTTPFetcher(t *testing.T) {
+	srv, requests := newItemServer(1)
+	defer srv.Close()
+	l := NewTyped(HTTPFetcher(srv.URL, func(i item) int { return i.ID }, HTTPFetcherOptions{
+		Timeout: time.Second,
+		Retries: 1,
+		Backoff: ExponentialBackoff(time.Millisecond, time.Millisecond),
+		Request: func(req *http.Request) error {
+			req.Header.Set("X-Token", "secret")
+			return nil
+		},
+	}), WithSynchronous(), WithNotFound(NotFoundError))
+	values, errs := l.LoadAll(context.Background(), []int{1, 2, 3})
+	if values[0].ID != 1 || values[1].ID != 2 || errs[0] != nil || errs[1] != nil || !errors.Is(errs[2], ErrNotFound) {
+		t.Fatalf("got %v, %v", values, errs)
+	}
+	if n := requests.Load(); n != 2 {
+		t.Fatalf("sent %d requests, want the unavailable one retried once", n)
+	}
+}
+
+func TestHTTPFetcherStatusError(t *testing.T) {
+	srv, requests := newItemServer(0)
+	defer srv.Close()
+	l := NewTyped(HTTPFetcher(srv.URL, func(i item) int { return i.ID }, HTTPFetcherOptions{Retries: 2}), WithSynchronous())
+	_, err := l.Load(context.Background(), 1)
+	var statusErr *HTTPStatusError
+	if !errors.As(err, &statusErr) || statusErr.StatusCode != http.StatusUnauthorized {
+		t.Fatalf("got %v, want an HTTPStatusError of status 401", err)
+	}
+	// client errors are not retried
+	if n := requests.Load(); n != 1 {
+		t.Fatalf("sent %d requests, want 1", n)
+	}
+}
@@ -0,0 +1,68 @@
+package dataloaders
+
+import "reflect"
+
+// SetIdentityMap keeps a single canonical instance of every value identified by fn, e.g. their ID,
+// in the caches of all attributes. A value loaded or primed at any attribute that is a new instance
+// replaces the value of the same identity at all other attribute keys it is cached at,
+// and clearing the value at one attribute clears it from all of them (see Clear).
+// It also sets fn as the identity used by ClearValue, see SetValueID.
+func (l *AttrDataLoader) SetIdentityMap(fn func(value Value) interface{}) *AttrDataLoader {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.tracked = true
+	l.valueID = fn
+	l.valueKeys, l.keyIDs = nil, nil
+	l.identities = map[interface{}]Value{}
+	return l
+}
+
+// Records the value as canonical instance of the identity cached at k.
+// Returns the other attribute keys of the identity to prime with the value if it is a new instance.
+// Must be called with l.mu held.
+func (l *AttrDataLoader) identify(id interface{}, k attrKey, value Value) []attrKey {
+	if l.identities == nil {
+		return nil
+	}
+	if prev, ok := l.identities[id]; ok && sameInstance(prev, value) {
+		return nil
+	}
+	l.identities[id] = value
+	others := make([]attrKey, 0, len(l.valueKeys[id]))
+	for other := range l.valueKeys[id] {
+		if other != k {
+			others = append(others, other)
+		}
+	}
+	return others
+}
+
+// Primes the attribute keys with the canonical value.
+func (l *AttrDataLoader) share(keys []attrKey, value Value) {
+	for _, k := range keys {
+		if loader := l.loader(k.attribute); loader != nil {
+			loader.ForcePrime(k.key, value)
+		}
+	}
+}
+
+// Clears the value cached at k from all attribute keys of its identity.
+// Returns false without identity map or if no value is known at k.
+func (l *AttrDataLoader) clearIdentity(k attrKey) bool {
+	l.mu.Lock()
+	id, ok := l.keyIDs[k]
+	ok = ok && l.identities != nil
+	l.mu.Unlock()
+	if !ok {
+		return false
+	}
+	l.clearID(id)
+	return true
+}
+
+// Returns true if a and b are the same instance, e.g. the same pointer.
+// Values of types that are not comparable are never the same.
+func sameInstance(a, b Value) bool {
+	t := reflect.TypeOf(a)
+	return t == reflect.TypeOf(b) && t.Comparable() && a == b
+}
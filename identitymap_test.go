@@ -0,0 +1,33 @@
+package dataloaders
+
+import (
+	"context"
+	"testing"
+)
+
+func TestIdentityMapKeyMovedToOtherValue(t *testing.T) {
+	db := newAccountDB(&account{ID: 1, Email: "bob"}, &account{ID: 2, Email: "alice"})
+	l := db.loader().SetIdentityMap(func(v Value) interface{} { return v.(*account).ID })
+	ctx := context.Background()
+	if _, err := l.Load(ctx, "id", 1); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := l.Load(ctx, "email", "bob"); err != nil {
+		t.Fatal(err)
+	}
+
+	// the email is reassigned to account 2
+	l.ForcePrime("email", "bob", &account{ID: 2, Email: "bob"})
+	if _, ok := l.valueKeys[1][attrKey{attribute: "email", key: "bob"}]; ok {
+		t.Fatal("moved key still tracked at account 1")
+	}
+	// a new instance of account 1 must not be shared to the moved key
+	l.ForcePrime("id", 1, &account{ID: 1, Email: "robert"})
+	v, err := l.Load(ctx, "email", "bob")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if id := v.(*account).ID; id != 2 {
+		t.Fatalf("email bob loaded account %d, want 2", id)
+	}
+}
@@ -0,0 +1,115 @@
+package dataloaders
+
+import "fmt"
+
+// InvalidationBus distributes the keys cleared by a DataLoader to the DataLoaders
+// of the same name on other instances, so they don't serve stale values after writes.
+// Implementations must be safe for concurrent use and handle their errors themselves.
+type InvalidationBus interface {
+	// Publish announces the invalidation to the other instances.
+	Publish(inv Invalidation)
+	// Subscribe registers fn to be called with the invalidations published by other instances
+	// until unsubscribe is called.
+	Subscribe(fn func(inv Invalidation)) (unsubscribe func())
+}
+
+// Invalidation is a cleared key published on an InvalidationBus.
+type Invalidation struct {
+	// The name of the DataLoader, see WithInvalidation.
+	Loader string
+	// The key encoded by the key codec of the DataLoader, nil if All or Tag is set.
+	Key []byte
+	// Whether all keys were cleared.
+	All bool
+	// The cleared tag, see ClearTag.
+	Tag string
+}
+
+// WithInvalidation publishes the keys cleared by Clear, ClearAll, ClearTag and ClearByValueID on the bus
+// and clears the keys published by the DataLoaders of the same name on other instances.
+// The keys are encoded with codec, e.g. JSONCodec.
+// The key type of codec must match the one of the DataLoader.
+// The bus holds the DataLoader until Close unsubscribes it, so DataLoaders created
+// per request (see Middleware) must be closed, otherwise they are never garbage collected.
+func WithInvalidation[K comparable](bus InvalidationBus, name string, codec Codec[K]) Option {
+	return func(o *options) {
+		o.invalidation = &invalidationOptions{bus: bus, name: name, codec: codec}
+	}
+}
+
+type invalidationOptions struct {
+	bus  InvalidationBus
+	name string
+	// the Codec[K] matching the loader's key type
+	codec interface{}
+}
+
+// invalidator publishes and subscribes the invalidations of a DataLoader.
+type invalidator[K comparable] struct {
+	bus         InvalidationBus
+	name        string
+	codec       Codec[K]
+	unsubscribe func()
+}
+
+// subscribe creates the invalidator configured by WithInvalidation, nil if none.
+func subscribe[K comparable, V any](l *TypedDataLoader[K, V], o *options) *invalidator[K] {
+	if o.invalidation == nil {
+		return nil
+	}
+	codec, ok := o.invalidation.codec.(Codec[K])
+	if !ok {
+		panic(fmt.Sprintf("dataloaders: key codec %T does not match the DataLoader's key type", o.invalidation.codec))
+	}
+	inv := &invalidator[K]{bus: o.invalidation.bus, name: o.invalidation.name, codec: codec}
+	inv.unsubscribe = inv.bus.Subscribe(func(published Invalidation) {
+		if published.Loader != inv.name {
+			return
+		}
+		if published.All {
+			l.clearAll()
+			return
+		}
+		if published.Tag != "" {
+			l.clearTag(published.Tag)
+			return
+		}
+		if key, err := codec.Unmarshal(published.Key); err == nil {
+			l.clear(key)
+		}
+	})
+	return inv
+}
+
+// publishKey publishes the cleared key, if i is not nil.
+func (i *invalidator[K]) publishKey(key K) {
+	if i == nil {
+		return
+	}
+	data, err := i.codec.Marshal(key)
+	if err != nil {
+		return
+	}
+	i.bus.Publish(Invalidation{Loader: i.name, Key: data})
+}
+
+// publishAll publishes that all keys were cleared, if i is not nil.
+func (i *invalidator[K]) publishAll() {
+	if i != nil {
+		i.bus.Publish(Invalidation{Loader: i.name, All: true})
+	}
+}
+
+// publishTag publishes the cleared tag, if i is not nil.
+func (i *invalidator[K]) publishTag(tag string) {
+	if i != nil {
+		i.bus.Publish(Invalidation{Loader: i.name, Tag: tag})
+	}
+}
+
+// stop unsubscribes from the bus, if i is not nil.
+func (i *invalidator[K]) stop() {
+	if i != nil {
+		i.unsubscribe()
+	}
+}
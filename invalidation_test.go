@@ -0,0 +1,68 @@
+package dataloaders
+
+import (
+	"context"
+	"sync"
+	"testing"
+)
+
+// localBus is an InvalidationBus delivering the invalidations to all other subscribers.
+type localBus struct {
+	mu       sync.Mutex
+	next     int
+	handlers map[int]func(Invalidation)
+}
+
+func (b *localBus) Publish(inv Invalidation) {
+	b.mu.Lock()
+	handlers := make([]func(Invalidation), 0, len(b.handlers))
+	for _, fn := range b.handlers {
+		handlers = append(handlers, fn)
+	}
+	b.mu.Unlock()
+	for _, fn := range handlers {
+		fn(inv)
+	}
+}
+
+func (b *localBus) Subscribe(fn func(Invalidation)) func() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.handlers == nil {
+		b.handlers = map[int]func(Invalidation){}
+	}
+	id := b.next
+	b.next++
+	b.handlers[id] = fn
+	return func() {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		delete(b.handlers, id)
+	}
+}
+
+func (b *localBus) subscribers() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return len(b.handlers)
+}
+
+func TestInvalidationClearsOtherLoaders(t *testing.T) {
+	bus := &localBus{}
+	f := newCountingFetcher(func(keys []int) ([]int, []error) { return keys, nil })
+	a := NewTyped(f.fetcher, WithInvalidation(bus, "users", JSONCodec[int]()))
+	b := NewTyped(f.fetcher, WithInvalidation(bus, "users", JSONCodec[int]()))
+	ctx := context.Background()
+	b.Load(ctx, 1)
+	a.Clear(1)
+	b.Load(ctx, 1)
+	if n := f.fetches(1); n != 2 {
+		t.Fatalf("fetched key %d times, want 2", n)
+	}
+
+	a.Close(ctx)
+	b.Close(ctx)
+	if n := bus.subscribers(); n != 0 {
+		t.Fatalf("%d loaders still subscribed after Close", n)
+	}
+}
@@ -0,0 +1,112 @@
+package dataloaders
+
+import (
+	"sync"
+	"time"
+)
+
+// Expirer is implemented by caches able to evict their expired values,
+// like the TTL caches (see NewTTLCache).
+type Expirer interface {
+	// EvictExpired evicts all expired values and returns their number.
+	EvictExpired() int
+}
+
+// Janitor evicts the expired values of a cache periodically in the background,
+// so values that are not accessed again don't stay in memory.
+type Janitor struct {
+	cache    Expirer
+	interval time.Duration
+	clock    Clock
+	// logs the evicted values, may be nil
+	logger Logger
+
+	// schedules the next eviction
+	timer   Timer
+	stopped bool
+
+	// held while evicting
+	mu sync.Mutex
+}
+
+// StartJanitor starts a Janitor evicting the expired values of cache every interval.
+func StartJanitor(cache Expirer, interval time.Duration) *Janitor {
+	return startJanitor(cache, interval, SystemClock, nil)
+}
+
+// startJanitor starts a Janitor evicting every interval by clock, logging to logger if not nil.
+func startJanitor(cache Expirer, interval time.Duration, clock Clock, logger Logger) *Janitor {
+	j := &Janitor{cache: cache, interval: interval, clock: clock, logger: logger}
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	j.timer = clock.AfterFunc(interval, j.evict)
+	return j
+}
+
+// evict evicts the expired values and schedules the next eviction.
+func (j *Janitor) evict() {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	if j.stopped {
+		return
+	}
+	if n := j.cache.EvictExpired(); n != 0 && j.logger != nil {
+		j.logger.Debug("dataloaders: expired values evicted", "count", n)
+	}
+	j.timer = j.clock.AfterFunc(j.interval, j.evict)
+}
+
+// Stop stops the Janitor and waits until a running eviction is done.
+// It can be called multiple times.
+func (j *Janitor) Stop() {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	j.stopped = true
+	j.timer.Stop()
+}
+
+// WithJanitor starts a Janitor evicting the expired values of the cache every interval,
+// stopped by Close. It has no effect if the cache doesn't implement Expirer,
+// which the default cache does with WithTTL or WithSlidingExpiration.
+// Expired values are evicted even with WithStaleWhileRevalidate, so they are fetched synchronously again.
+func WithJanitor(interval time.Duration) Option {
+	return func(o *options) {
+		o.janitor = interval
+	}
+}
+
+// EvictExpired evicts all expired values.
+func (c *ttlCache[K, V]) EvictExpired() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	now := c.clock.Now()
+	var n int
+	for key, exp := range c.expires {
+		if !now.Before(exp.at) {
+			c.inner.Delete(key)
+			delete(c.expires, key)
+			n++
+		}
+	}
+	return n
+}
+
+// EvictExpired evicts the expired values of all shards implementing Expirer.
+func (c *shardedCache[K, V]) EvictExpired() int {
+	var n int
+	for _, s := range c.shards {
+		if e, ok := s.(Expirer); ok {
+			n += e.EvictExpired()
+		}
+	}
+	return n
+}
+
+// EvictExpired evicts the expired values of l1, if it implements Expirer.
+// l2 is expected to expire its values itself.
+func (c *tieredCache[K, V]) EvictExpired() int {
+	if e, ok := c.l1.(Expirer); ok {
+		return e.EvictExpired()
+	}
+	return 0
+}
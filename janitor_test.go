@@ -0,0 +1,37 @@
+package dataloaders
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestWithJanitor(t *testing.T) {
+	clock := NewFakeClock(time.Now())
+	l := NewTyped(echoFetcher, WithClock(clock), WithTTL(time.Minute), WithJanitor(time.Second))
+	l.Prime(1, 1)
+	clock.Advance(30 * time.Second)
+	l.Prime(2, 2)
+	clock.Advance(30 * time.Second)
+	// only the expired value is evicted without being accessed
+	if n := l.cache.Len(); n != 1 {
+		t.Fatalf("cached %d values, want 1", n)
+	}
+	if err := l.Close(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if n := clock.Timers(); n != 0 {
+		t.Fatalf("%d timers left after Close, want the janitor stopped", n)
+	}
+}
+
+func TestStartJanitor(t *testing.T) {
+	c := NewTTLCache(NewMapCache[int, int](), time.Nanosecond)
+	c.Set(1, 1)
+	time.Sleep(time.Millisecond)
+	j := StartJanitor(c.(Expirer), time.Millisecond)
+	defer j.Stop()
+	for c.Len() != 0 {
+		time.Sleep(time.Millisecond)
+	}
+}
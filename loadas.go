@@ -0,0 +1,79 @@
+package dataloaders
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+)
+
+// TypeOf returns the object type of the Go type T used by LoadAs,
+// so object types don't need to be identified by strings.
+func TypeOf[T any]() ObjectType {
+	return reflect.TypeOf((*T)(nil)).Elem()
+}
+
+// RegisterTypeOf registers the loader initializer of the Go type T, see TypeOf.
+func RegisterTypeOf[T any](l *ObjAttrDataLoader, init func() *AttrDataLoader) {
+	l.RegisterType(TypeOf[T](), init)
+}
+
+// ObjectOf returns the builder of the Go type T, see TypeOf.
+func ObjectOf[T any](b *RegistryBuilder) *ObjectBuilder {
+	return b.Object(TypeOf[T]())
+}
+
+// LoadAs loads the value at key at attribute of the object type of T (see TypeOf)
+// and returns it as T. A nil value is returned as the zero value of T.
+func LoadAs[T any](ctx context.Context, l *ObjAttrDataLoader, attribute Attribute, key Key, opts ...LoadOption) (T, error) {
+	value, err := l.Load(ctx, TypeOf[T](), attribute, key, opts...)
+	if err != nil {
+		var zero T
+		return zero, err
+	}
+	return valueAs[T](value)
+}
+
+// LoadAllAs loads the values at keys at attribute of the object type of T, see LoadAs.
+func LoadAllAs[T any](ctx context.Context, l *ObjAttrDataLoader, attribute Attribute, keys []Key, opts ...LoadOption) ([]T, []error) {
+	values, errs := l.LoadAll(ctx, TypeOf[T](), attribute, keys, opts...)
+	typed := make([]T, len(values))
+	for i, value := range values {
+		if i < len(errs) && errs[i] != nil {
+			continue
+		}
+		var err error
+		if typed[i], err = valueAs[T](value); err != nil {
+			if errs == nil {
+				errs = make([]error, len(values))
+			}
+			errs[i] = err
+		}
+	}
+	return typed, errs
+}
+
+// Returns the value as T.
+func valueAs[T any](value Value) (T, error) {
+	if value == nil {
+		var zero T
+		return zero, nil
+	}
+	typed, ok := value.(T)
+	if !ok {
+		return typed, NewValueTypeError(fmt.Sprintf("loaded value of type %T is not a %s", value, TypeOf[T]()))
+	}
+	return typed, nil
+}
+
+// Occurs when a loaded value doesn't match the requested Go type.
+type ValueTypeError struct {
+	msg string
+}
+
+func (e *ValueTypeError) Error() string {
+	return e.msg
+}
+
+func NewValueTypeError(msg string) error {
+	return &ValueTypeError{msg: msg}
+}
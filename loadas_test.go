@@ -0,0 +1,38 @@
+package dataloaders
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestLoadAs(t *testing.T) {
+	db := newAccountDB(&account{ID: 1, Email: "a"})
+	l := ObjectOf[*account](NewRegistryBuilder()).Attr("id", db.fetcher("id")).Build()
+	ctx := context.Background()
+	if a, err := LoadAs[*account](ctx, l, "id", 1); err != nil || a.ID != 1 {
+		t.Fatalf("got %v, %v", a, err)
+	}
+	// missing values are returned as the zero value
+	accounts, errs := LoadAllAs[*account](ctx, l, "id", []Key{1, 2})
+	if anyError(errs) || accounts[0].ID != 1 || accounts[1] != nil {
+		t.Fatalf("got %v, %v", accounts, errs)
+	}
+	if _, err := LoadAs[account](ctx, l, "id", 1); !errors.Is(err, &ObjTypeNotRegError{}) {
+		t.Fatalf("got %v, want an ObjTypeNotRegError of the unregistered type", err)
+	}
+}
+
+func TestLoadAsValueTypeError(t *testing.T) {
+	db := newAccountDB(&account{ID: 1, Email: "a"})
+	l := NewObjAttrDataLoader(ObjAttrDataLoaderInits{})
+	// the loader of users returns accounts
+	RegisterTypeOf[*user](l, db.loader)
+	var typeErr *ValueTypeError
+	if _, err := LoadAs[*user](context.Background(), l, "id", 1); !errors.As(err, &typeErr) {
+		t.Fatalf("got %v, want a ValueTypeError", err)
+	}
+	if _, errs := LoadAllAs[*user](context.Background(), l, "id", []Key{1}); !errors.As(errs[0], &typeErr) {
+		t.Fatalf("got %v, want a ValueTypeError", errs)
+	}
+}
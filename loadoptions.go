@@ -0,0 +1,90 @@
+package dataloaders
+
+import (
+	"context"
+	"time"
+)
+
+// LoadOption configures a single load.
+type LoadOption func(*loadOptions)
+
+type loadOptions struct {
+	// neither read nor write the cache
+	noCache bool
+	// don't read the cache but write the result
+	refresh bool
+	// how long to wait for the result, 0 = no limit
+	timeout time.Duration
+	// don't wait for other keys of the batch
+	immediate bool
+	// fetch keys concurrently without batching instead of in the calling goroutine,
+	// set by LoadAllThunk
+	concurrent bool
+	// added to the metadata of the batch, see BatchMeta
+	meta interface{}
+}
+
+func newLoadOptions(opts []LoadOption) *loadOptions {
+	o := &loadOptions{}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
+
+// NoCache loads the key from the fetcher without reading or writing the cache.
+func NoCache() LoadOption {
+	return func(o *loadOptions) {
+		o.noCache = true
+	}
+}
+
+// ForceRefresh loads the key from the fetcher even if it is cached
+// and caches the new result.
+func ForceRefresh() LoadOption {
+	return func(o *loadOptions) {
+		o.refresh = true
+	}
+}
+
+// WithTimeout returns context.DeadlineExceeded for the load
+// if its result is not available within d, without canceling the batch
+// for other loads waiting for it. Keys of LoadAll fetched in time keep their values.
+func WithTimeout(d time.Duration) LoadOption {
+	return func(o *loadOptions) {
+		o.timeout = d
+	}
+}
+
+// Immediate fetches the key without waiting for the batch wait duration:
+// a key missing the cache is fetched alone right away, a key already collected
+// in the current batch sends that batch immediately, and a key being fetched joins that fetch.
+// The result is cached as usual.
+func Immediate() LoadOption {
+	return func(o *loadOptions) {
+		o.immediate = true
+	}
+}
+
+// WithMeta attaches metadata, like a tenant or locale, to the batch the key is fetched in.
+// The fetcher gets the metadata of all loads of the batch with BatchMeta.
+// Loads served from the cache don't add their metadata to any batch.
+func WithMeta(meta interface{}) LoadOption {
+	return func(o *loadOptions) {
+		o.meta = meta
+	}
+}
+
+// bypassCache returns true if the cache must not be read.
+func (o *loadOptions) bypassCache() bool {
+	return o.noCache || o.refresh
+}
+
+// context applies the timeout to ctx.
+// The returned release function must be called once the load returned.
+func (o *loadOptions) context(ctx context.Context) (context.Context, func()) {
+	if o.timeout <= 0 {
+		return ctx, func() {}
+	}
+	return context.WithTimeout(ctx, o.timeout)
+}
@@ -0,0 +1,138 @@
+package dataloaders
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+)
+
+// versionFetcher returns the number of the fetch as the value of every key.
+type versionFetcher struct {
+	mu      sync.Mutex
+	version int
+}
+
+func (f *versionFetcher) fetcher(ctx context.Context, keys []int) ([]int, []error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.version++
+	values := make([]int, len(keys))
+	for i := range keys {
+		values[i] = f.version
+	}
+	return values, nil
+}
+
+func TestLoadOptionsCache(t *testing.T) {
+	f := &versionFetcher{}
+	l := NewTyped(f.fetcher, WithSynchronous())
+	ctx := context.Background()
+	var versions []int
+	for _, opts := range [][]LoadOption{nil, {NoCache()}, nil, {ForceRefresh()}, nil} {
+		v, err := l.Load(ctx, 1, opts...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		versions = append(versions, v)
+	}
+	// NoCache neither reads nor writes the cache, ForceRefresh only writes it
+	if fmt.Sprint(versions) != "[1 2 1 3 3]" {
+		t.Fatalf("loaded versions %v, want [1 2 1 3 3]", versions)
+	}
+}
+
+func TestLoadOptionsTimeout(t *testing.T) {
+	release := make(chan struct{})
+	l := NewTyped(func(ctx context.Context, keys []int) ([]int, []error) {
+		<-release
+		return keys, nil
+	}, WithWait(time.Millisecond))
+	ctx := context.Background()
+	waiting := l.LoadThunk(ctx, 1)
+	if _, err := l.Load(ctx, 1, WithTimeout(5*time.Millisecond)); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("got %v, want %v", err, context.DeadlineExceeded)
+	}
+	// the batch is not canceled for the load still waiting for it
+	close(release)
+	if v, err := waiting(); err != nil || v != 1 {
+		t.Fatalf("got %d, %v", v, err)
+	}
+}
+
+func TestBatchMeta(t *testing.T) {
+	clock := NewFakeClock(time.Now())
+	var meta []interface{}
+	l := NewTyped(func(ctx context.Context, keys []int) ([]int, []error) {
+		meta = BatchMeta(ctx)
+		return keys, nil
+	}, WithClock(clock))
+	ctx := context.Background()
+	first := l.LoadThunk(ctx, 1, WithMeta("a"))
+	second := l.LoadThunk(ctx, 2)
+	third := l.LoadThunk(ctx, 3, WithMeta("b"))
+	clock.Advance(defaultWait)
+	for _, thunk := range []func() (int, error){first, second, third} {
+		thunk()
+	}
+	// loads without metadata add none
+	if fmt.Sprint(meta) != "[a b]" {
+		t.Fatalf("batch metadata %v, want [a b]", meta)
+	}
+	if v, err := l.LoadWithMeta(ctx, 1, "c"); err != nil || v != 1 || len(meta) != 2 {
+		t.Fatalf("cached key: got %d, %v with batch metadata %v", v, err, meta)
+	}
+}
+
+func TestLoadWithMetaKeepsCallerOptions(t *testing.T) {
+	l := NewTyped(echoFetcher, WithSynchronous())
+	opts := make([]LoadOption, 1, 2)
+	opts[0] = NoCache()
+	if _, err := l.LoadWithMeta(context.Background(), 1, "a", opts...); err != nil {
+		t.Fatal(err)
+	}
+	if opts[:2][1] != nil {
+		t.Fatal("LoadWithMeta wrote into the caller's options")
+	}
+}
+
+func TestLoadImmediate(t *testing.T) {
+	f := newCountingFetcher(echo)
+	l := NewTyped(f.fetcher, WithWait(time.Hour))
+	ctx := context.Background()
+
+	// fetched alone
+	if v, err := l.LoadImmediate(ctx, 1); err != nil || v != 1 {
+		t.Fatalf("got %v, %v, want 1", v, err)
+	}
+	// sends the batch the key is collected in
+	second := l.LoadThunk(ctx, 2)
+	l.LoadThunk(ctx, 3)
+	if v, err := l.LoadImmediate(ctx, 2); err != nil || v != 2 {
+		t.Fatalf("got %v, %v, want 2", v, err)
+	}
+	if _, err := second(); err != nil {
+		t.Fatal(err)
+	}
+	// cached as usual
+	if v, err := l.LoadImmediate(ctx, 1); err != nil || v != 1 {
+		t.Fatalf("got %v, %v, want 1", v, err)
+	}
+	if sizes := f.batchSizes(); len(sizes) != 2 || sizes[0] != 1 || sizes[1] != 2 {
+		t.Fatalf("batch sizes %v, want [1 2]", sizes)
+	}
+}
+
+func TestLoadImmediateKeepsCallerOptions(t *testing.T) {
+	l := NewTyped(echoFetcher, WithSynchronous())
+	opts := make([]LoadOption, 1, 2)
+	opts[0] = NoCache()
+	if _, err := l.LoadImmediate(context.Background(), 1, opts...); err != nil {
+		t.Fatal(err)
+	}
+	if opts[:2][1] != nil {
+		t.Fatal("LoadImmediate wrote into the caller's options")
+	}
+}
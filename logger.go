@@ -0,0 +1,95 @@
+package dataloaders
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+)
+
+// Logger is the minimal structured logger the loaders log operational events to,
+// see WithLogger. The args are alternating keys and values like in log/slog.
+// *slog.Logger implements Logger (see NewSlogLogger),
+// other loggers like zap or logrus are adapted by implementing the two methods.
+type Logger interface {
+	// Debug logs frequent events, like dispatched batches and evicted values.
+	Debug(msg string, args ...interface{})
+	// Error logs failures, like failed fetches and panicked propagators.
+	Error(msg string, args ...interface{})
+}
+
+// NewSlogLogger returns a Logger logging to logger, or slog.Default() if nil.
+func NewSlogLogger(logger *slog.Logger) Logger {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return logger
+}
+
+// WithLogger logs the dispatched, done and failed batches of the DataLoader
+// and the expired values evicted by its Janitor to logger (see LogHooks).
+func WithLogger(logger Logger) Option {
+	return func(o *options) {
+		o.logger = logger
+	}
+}
+
+// LogHooks returns hooks logging dispatched and done batches at debug level
+// and failed fetches at error level to logger.
+func LogHooks[K comparable, V any](logger Logger) TypedHooks[K, V] {
+	return TypedHooks[K, V]{
+		OnBatchDispatch: func(ctx context.Context, keys []K) {
+			logger.Debug("dataloaders: batch dispatched", "keys", len(keys))
+		},
+		OnBatchDone: func(ctx context.Context, keys []K, duration time.Duration, errs []error) {
+			logger.Debug("dataloaders: batch done", "keys", len(keys), "duration", duration)
+		},
+		OnFetchError: func(ctx context.Context, keys []K, errs []error) {
+			logger.Error("dataloaders: fetch failed", "keys", len(keys), "error", errors.Join(errs...))
+		},
+	}
+}
+
+// SetLogger logs the errors and panics of the propagators of loaded values to logger,
+// in addition to the handler set with OnPropagatorError. Nil disables logging.
+func (l *AttrDataLoader) SetLogger(logger Logger) *AttrDataLoader {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.logger = logger
+	return l
+}
+
+// SetLogger logs the errors and panics of the propagators registered with AddPropagators
+// to logger, see AttrDataLoader.SetLogger.
+func (l *ObjAttrDataLoader) SetLogger(logger Logger) *ObjAttrDataLoader {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.logger = logger
+	return l
+}
+
+// Logger sets the logger of the built ObjAttrDataLoader, the AttrDataLoaders of all
+// object types and all their loaders, see WithLogger and AttrDataLoader.SetLogger.
+func (b *RegistryBuilder) Logger(logger Logger) *RegistryBuilder {
+	b.logger = logger
+	return b
+}
+
+// logPropagatorError logs the joined errors of the propagators of value,
+// panics separately with their stack trace.
+func logPropagatorError(logger Logger, err error, args ...interface{}) {
+	var errs []error
+	if joined, ok := err.(interface{ Unwrap() []error }); ok {
+		errs = joined.Unwrap()
+	} else {
+		errs = []error{err}
+	}
+	for _, err := range errs {
+		var panicErr *PropagatorPanicError
+		if errors.As(err, &panicErr) {
+			logger.Error("dataloaders: propagator panicked", append(args, "panic", panicErr.Recovered, "stack", string(panicErr.Stack))...)
+		} else {
+			logger.Error("dataloaders: propagator failed", append(args, "error", err)...)
+		}
+	}
+}
@@ -0,0 +1,69 @@
+package dataloaders
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// recordingLogger records the logged messages.
+type recordingLogger struct {
+	mu   sync.Mutex
+	msgs []string
+}
+
+func (r *recordingLogger) Debug(msg string, args ...interface{}) { r.log(msg) }
+func (r *recordingLogger) Error(msg string, args ...interface{}) { r.log(msg) }
+
+func (r *recordingLogger) log(msg string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.msgs = append(r.msgs, msg)
+}
+
+func (r *recordingLogger) logged() string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return strings.Join(r.msgs, "\n")
+}
+
+func TestLogger(t *testing.T) {
+	logger := &recordingLogger{}
+	db := newAccountDB(&account{ID: 1, Email: "a"})
+	l := NewRegistryBuilder().
+		Logger(logger).
+		Object("account").
+		Attr("id", db.fetcher("id")).
+		Attr("email", func(ctx context.Context, keys []Key) ([]Value, []error) {
+			return nil, []error{errors.New("boom")}
+		}).
+		PropagateFrom("id", func(v Value, l *AttrDataLoader) error { panic("oops") }).
+		PropagateObjects(func(v Value, attribute Attribute, l *ObjAttrDataLoader) error {
+			return errors.New("boom")
+		}).
+		Build()
+	ctx := context.Background()
+	l.Load(ctx, "account", "email", "a")
+	l.Load(ctx, "account", "id", 1)
+
+	logged := logger.logged()
+	for _, msg := range []string{
+		"dataloaders: batch dispatched",
+		"dataloaders: batch done",
+		"dataloaders: fetch failed",
+		"dataloaders: propagator panicked",
+		"dataloaders: propagator failed",
+	} {
+		if !strings.Contains(logged, msg) {
+			t.Fatalf("%q not logged in:\n%s", msg, logged)
+		}
+	}
+}
+
+func TestNewSlogLogger(t *testing.T) {
+	if NewSlogLogger(nil) == nil {
+		t.Fatal("got nil logger, want slog.Default()")
+	}
+}
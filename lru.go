@@ -0,0 +1,139 @@
+package dataloaders
+
+import (
+	"container/list"
+	"sync"
+)
+
+// NewLRUCache creates a cache holding at most size values.
+// When full, the least recently used value is evicted.
+func NewLRUCache[K comparable, V any](size int) TypedCache[K, V] {
+	return &lruCache[K, V]{
+		size:  size,
+		items: map[K]*list.Element{},
+		order: list.New(),
+	}
+}
+
+// NewWeightedLRUCache creates a cache holding values weighing at most maxCost in total,
+// e.g. their approximate size in bytes. When full, the least recently used values are evicted.
+// Values weighing more than maxCost are not cached and remove the value cached at their key.
+func NewWeightedLRUCache[K comparable, V any](maxCost int64, weigher func(key K, value V) int64) TypedCache[K, V] {
+	return &lruCache[K, V]{
+		maxCost: maxCost,
+		weigher: weigher,
+		items:   map[K]*list.Element{},
+		order:   list.New(),
+	}
+}
+
+type lruCache[K comparable, V any] struct {
+	// the maximum number of values, 0 = no limit
+	size int
+	// the maximum total cost of the values weighed by weigher, 0 = no limit
+	maxCost int64
+	weigher func(K, V) int64
+	// the total cost of the values
+	cost int64
+
+	// the elements of order by key
+	items map[K]*list.Element
+	// the entries from most to least recently used
+	order *list.List
+	// called with the keys of the values evicted to make room, see evicter
+	evicted func(key K)
+
+	mu sync.Mutex
+}
+
+// evicter is implemented by caches evicting values on their own while setting others.
+type evicter[K comparable] interface {
+	// onEvict sets the function called with the key of every evicted value,
+	// from within Set with the lock of the cache held.
+	onEvict(fn func(key K))
+}
+
+func (c *lruCache[K, V]) onEvict(fn func(key K)) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.evicted = fn
+}
+
+type lruEntry[K comparable, V any] struct {
+	key   K
+	value V
+	// the weight of value
+	cost int64
+}
+
+func (c *lruCache[K, V]) Get(key K) (V, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if e, ok := c.items[key]; ok {
+		c.order.MoveToFront(e)
+		return e.Value.(*lruEntry[K, V]).value, true
+	}
+	var zero V
+	return zero, false
+}
+
+func (c *lruCache[K, V]) Set(key K, value V) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	var cost int64
+	if c.weigher != nil {
+		cost = c.weigher(key, value)
+	}
+	// too heavy to be cached, without evicting all other values first
+	if c.maxCost > 0 && cost > c.maxCost {
+		if e, ok := c.items[key]; ok {
+			c.removeElement(e)
+		}
+		return
+	}
+	if e, ok := c.items[key]; ok {
+		entry := e.Value.(*lruEntry[K, V])
+		c.cost += cost - entry.cost
+		entry.value, entry.cost = value, cost
+		c.order.MoveToFront(e)
+	} else {
+		c.items[key] = c.order.PushFront(&lruEntry[K, V]{key: key, value: value, cost: cost})
+		c.cost += cost
+	}
+	for c.size > 0 && c.order.Len() > c.size || c.maxCost > 0 && c.cost > c.maxCost {
+		e := c.order.Back()
+		c.removeElement(e)
+		if c.evicted != nil {
+			c.evicted(e.Value.(*lruEntry[K, V]).key)
+		}
+	}
+}
+
+func (c *lruCache[K, V]) Delete(key K) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if e, ok := c.items[key]; ok {
+		c.removeElement(e)
+	}
+}
+
+func (c *lruCache[K, V]) Clear() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.items = map[K]*list.Element{}
+	c.order.Init()
+	c.cost = 0
+}
+
+func (c *lruCache[K, V]) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.order.Len()
+}
+
+func (c *lruCache[K, V]) removeElement(e *list.Element) {
+	c.order.Remove(e)
+	entry := e.Value.(*lruEntry[K, V])
+	delete(c.items, entry.key)
+	c.cost -= entry.cost
+}
@@ -0,0 +1,64 @@
+package dataloaders
+
+import (
+	"context"
+	"testing"
+)
+
+func TestLRUCacheEvictsLeastRecentlyUsed(t *testing.T) {
+	c := NewLRUCache[int, int](2)
+	c.Set(1, 1)
+	c.Set(2, 2)
+	c.Get(1)
+	c.Set(3, 3)
+	if _, ok := c.Get(2); ok {
+		t.Fatal("least recently used value not evicted")
+	}
+	if n := c.Len(); n != 2 {
+		t.Fatalf("cached %d values, want 2", n)
+	}
+}
+
+func TestWithCacheSize(t *testing.T) {
+	f := newCountingFetcher(echo)
+	l := NewTyped(f.fetcher, WithCacheSize(1), WithSynchronous())
+	ctx := context.Background()
+	for _, key := range []int{1, 2, 1} {
+		l.Load(ctx, key)
+	}
+	if n := f.fetches(1); n != 2 {
+		t.Fatalf("fetched evicted key %d times, want 2", n)
+	}
+}
+
+func TestWeightedLRUCacheTooHeavy(t *testing.T) {
+	c := NewWeightedLRUCache[int, int](10, func(key, value int) int64 { return int64(value) })
+	c.Set(1, 4)
+	c.Set(2, 4)
+	c.Set(2, 11)
+	if _, ok := c.Get(1); !ok {
+		t.Fatal("too heavy value evicted other values")
+	}
+	if v, ok := c.Get(2); ok {
+		t.Fatalf("cached too heavy value or kept the replaced value %d", v)
+	}
+	if n := c.Len(); n != 1 {
+		t.Fatalf("cached %d values, want 1", n)
+	}
+}
+
+func TestWeightedLRUCacheEvictsLeastRecentlyUsed(t *testing.T) {
+	c := NewWeightedLRUCache[int, int](10, func(key, value int) int64 { return int64(value) })
+	c.Set(1, 4)
+	c.Set(2, 4)
+	c.Get(1)
+	c.Set(3, 4)
+	if _, ok := c.Get(2); ok {
+		t.Fatal("least recently used value not evicted")
+	}
+	for _, key := range []int{1, 3} {
+		if _, ok := c.Get(key); !ok {
+			t.Fatalf("value %d evicted", key)
+		}
+	}
+}
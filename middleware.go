@@ -0,0 +1,33 @@
+package dataloaders
+
+import "fmt"
+
+// FetcherMiddleware is the fetcher middleware of the untyped DataLoader.
+type FetcherMiddleware = TypedFetcherMiddleware[Key, Value]
+
+// TypedFetcherMiddleware wraps a fetcher, e.g. to log, time or sort the fetched keys.
+type TypedFetcherMiddleware[K comparable, V any] func(next TypedFetcher[K, V]) TypedFetcher[K, V]
+
+// WithFetcherMiddleware wraps the fetcher of the DataLoader with the middleware.
+// The first middleware is the outermost one, called first for every fetch.
+// Retries (see WithRetry) call the middleware again.
+// The key and value types of the middleware must match the ones of the DataLoader.
+func WithFetcherMiddleware[K comparable, V any](mw ...TypedFetcherMiddleware[K, V]) Option {
+	return func(o *options) {
+		for _, m := range mw {
+			o.middleware = append(o.middleware, m)
+		}
+	}
+}
+
+// wrapFetcher wraps fetch with the middleware configured by WithFetcherMiddleware.
+func wrapFetcher[K comparable, V any](fetch TypedFetcher[K, V], o *options) TypedFetcher[K, V] {
+	for i := len(o.middleware) - 1; i >= 0; i-- {
+		mw, ok := o.middleware[i].(TypedFetcherMiddleware[K, V])
+		if !ok {
+			panic(fmt.Sprintf("dataloaders: fetcher middleware %T does not match the DataLoader's key and value types", o.middleware[i]))
+		}
+		fetch = mw(fetch)
+	}
+	return fetch
+}
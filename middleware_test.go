@@ -0,0 +1,40 @@
+package dataloaders
+
+import (
+	"context"
+	"fmt"
+	"testing"
+)
+
+func TestWithFetcherMiddleware(t *testing.T) {
+	var calls []string
+	record := func(name string) TypedFetcherMiddleware[int, int] {
+		return func(next TypedFetcher[int, int]) TypedFetcher[int, int] {
+			return func(ctx context.Context, keys []int) ([]int, []error) {
+				calls = append(calls, name)
+				return next(ctx, keys)
+			}
+		}
+	}
+	l := NewTyped(func(ctx context.Context, keys []int) ([]int, []error) {
+		calls = append(calls, "fetch")
+		return keys, nil
+	}, WithSynchronous(), WithFetcherMiddleware(record("outer"), record("inner")))
+	if v, err := l.Load(context.Background(), 1); err != nil || v != 1 {
+		t.Fatalf("got %d, %v", v, err)
+	}
+	if fmt.Sprint(calls) != "[outer inner fetch]" {
+		t.Fatalf("called %v, want [outer inner fetch]", calls)
+	}
+}
+
+func TestWithFetcherMiddlewareTypeMismatch(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Fatal("no panic for middleware of other types")
+		}
+	}()
+	NewTyped(echoFetcher, WithFetcherMiddleware[string, int](func(next TypedFetcher[string, int]) TypedFetcher[string, int] {
+		return next
+	}))
+}
@@ -1,8 +1,10 @@
 package dataloaders
 
 import (
+	"context"
 	"fmt"
 	"sync"
+	"time"
 )
 
 func NewObjAttrDataLoader(initLoaders ObjAttrDataLoaderInits) *ObjAttrDataLoader {
@@ -22,6 +24,17 @@ type ObjAttrDataLoader struct {
 	// The loaders & caches.
 	loaders ObjAttrDataLoaders
 
+	// Run for the values loaded for an object type, see ObjValuePropagator.
+	propagators map[ObjectType][]ObjValuePropagator
+
+	// Called with the errors of propagators, may be nil.
+	onPropagatorError func(value Value, objectType ObjectType, attribute Attribute, err error)
+	// Logs the errors of propagators, may be nil, see SetLogger.
+	logger Logger
+
+	// Whether Close was called, no more loaders are initialized.
+	closed bool
+
 	// Mutex to prevent races.
 	mu sync.Mutex
 }
@@ -34,22 +47,44 @@ type ObjAttrDataLoaderInits map[ObjectType]func() *AttrDataLoader
 // AttributeDataLoaders map
 type ObjAttrDataLoaders map[ObjectType]*AttrDataLoader
 
-func (l *ObjAttrDataLoader) Load(objectType ObjectType, attribute Attribute, key Key) (Value, error) {
+func (l *ObjAttrDataLoader) Load(ctx context.Context, objectType ObjectType, attribute Attribute, key Key, opts ...LoadOption) (Value, error) {
 	if loader := l.loader(objectType); loader != nil {
-		return loader.Load(attribute, key)
+		value, err := loader.Load(ctx, attribute, key, opts...)
+		if err == nil {
+			l.RunPropagators(value, objectType, attribute)
+		}
+		return value, keyedAt(err, objectType, nil)
 	} else {
-		return nil, NewObjTypeNotRegError(fmt.Sprintf("no dataloader for objectType '%s' registered", objectType))
+		return nil, l.notRegError(objectType)
 	}
 }
 
-func (l *ObjAttrDataLoader) LoadAll(objectType ObjectType, attribute Attribute, keys []Key) ([]Value, []error) {
+func (l *ObjAttrDataLoader) LoadAll(ctx context.Context, objectType ObjectType, attribute Attribute, keys []Key, opts ...LoadOption) ([]Value, []error) {
 	if loader := l.loader(objectType); loader != nil {
-		return loader.LoadAll(attribute, keys)
+		values, errs := loader.LoadAll(ctx, attribute, keys, opts...)
+		for i, value := range values {
+			if i < len(errs) && errs[i] != nil {
+				errs[i] = keyedAt(errs[i], objectType, nil)
+				continue
+			}
+			l.RunPropagators(value, objectType, attribute)
+		}
+		return values, errs
 	} else {
-		return nil, []error{NewObjTypeNotRegError(fmt.Sprintf("no dataloader for objectType '%s' registered", objectType))}
+		return nil, []error{l.notRegError(objectType)}
 	}
 }
 
+// Refresh the value at key at attribute for objectType from the fetcher and cache the new value.
+func (l *ObjAttrDataLoader) Refresh(ctx context.Context, objectType ObjectType, attribute Attribute, key Key) (Value, error) {
+	return l.Load(ctx, objectType, attribute, key, ForceRefresh())
+}
+
+// RefreshMany refreshes the values at keys at attribute for objectType from the fetcher and caches the new values.
+func (l *ObjAttrDataLoader) RefreshMany(ctx context.Context, objectType ObjectType, attribute Attribute, keys []Key) ([]Value, []error) {
+	return l.LoadAll(ctx, objectType, attribute, keys, ForceRefresh())
+}
+
 // Prime the cache with the provided objectType, attribute, key and value.
 // If the key already exists, no change is made
 // and false is returned. Returns false if attribute not registered.
@@ -63,6 +98,25 @@ func (l *ObjAttrDataLoader) ForcePrime(objectType ObjectType, attribute Attribut
 	return l.prime(objectType, attribute, key, value, true)
 }
 
+// PrimeWithTTL primes the cache of attribute for objectType with a value expiring after ttl,
+// see DataLoader.PrimeWithTTL. Returns false if objectType or attribute not registered.
+func (l *ObjAttrDataLoader) PrimeWithTTL(objectType ObjectType, attribute Attribute, key Key, value Value, ttl time.Duration) bool {
+	if loader := l.loader(objectType); loader != nil {
+		return loader.PrimeWithTTL(attribute, key, value, ttl)
+	}
+	return false
+}
+
+// PrimeMany primes the cache of attribute for objectType with all provided keys and values.
+// Keys that already exist are not changed.
+// Returns the number of primed keys, 0 if objectType or attribute not registered.
+func (l *ObjAttrDataLoader) PrimeMany(objectType ObjectType, attribute Attribute, values map[Key]Value) int {
+	if loader := l.loader(objectType); loader != nil {
+		return loader.PrimeMany(attribute, values)
+	}
+	return 0
+}
+
 func (l *ObjAttrDataLoader) prime(objectType ObjectType, attribute Attribute, key Key, value Value, forcePrime bool) bool {
 	if loader := l.loader(objectType); loader != nil {
 		return loader.prime(attribute, key, value, forcePrime)
@@ -78,6 +132,53 @@ func (l *ObjAttrDataLoader) Clear(objectType ObjectType, attribute Attribute, ke
 	return l
 }
 
+// Clear the entire cache of all attributes of all object types.
+func (l *ObjAttrDataLoader) ClearAll() *ObjAttrDataLoader {
+	l.mu.Lock()
+	loaders := make([]*AttrDataLoader, 0, len(l.loaders))
+	for _, loader := range l.loaders {
+		loaders = append(loaders, loader)
+	}
+	l.mu.Unlock()
+	for _, loader := range loaders {
+		loader.ClearAll()
+	}
+	return l
+}
+
+// RegisterType registers the loader initializer of the object type after construction.
+// An already registered object type is replaced including its loaders and caches.
+// The replaced loaders are closed, waiting for their batches (see AttrDataLoader.Close).
+func (l *ObjAttrDataLoader) RegisterType(objectType ObjectType, init func() *AttrDataLoader) {
+	l.mu.Lock()
+	l.initLoaders[objectType] = init
+	replaced := l.loaders[objectType]
+	delete(l.loaders, objectType)
+	l.mu.Unlock()
+	if replaced != nil {
+		replaced.Close(context.Background())
+	}
+}
+
+// ObjectTypes returns the registered object types in no particular order.
+func (l *ObjAttrDataLoader) ObjectTypes() []ObjectType {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	objectTypes := make([]ObjectType, 0, len(l.initLoaders))
+	// initializers of initialized loaders are kept as nil
+	for objectType := range l.initLoaders {
+		objectTypes = append(objectTypes, objectType)
+	}
+	return objectTypes
+}
+
+// AttrLoader returns the attribute dataloader of the object type, initializing it if needed.
+// Returns false if the object type is not registered.
+func (l *ObjAttrDataLoader) AttrLoader(objectType ObjectType) (*AttrDataLoader, bool) {
+	loader := l.loader(objectType)
+	return loader, loader != nil
+}
+
 // Returns the dataloader of the objectType.
 // Initializes the dataloader if not exists and initializer is registered.
 func (l *ObjAttrDataLoader) loader(objectType ObjectType) *AttrDataLoader {
@@ -87,7 +188,7 @@ func (l *ObjAttrDataLoader) loader(objectType ObjectType) *AttrDataLoader {
 	if loader, exists := l.loaders[objectType]; exists {
 		return loader
 	} else { // Init if init func registered.
-		if loaderInit, exists := l.initLoaders[objectType]; exists {
+		if loaderInit, exists := l.initLoaders[objectType]; exists && !l.closed {
 			// create loader
 			loader = loaderInit()
 			// remove init func, since no longer needed
@@ -102,28 +203,43 @@ func (l *ObjAttrDataLoader) loader(objectType ObjectType) *AttrDataLoader {
 	return nil
 }
 
+// Returns the error for loads of an object type without loader.
+func (l *ObjAttrDataLoader) notRegError(objectType ObjectType) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.closed {
+		return ErrClosed
+	}
+	return &ObjTypeNotRegError{
+		ObjectType: objectType,
+		msg:        fmt.Sprintf("no dataloader for objectType '%s' registered", objectType),
+	}
+}
+
 // Occurs when an unregistered object type is requested.
 type ObjTypeNotRegError struct {
-	msg string
+	// The requested object type, nil if created by NewObjTypeNotRegError.
+	ObjectType ObjectType
+	msg        string
 }
 
 func (e *ObjTypeNotRegError) Error() string {
 	return e.msg
 }
 
+// Is reports whether target is an *ObjTypeNotRegError of the same object type,
+// or of any object type if the ObjectType of target is nil, see AttrNotRegError.Is.
+func (e *ObjTypeNotRegError) Is(target error) bool {
+	t, ok := target.(*ObjTypeNotRegError)
+	return ok && (t.ObjectType == nil || t.ObjectType == e.ObjectType)
+}
+
 func NewObjTypeNotRegError(msg string) error {
 	return &ObjTypeNotRegError{msg: msg}
 }
 
-// Returns true if the error is occurred when running the loader to resolve data.
+// Returns true if the error is occurred when running the loader to resolve data, see IsFetchError.
+// Returns false for nil errors.
 func IsResolverError(err error) bool {
-	if err != nil {
-		switch err.(type) {
-		case *ObjTypeNotRegError:
-			return false
-		case *AttrNotRegError:
-			return false
-		}
-	}
-	return true
+	return IsFetchError(err)
 }
@@ -0,0 +1,70 @@
+package dataloaders
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+// objLoader returns an ObjAttrDataLoader of the accounts as object type "account".
+func (db *accountDB) objLoader() *ObjAttrDataLoader {
+	return NewObjAttrDataLoader(ObjAttrDataLoaderInits{
+		"account": db.loader,
+	})
+}
+
+func TestObjAttrClearAll(t *testing.T) {
+	db := newAccountDB(&account{ID: 1, Email: "a"})
+	l := db.objLoader()
+	ctx := context.Background()
+	l.Load(ctx, "account", "id", 1)
+	l.ClearAll()
+	l.Load(ctx, "account", "id", 1)
+	if n := db.fetches("id"); n != 2 {
+		t.Fatalf("fetched id %d times, want 2", n)
+	}
+}
+
+func TestRegisterType(t *testing.T) {
+	db := newAccountDB(&account{ID: 1, Email: "a"})
+	l := NewObjAttrDataLoader(ObjAttrDataLoaderInits{})
+	ctx := context.Background()
+	if _, err := l.Load(ctx, "account", "id", 1); !errors.Is(err, &ObjTypeNotRegError{}) {
+		t.Fatalf("got %v, want an ObjTypeNotRegError", err)
+	}
+	l.RegisterType("account", db.loader)
+	if _, err := l.Load(ctx, "account", "id", 1); err != nil {
+		t.Fatal(err)
+	}
+	if types := l.ObjectTypes(); len(types) != 1 || types[0] != "account" {
+		t.Fatalf("got object types %v, want [account]", types)
+	}
+	loader, ok := l.AttrLoader("account")
+	if !ok {
+		t.Fatal("no loader of a registered object type")
+	}
+	loader.Load(ctx, "id", 1)
+	if n := db.fetches("id"); n != 1 {
+		t.Fatalf("fetched id %d times, want the cache of the returned loader", n)
+	}
+	if _, ok := l.AttrLoader("order"); ok {
+		t.Fatal("got a loader of an unregistered object type")
+	}
+}
+
+func TestRegisterTypeClosesReplacedLoader(t *testing.T) {
+	db := newAccountDB(&account{ID: 1, Email: "a"})
+	l := NewObjAttrDataLoader(ObjAttrDataLoaderInits{"account": db.loader})
+	ctx := context.Background()
+	replaced, _ := l.AttrLoader("account")
+	if _, err := l.Load(ctx, "account", "id", 1); err != nil {
+		t.Fatal(err)
+	}
+	l.RegisterType("account", db.loader)
+	if _, err := replaced.Load(ctx, "id", 1); err != ErrClosed {
+		t.Fatalf("replaced loader: got %v, want %v", err, ErrClosed)
+	}
+	if _, err := l.Load(ctx, "account", "id", 1); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,68 @@
+package dataloaders
+
+import "errors"
+
+// ObjValuePropagator is like a ValuePropagator, but registered at the ObjAttrDataLoader
+// for the values loaded for an object type by any attribute, so it can prime the caches
+// of other object types. For example, loading an Order primes the Customer loader
+// with the customer embedded in the order:
+//
+// 	l.AddPropagators("order", func(v Value, attribute Attribute, l *ObjAttrDataLoader) error {
+// 		order := v.(*Order)
+// 		l.Prime("customer", "id", order.Customer.ID, order.Customer)
+// 		return nil
+// 	})
+//
+// It is executed after the propagators of the AttrDataLoader of the object type.
+// The returned error doesn't fail the load, it is passed to the handler set with OnPropagatorError.
+// Panics are recovered and reported as *PropagatorPanicError.
+type ObjValuePropagator func(loadedValue Value, attribute Attribute, l *ObjAttrDataLoader) error
+
+// AddPropagators registers propagators run for the values loaded for the object type.
+func (l *ObjAttrDataLoader) AddPropagators(objectType ObjectType, propagators ...ObjValuePropagator) *ObjAttrDataLoader {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.propagators == nil {
+		l.propagators = map[ObjectType][]ObjValuePropagator{}
+	}
+	// copy so running propagators keep their slice
+	prev := l.propagators[objectType]
+	all := make([]ObjValuePropagator, 0, len(prev)+len(propagators))
+	all = append(all, prev...)
+	l.propagators[objectType] = append(all, propagators...)
+	return l
+}
+
+// RunPropagators runs the propagators registered for the object type.
+// Returns the errors of all propagators joined, they are also passed to the OnPropagatorError handler.
+func (l *ObjAttrDataLoader) RunPropagators(value Value, objectType ObjectType, attribute Attribute) error {
+	l.mu.Lock()
+	propagators := l.propagators[objectType]
+	onError := l.onPropagatorError
+	logger := l.logger
+	l.mu.Unlock()
+
+	var errs []error
+	for _, propagator := range propagators {
+		errs = append(errs, safePropagate(attribute, func() error {
+			return propagator(value, attribute, l)
+		}))
+	}
+	err := errors.Join(errs...)
+	if err != nil && onError != nil {
+		onError(value, objectType, attribute, err)
+	}
+	if err != nil && logger != nil {
+		logPropagatorError(logger, err, "objectType", objectType, "attribute", attribute)
+	}
+	return err
+}
+
+// OnPropagatorError sets the handler called with the errors of the propagators
+// registered with AddPropagators for a loaded value.
+func (l *ObjAttrDataLoader) OnPropagatorError(fn func(value Value, objectType ObjectType, attribute Attribute, err error)) *ObjAttrDataLoader {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.onPropagatorError = fn
+	return l
+}
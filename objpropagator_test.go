@@ -0,0 +1,70 @@
+package dataloaders
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestObjPropagators(t *testing.T) {
+	type customer struct{ ID int }
+	type order struct {
+		ID       int
+		Customer *customer
+	}
+	var customerFetches int
+	l := NewRegistryBuilder().
+		Object("order").
+		Attr("id", func(ctx context.Context, keys []Key) ([]Value, []error) {
+			values := make([]Value, len(keys))
+			for i, key := range keys {
+				values[i] = &order{ID: key.(int), Customer: &customer{ID: 7}}
+			}
+			return values, nil
+		}).
+		PropagateObjects(func(v Value, attribute Attribute, l *ObjAttrDataLoader) error {
+			c := v.(*order).Customer
+			l.Prime("customer", "id", c.ID, c)
+			return nil
+		}).
+		Object("customer").
+		Attr("id", func(ctx context.Context, keys []Key) ([]Value, []error) {
+			customerFetches++
+			return make([]Value, len(keys)), nil
+		}).
+		Build()
+	ctx := context.Background()
+	if _, err := l.Load(ctx, "order", "id", 1); err != nil {
+		t.Fatal(err)
+	}
+	c, err := l.Load(ctx, "customer", "id", 7)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c == nil || c.(*customer).ID != 7 || customerFetches != 0 {
+		t.Fatalf("got %v after %d fetches, want the primed customer", c, customerFetches)
+	}
+}
+
+func TestObjPropagatorErrors(t *testing.T) {
+	boom := errors.New("boom")
+	l := NewObjAttrDataLoader(ObjAttrDataLoaderInits{
+		"user": func() *AttrDataLoader { return newAccountDB().loader() },
+	})
+	var handled error
+	l.AddPropagators("user",
+		func(v Value, attribute Attribute, l *ObjAttrDataLoader) error { return boom },
+		func(v Value, attribute Attribute, l *ObjAttrDataLoader) error { panic("oops") },
+	).OnPropagatorError(func(value Value, objectType ObjectType, attribute Attribute, err error) {
+		handled = err
+	})
+
+	err := l.RunPropagators(&account{ID: 1}, "user", "id")
+	var panicErr *PropagatorPanicError
+	if !errors.Is(err, boom) || !errors.As(err, &panicErr) {
+		t.Fatalf("got %v, want boom and the recovered panic", err)
+	}
+	if handled != err {
+		t.Fatalf("handled %v, want %v", handled, err)
+	}
+}
@@ -0,0 +1,421 @@
+package dataloaders
+
+import (
+	"fmt"
+	"time"
+)
+
+// Option configures a DataLoader.
+type Option func(*options)
+
+type options struct {
+	// how long to wait before sending a batch
+	wait time.Duration
+	// tunes the wait by the arrival rate, may be nil
+	adaptiveWait *adaptiveWait
+	// the maximum number of keys to send in one batch, 0 = no limit
+	maxBatch int
+	// fetch every key on its own, see WithoutBatching
+	unbatched bool
+	// fetch batches in the waiting goroutine, see WithSynchronous
+	synchronous bool
+
+	// the TypedCache[K, V] matching the loader's key and value types
+	cache interface{}
+	// never cache values, see WithNoCache
+	noCache bool
+	// don't cache nil values, see WithSkipNilCache
+	skipNil bool
+	// the maximum number of cached values, 0 = no limit
+	cacheSize int
+	// the maximum total cost of the cached values, 0 = no limit
+	maxCost int64
+	// the func(K, V) int64 weighing the cached values
+	weigher interface{}
+	// how long cached values are valid, 0 = forever
+	ttl time.Duration
+	// whether accessing cached values extends their TTL
+	sliding bool
+	// how often expired values are evicted in the background, 0 = never
+	janitor time.Duration
+	// the number of shards of the default cache, 0 or 1 = not sharded
+	shards int
+	// the TypedCache[K, V] shared by many loaders behind the cache, may be nil
+	l2 interface{}
+	// distributes cleared keys, may be nil
+	invalidation *invalidationOptions
+
+	// called when the fetcher panicked
+	onPanic func(err *FetchPanicError)
+
+	// fail whole batches on invalid fetch results
+	strict bool
+
+	// how keys not found are handled
+	notFound NotFoundPolicy
+
+	// wrap fetch errors with their key, see WithKeyedErrors
+	keyedErrors bool
+
+	// the TypedHooks[K, V] matching the loader's key and value types
+	hooks []interface{}
+
+	// the func(K) K matching the loader's key type
+	keyFunc interface{}
+
+	// the func(V) interface{} matching the loader's value type
+	valueID interface{}
+
+	// the func(K, V) V matching the loader's key and value types
+	transform interface{}
+
+	// the func(V) V matching the loader's value type
+	cloner interface{}
+
+	// the TypedFetcherMiddleware[K, V] matching the loader's key and value types
+	middleware []interface{}
+	// the func(a, b K) int matching the loader's key type
+	sortKeys interface{}
+
+	// serve expired values while refetching them
+	staleWhileRevalidate bool
+
+	// how long per-key fetch errors are cached
+	errorTTL time.Duration
+
+	// how often and when to retry failed batches
+	retries int
+	backoff Backoff
+
+	// when to send a slow batch again, 0 = never
+	hedgeDelay time.Duration
+
+	// fails batches fast after consecutive failures
+	breaker *circuitBreaker
+
+	// limits the batches sent to the fetcher
+	limiter RateLimiter
+
+	// the maximum number of concurrent fetches, 0 = no limit
+	maxConcurrent int
+
+	// one fetch per key outstanding at a time, see WithSingleflight
+	singleflight bool
+
+	// the maximum number of keys in batches not yet done, 0 = no limit
+	maxPending    int
+	pendingPolicy PendingPolicy
+
+	// the source of time, see WithClock
+	clock Clock
+
+	// dump the batched keys, see WithDebug
+	debug bool
+
+	// logs batches and evictions, may be nil
+	logger Logger
+
+	// the name the stats are published under via expvar, empty = not published
+	expvarName string
+}
+
+// defaultWait is the batch wait duration if none is configured.
+const defaultWait = 16 * time.Millisecond
+
+func newOptions(opts []Option) *options {
+	o := &options{wait: defaultWait, clock: SystemClock}
+	for _, opt := range opts {
+		opt(o)
+	}
+	if o.breaker != nil {
+		o.breaker.clock = o.clock
+	}
+	if o.noCache {
+		// nothing is cached to expire or index
+		o.errorTTL = 0
+		o.valueID = nil
+		o.janitor = 0
+		if o.notFound == NotFoundCache {
+			o.notFound = NotFoundError
+		}
+	}
+	return o
+}
+
+// WithWait sets how long the DataLoader collects keys
+// before sending them as one batch to the fetcher (default 16ms).
+func WithWait(d time.Duration) Option {
+	return func(o *options) {
+		o.wait = d
+	}
+}
+
+// WithMaxBatch limits the number of keys sent to the fetcher in one batch.
+// A full batch is sent immediately without waiting. 0 = no limit (default).
+func WithMaxBatch(n int) Option {
+	return func(o *options) {
+		o.maxBatch = n
+	}
+}
+
+// WithCache sets the cache used by the DataLoader instead of the default map cache.
+// The key and value types of the cache must match the ones of the DataLoader.
+func WithCache[K comparable, V any](cache TypedCache[K, V]) Option {
+	return func(o *options) {
+		o.cache = cache
+	}
+}
+
+// WithoutBatching disables batching for fetchers without batch endpoint:
+// a load missing the cache fetches its key alone immediately in the calling goroutine,
+// without waiting for other keys. Concurrent loads of the same key still share the fetch.
+// LoadThunk blocks until the key is fetched and LoadAll fetches the keys concurrently,
+// limited by WithMaxConcurrentBatches.
+func WithoutBatching() Option {
+	return func(o *options) {
+		o.unbatched = true
+	}
+}
+
+// WithSynchronous makes batching deterministic for tests: batches have no wait duration
+// and are fetched in the goroutine of the first load waiting for them,
+// once it calls its thunk, or by Dispatch and Close. Keys loaded with LoadThunk
+// and LoadAllThunk before calling any thunk are fetched in one batch (split by WithMaxBatch).
+// No timers or goroutines are started for batches.
+func WithSynchronous() Option {
+	return func(o *options) {
+		o.synchronous = true
+	}
+}
+
+// WithNoCache disables caching: keys loaded concurrently are still batched and deduplicated,
+// but loaded values and errors are never stored, so every load is fetched fresh.
+// Prime has no effect and NotFoundCache behaves like NotFoundError.
+// It replaces a cache set with WithCache and WithL2Cache.
+func WithNoCache() Option {
+	return func(o *options) {
+		o.noCache = true
+	}
+}
+
+// WithSkipNilCache returns nil values fetched for keys, including keys not found
+// with NotFoundNil, without caching them, so the next load fetches them again,
+// e.g. to see rows created right after a first lookup missed them.
+// Values are nil if they are nil pointers, interfaces, maps, slices, channels or functions.
+func WithSkipNilCache() Option {
+	return func(o *options) {
+		o.skipNil = true
+	}
+}
+
+// WithCacheSize bounds the default cache to n values
+// by using a least recently used cache (see NewLRUCache).
+// It has no effect if a cache is set with WithCache.
+func WithCacheSize(n int) Option {
+	return func(o *options) {
+		o.cacheSize = n
+	}
+}
+
+// WithMaxCost bounds the default cache to values weighing maxCost in total,
+// e.g. their approximate size in bytes, by using a weighted least recently used cache
+// (see NewWeightedLRUCache). It replaces a size set with WithCacheSize.
+// It has no effect if a cache is set with WithCache.
+// The key and value types of weigher must match the ones of the DataLoader.
+func WithMaxCost[K comparable, V any](maxCost int64, weigher func(key K, value V) int64) Option {
+	return func(o *options) {
+		o.maxCost = maxCost
+		o.weigher = weigher
+	}
+}
+
+// WithTTL lets cached values expire d after they were loaded or primed,
+// so they are fetched again on the next load.
+func WithTTL(d time.Duration) Option {
+	return func(o *options) {
+		o.ttl = d
+	}
+}
+
+// WithSlidingExpiration extends the TTL of cached values every time they are loaded,
+// so only values not loaded for their TTL (see WithTTL and PrimeWithTTL) expire.
+func WithSlidingExpiration() Option {
+	return func(o *options) {
+		o.sliding = true
+	}
+}
+
+// WithL2Cache adds a second level cache behind the cache of the DataLoader,
+// e.g. Redis shared by many replicas (see NewTieredCache).
+// Values are written to the second level after the DataLoader released its lock,
+// so slow writes don't block other loads. Prime only checks the first level
+// for an existing value, not to read the second level with the lock held.
+// All other cache options only apply to the first level, stale values of
+// WithStaleWhileRevalidate are served from the first level if the second has none.
+// The key and value types of the cache must match the ones of the DataLoader.
+func WithL2Cache[K comparable, V any](cache TypedCache[K, V]) Option {
+	return func(o *options) {
+		o.l2 = cache
+	}
+}
+
+// WithShards splits the default cache into n shards selected by hashing the keys,
+// so highly concurrent loads don't contend on the lock of a single cache (see NewShardedCache).
+// A cache size set with WithCacheSize or WithMaxCost is divided evenly between the shards.
+// It has no effect if a cache is set with WithCache.
+func WithShards(n int) Option {
+	return func(o *options) {
+		o.shards = n
+	}
+}
+
+// WithOnPanic sets a function called when the fetcher panicked.
+// The panic is always recovered and returned as *FetchPanicError to every caller of the batch.
+func WithOnPanic(fn func(err *FetchPanicError)) Option {
+	return func(o *options) {
+		o.onPanic = fn
+	}
+}
+
+// WithStrict fails the whole batch with a *FetchResultError if the fetcher doesn't return
+// exactly one value per key and either no, one or one error per key.
+// Without strict mode only the keys without a returned value get the error.
+func WithStrict() Option {
+	return func(o *options) {
+		o.strict = true
+	}
+}
+
+// WithNotFound sets how keys the fetcher returned ErrNotFound for are handled
+// (default NotFoundNil).
+func WithNotFound(policy NotFoundPolicy) Option {
+	return func(o *options) {
+		o.notFound = policy
+	}
+}
+
+// WithStaleWhileRevalidate returns expired cached values immediately
+// while refetching them in the background to update the cache.
+// It requires a cache implementing TypedStaleCache, e.g. by using WithTTL.
+func WithStaleWhileRevalidate() Option {
+	return func(o *options) {
+		o.staleWhileRevalidate = true
+	}
+}
+
+// WithErrorCache caches the errors the fetcher returned for single keys for d,
+// so a failing key is not fetched on every load. Only errors returned in a list of one error
+// per key are cached, a single error fails the whole batch like the errors of the DataLoader
+// itself (e.g. ErrCircuitOpen) and is not cached, even for batches of a single key.
+// Context errors are not cached either. Cached errors are removed by Clear and ClearAll.
+func WithErrorCache(d time.Duration) Option {
+	return func(o *options) {
+		o.errorTTL = d
+	}
+}
+
+// WithKeyFunc sets a function mapping keys to the identity used for caching
+// and deduplicating keys within a batch, e.g. to stringify composite or
+// non-comparable keys. The fetcher still receives the original keys.
+// The key type of fn must match the one of the DataLoader.
+func WithKeyFunc[K comparable](fn func(key K) K) Option {
+	return func(o *options) {
+		o.keyFunc = fn
+	}
+}
+
+// newKeyFunc returns the function set by WithKeyFunc, nil if none.
+func newKeyFunc[K comparable](o *options) func(K) K {
+	if o.keyFunc == nil {
+		return nil
+	}
+	fn, ok := o.keyFunc.(func(K) K)
+	if !ok {
+		panic(fmt.Sprintf("dataloaders: key func %T does not match the DataLoader's key type", o.keyFunc))
+	}
+	return fn
+}
+
+// newCache returns the configured cache or the default one
+// in front of the configured second level cache.
+func newCache[K comparable, V any](o *options) TypedCache[K, V] {
+	if o.noCache {
+		return nopCache[K, V]{}
+	}
+	cache := newL1Cache[K, V](o)
+	if o.l2 == nil {
+		return cache
+	}
+	l2, ok := o.l2.(TypedCache[K, V])
+	if !ok {
+		panic(fmt.Sprintf("dataloaders: second level cache %T does not match the DataLoader's key and value types", o.l2))
+	}
+	return newWriteBehindCache(cache, l2)
+}
+
+// newL1Cache returns the configured cache or the default one.
+func newL1Cache[K comparable, V any](o *options) TypedCache[K, V] {
+	if o.cache != nil {
+		cache, ok := o.cache.(TypedCache[K, V])
+		if !ok {
+			panic(fmt.Sprintf("dataloaders: cache %T does not match the DataLoader's key and value types", o.cache))
+		}
+		return newTTLCache(cache, o.ttl, o.sliding, o.clock)
+	}
+	var weigher func(K, V) int64
+	if o.maxCost > 0 {
+		var ok bool
+		if weigher, ok = o.weigher.(func(K, V) int64); !ok {
+			panic(fmt.Sprintf("dataloaders: weigher %T does not match the DataLoader's key and value types", o.weigher))
+		}
+	}
+	if o.shards > 1 {
+		// round up so the shards hold at least cacheSize values and maxCost
+		size := (o.cacheSize + o.shards - 1) / o.shards
+		maxCost := (o.maxCost + int64(o.shards) - 1) / int64(o.shards)
+		return NewShardedCache(o.shards, func() TypedCache[K, V] {
+			return newExpiringCache(newDefaultCache(size, maxCost, weigher), o.ttl, o.sliding, o.clock)
+		})
+	}
+	return newExpiringCache(newDefaultCache(o.cacheSize, o.maxCost, weigher), o.ttl, o.sliding, o.clock)
+}
+
+// newDefaultCache returns a cache bounded to values weighing maxCost, if not 0,
+// or else to size values, if not 0.
+func newDefaultCache[K comparable, V any](size int, maxCost int64, weigher func(K, V) int64) TypedCache[K, V] {
+	switch {
+	case maxCost > 0:
+		return NewWeightedLRUCache(maxCost, weigher)
+	case size > 0:
+		return NewLRUCache[K, V](size)
+	default:
+		return NewMapCache[K, V]()
+	}
+}
+
+// newTTLCache wraps the cache so its values expire after ttl by clock, if not 0,
+// extending the TTL on access if sliding.
+func newTTLCache[K comparable, V any](cache TypedCache[K, V], ttl time.Duration, sliding bool, clock Clock) TypedCache[K, V] {
+	if !sliding && ttl <= 0 {
+		return cache
+	}
+	return newExpiringCache(cache, ttl, sliding, clock)
+}
+
+// newExpiringCache wraps the cache so it supports per-key TTLs (see PrimeWithTTL)
+// and its values expire after ttl by clock, if not 0, extending the TTL on access if sliding.
+// The default cache is always wrapped, as its values may be primed with a TTL.
+func newExpiringCache[K comparable, V any](cache TypedCache[K, V], ttl time.Duration, sliding bool, clock Clock) TypedCache[K, V] {
+	c := &ttlCache[K, V]{
+		inner:   cache,
+		ttl:     ttl,
+		sliding: sliding,
+		clock:   clock,
+		expires: map[K]expiry{},
+	}
+	if e, ok := cache.(evicter[K]); ok {
+		// evictions happen in inner.Set, called with c.mu held
+		e.onEvict(func(key K) { delete(c.expires, key) })
+	}
+	return c
+}
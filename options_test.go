@@ -0,0 +1,147 @@
+package dataloaders
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+)
+
+func TestWithMaxBatch(t *testing.T) {
+	f := newCountingFetcher(echo)
+	l := NewTyped(f.fetcher, WithMaxBatch(2))
+	values, errs := l.LoadAll(context.Background(), []int{1, 2, 3, 4, 5})
+	if anyError(errs) || !reflect.DeepEqual(values, []int{1, 2, 3, 4, 5}) {
+		t.Fatalf("got %v, %v", values, errs)
+	}
+	// the sub batches are fetched concurrently
+	sizes := f.batchSizes()
+	sort.Ints(sizes)
+	if !reflect.DeepEqual(sizes, []int{1, 2, 2}) {
+		t.Fatalf("batch sizes %v, want two of 2 and one of 1", sizes)
+	}
+}
+
+func TestWithWait(t *testing.T) {
+	clock := NewFakeClock(time.Now())
+	f := newCountingFetcher(echo)
+	l := NewTyped(f.fetcher, WithClock(clock), WithWait(time.Second))
+	thunk := l.LoadThunk(context.Background(), 1)
+	clock.Advance(time.Second - time.Millisecond)
+	if n := f.fetches(1); n != 0 {
+		t.Fatal("batch fetched before the wait duration")
+	}
+	clock.Advance(time.Millisecond)
+	if v, err := thunk(); err != nil || v != 1 {
+		t.Fatalf("got %d, %v", v, err)
+	}
+}
+
+func TestNoCacheNotFoundCache(t *testing.T) {
+	f := newCountingFetcher(func(keys []int) ([]int, []error) {
+		return nil, []error{ErrNotFound}
+	})
+	l := NewTyped(f.fetcher, WithNoCache(), WithNotFound(NotFoundCache))
+	ctx := context.Background()
+	for i := 0; i < 2; i++ {
+		if _, err := l.Load(ctx, 1); !errors.Is(err, ErrNotFound) {
+			t.Fatalf("load %d: got %v, want %v", i, err, ErrNotFound)
+		}
+	}
+	if n := f.fetches(1); n != 2 {
+		t.Fatalf("fetched missing key %d times, want 2", n)
+	}
+}
+
+func TestWithKeyFunc(t *testing.T) {
+	var fetched [][]Key
+	l := New(func(ctx context.Context, keys []Key) ([]Value, []error) {
+		fetched = append(fetched, keys)
+		values := make([]Value, len(keys))
+		for i, key := range keys {
+			values[i] = len(key.([]int))
+		}
+		return values, nil
+	}, WithSynchronous(), WithKeyFunc(func(key Key) Key { return fmt.Sprint(key) }))
+	ctx := context.Background()
+	// slices are not comparable, their stringified identities are
+	values, errs := l.LoadAll(ctx, []Key{[]int{1, 2}, []int{3}, []int{1, 2}})
+	if anyError(errs) || !reflect.DeepEqual(values, []Value{2, 1, 2}) {
+		t.Fatalf("got %v, %v", values, errs)
+	}
+	if v, err := l.Load(ctx, []int{3}); err != nil || v != 1 {
+		t.Fatalf("cached key: got %v, %v", v, err)
+	}
+	if !reflect.DeepEqual(fetched, [][]Key{{[]int{1, 2}, []int{3}}}) {
+		t.Fatalf("fetched %v, want the original keys once", fetched)
+	}
+}
+
+func TestWithSynchronous(t *testing.T) {
+	f := newCountingFetcher(echo)
+	l := NewTyped(f.fetcher, WithSynchronous(), WithMaxBatch(3))
+	ctx := context.Background()
+	first := l.LoadThunk(ctx, 1)
+	second := l.LoadThunk(ctx, 2)
+	all := l.LoadAllThunk(ctx, []int{3, 4, 5})
+	if sizes := f.batchSizes(); len(sizes) != 0 {
+		t.Fatalf("fetched batches %v before calling a thunk", sizes)
+	}
+	// the thunk fetches the batch of its key in the calling goroutine
+	if v, err := second(); err != nil || v != 2 {
+		t.Fatalf("got %d, %v", v, err)
+	}
+	if fmt.Sprint(f.batches) != "[[1 2 3]]" {
+		t.Fatalf("fetched batches %v, want [[1 2 3]]", f.batches)
+	}
+	first()
+	if values, _ := all(); fmt.Sprint(values) != "[3 4 5]" || fmt.Sprint(f.batches) != "[[1 2 3] [4 5]]" {
+		t.Fatalf("got %v after fetching batches %v", values, f.batches)
+	}
+
+	l.LoadThunk(ctx, 6)
+	l.Dispatch()
+	l.LoadThunk(ctx, 7)
+	l.Close(ctx)
+	if fmt.Sprint(f.batches) != "[[1 2 3] [4 5] [6] [7]]" {
+		t.Fatalf("fetched batches %v, want the batches dispatched and closed", f.batches)
+	}
+}
+
+func TestWithSkipNilCache(t *testing.T) {
+	var fetches int
+	l := NewTyped(func(ctx context.Context, keys []int) ([]*int, []error) {
+		fetches++
+		values := make([]*int, len(keys))
+		if fetches == 3 {
+			values[0] = &fetches
+		}
+		return values, nil
+	}, WithSynchronous(), WithSkipNilCache())
+	ctx := context.Background()
+	for i := 0; i < 4; i++ {
+		if _, err := l.Load(ctx, 1); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// nil twice, then cached
+	if fetches != 3 {
+		t.Fatalf("fetched %d times, want 3", fetches)
+	}
+}
+
+func TestIsNil(t *testing.T) {
+	for _, v := range []interface{}{nil, (*int)(nil), []int(nil), map[int]int(nil), (func())(nil)} {
+		if !isNil(v) {
+			t.Fatalf("%#v not nil", v)
+		}
+	}
+	for _, v := range []interface{}{0, "", []int{}, struct{}{}} {
+		if isNil(v) {
+			t.Fatalf("%#v nil", v)
+		}
+	}
+}
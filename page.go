@@ -0,0 +1,85 @@
+package dataloaders
+
+import "context"
+
+// PageArgs are the arguments selecting a page of children, e.g. of a GraphQL connection.
+type PageArgs struct {
+	// The maximum number of children.
+	First int
+	// The cursor of the child the page starts after, empty for the first page.
+	After string
+}
+
+// PageKey is the key of a page of the children of a parent.
+type PageKey[K comparable] struct {
+	Parent K
+	PageArgs
+}
+
+// Page is a loaded page of children.
+type Page[V any] struct {
+	Items []V
+	// The cursor of the last item, to load the next page after.
+	EndCursor string
+	// Whether there are more children after the page.
+	HasNextPage bool
+}
+
+// PageFetcher is the page fetch function of the untyped PageLoader.
+type PageFetcher = TypedPageFetcher[Key, Value]
+
+// TypedPageFetcher loads the pages of a batch of page keys, whose page arguments may differ.
+// The keys are usually fetched by one query per distinct page arguments, see GroupByPageArgs.
+// Keys missing in the map get an empty page.
+type TypedPageFetcher[K comparable, V any] func(ctx context.Context, keys []PageKey[K]) (map[PageKey[K]]Page[V], error)
+
+// Fetcher converts the page fetch function into a Fetcher
+// returning the pages in the order of the keys.
+// An error fails the whole batch.
+func (f TypedPageFetcher[K, V]) Fetcher() TypedFetcher[PageKey[K], Page[V]] {
+	return func(ctx context.Context, keys []PageKey[K]) ([]Page[V], []error) {
+		m, err := f(ctx, keys)
+		if err != nil {
+			return nil, []error{err}
+		}
+		pages := make([]Page[V], len(keys))
+		for i, key := range keys {
+			pages[i] = m[key]
+		}
+		return pages, nil
+	}
+}
+
+// GroupByPageArgs groups the parents of the page keys by their page arguments,
+// keeping the order of the keys.
+func GroupByPageArgs[K comparable](keys []PageKey[K]) map[PageArgs][]K {
+	groups := map[PageArgs][]K{}
+	for _, key := range keys {
+		groups[key.PageArgs] = append(groups[key.PageArgs], key.Parent)
+	}
+	return groups
+}
+
+// PageLoader is the untyped page loader.
+type PageLoader = TypedPageLoader[Key, Value]
+
+// TypedPageLoader is a DataLoader batching and caching pages of children by parent and page arguments,
+// e.g. the first N comments after a cursor of every post of a GraphQL query.
+type TypedPageLoader[K comparable, V any] struct {
+	*TypedDataLoader[PageKey[K], Page[V]]
+}
+
+// NewPageLoader creates a PageLoader configured by the given options.
+func NewPageLoader(fetch PageFetcher, opts ...Option) *PageLoader {
+	return NewTypedPageLoader(fetch, opts...)
+}
+
+// NewTypedPageLoader creates a PageLoader with typed parents and children configured by the given options.
+func NewTypedPageLoader[K comparable, V any](fetch TypedPageFetcher[K, V], opts ...Option) *TypedPageLoader[K, V] {
+	return &TypedPageLoader[K, V]{NewTyped(fetch.Fetcher(), opts...)}
+}
+
+// LoadPage loads the page of the children of parent selected by args.
+func (l *TypedPageLoader[K, V]) LoadPage(ctx context.Context, parent K, args PageArgs) (Page[V], error) {
+	return l.Load(ctx, PageKey[K]{Parent: parent, PageArgs: args})
+}
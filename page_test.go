@@ -0,0 +1,48 @@
+package dataloaders
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestPageLoader(t *testing.T) {
+	clock := NewFakeClock(time.Now())
+	var queries int
+	l := NewTypedPageLoader(func(ctx context.Context, keys []PageKey[int]) (map[PageKey[int]]Page[int], error) {
+		pages := map[PageKey[int]]Page[int]{}
+		for args, parents := range GroupByPageArgs(keys) {
+			queries++
+			for _, parent := range parents {
+				if parent == 3 {
+					continue
+				}
+				pages[PageKey[int]{Parent: parent, PageArgs: args}] = Page[int]{Items: make([]int, args.First), HasNextPage: true}
+			}
+		}
+		return pages, nil
+	}, WithClock(clock))
+	ctx := context.Background()
+	first := PageArgs{First: 2}
+	thunks := map[PageKey[int]]func() (Page[int], error){}
+	for _, key := range []PageKey[int]{{1, first}, {2, first}, {1, PageArgs{First: 1, After: "c"}}, {3, first}} {
+		thunks[key] = l.LoadThunk(ctx, key)
+	}
+	clock.Advance(defaultWait)
+	for key, thunk := range thunks {
+		want := Page[int]{Items: make([]int, key.First), HasNextPage: true}
+		if key.Parent == 3 {
+			// parents missing in the map get an empty page
+			want = Page[int]{}
+		}
+		if page, err := thunk(); err != nil || len(page.Items) != len(want.Items) || page.HasNextPage != want.HasNextPage {
+			t.Fatalf("%+v: got %+v, %v", key, page, err)
+		}
+	}
+	if queries != 2 {
+		t.Fatalf("queried %d times, want once per distinct page arguments", queries)
+	}
+	if page, err := l.LoadPage(ctx, 1, first); err != nil || len(page.Items) != 2 || queries != 2 {
+		t.Fatalf("cached page: got %+v, %v", page, err)
+	}
+}
@@ -0,0 +1,51 @@
+package dataloaders
+
+import "errors"
+
+// ErrTooManyPending is returned for loads rejected by WithMaxPending.
+var ErrTooManyPending = errors.New("too many pending keys")
+
+// PendingPolicy controls how a DataLoader handles loads
+// once the limit set with WithMaxPending is reached.
+type PendingPolicy int
+
+const (
+	// PendingBlock waits until fetched batches make room, or until the context of the load is done.
+	PendingBlock PendingPolicy = iota
+	// PendingReject fails the load fast with ErrTooManyPending.
+	PendingReject
+	// PendingSpill sends the current batch immediately and adds the key to a new batch,
+	// so keys don't pile up waiting for the batch wait duration.
+	PendingSpill
+)
+
+// WithMaxPending limits the number of keys in batches not yet done, i.e. collecting keys,
+// waiting to be fetched or being fetched, to n. Loads of keys missing the cache beyond
+// the limit are handled by policy, e.g. to fail fast during incidents instead of piling up
+// goroutines waiting for slow batches. Loads joining a batch of their key are not limited.
+// PendingBlock must not be used with WithSynchronous.
+func WithMaxPending(n int, policy PendingPolicy) Option {
+	return func(o *options) {
+		o.maxPending = n
+		o.pendingPolicy = policy
+	}
+}
+
+// drainedChan returns a channel closed once the pending keys decreased.
+// Must be called with l.mu held.
+func (l *TypedDataLoader[K, V]) drainedChan() chan struct{} {
+	if l.drained == nil {
+		l.drained = make(chan struct{})
+	}
+	return l.drained
+}
+
+// drain removes n done keys from the pending keys, waking up blocked loads.
+// Must be called with l.mu held.
+func (l *TypedDataLoader[K, V]) drain(n int) {
+	l.pendingKeys -= n
+	if l.drained != nil {
+		close(l.drained)
+		l.drained = nil
+	}
+}
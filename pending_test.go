@@ -0,0 +1,88 @@
+package dataloaders
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestMaxPendingReject(t *testing.T) {
+	release := make(chan struct{})
+	l := NewTyped(func(ctx context.Context, keys []int) ([]int, []error) {
+		<-release
+		return keys, nil
+	}, WithWait(time.Millisecond), WithMaxPending(2, PendingReject))
+	ctx := context.Background()
+
+	first := l.LoadThunk(ctx, 1)
+	second := l.LoadThunk(ctx, 2)
+	if _, err := l.LoadThunk(ctx, 3)(); err != ErrTooManyPending {
+		t.Fatalf("got %v, want %v", err, ErrTooManyPending)
+	}
+	// joins the batch of its key regardless of the limit
+	joined := l.LoadThunk(ctx, 1)
+	close(release)
+	for _, thunk := range []func() (int, error){first, second, joined} {
+		if _, err := thunk(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if v, err := l.Load(ctx, 3); err != nil || v != 3 {
+		t.Fatalf("got %v, %v, want 3 once drained", v, err)
+	}
+}
+
+func TestMaxPendingBlock(t *testing.T) {
+	release := make(chan struct{})
+	l := NewTyped(func(ctx context.Context, keys []int) ([]int, []error) {
+		<-release
+		return keys, nil
+	}, WithWait(time.Millisecond), WithMaxPending(1, PendingBlock))
+	ctx := context.Background()
+
+	first := l.LoadThunk(ctx, 1)
+	done := make(chan int)
+	go func() {
+		v, _ := l.Load(ctx, 2)
+		done <- v
+	}()
+	select {
+	case <-done:
+		t.Fatal("load not blocked")
+	case <-time.After(30 * time.Millisecond):
+	}
+	timeoutCtx, cancel := context.WithTimeout(ctx, 5*time.Millisecond)
+	defer cancel()
+	if _, err := l.Load(timeoutCtx, 3); err != context.DeadlineExceeded {
+		t.Fatalf("got %v, want %v", err, context.DeadlineExceeded)
+	}
+
+	close(release)
+	if _, err := first(); err != nil {
+		t.Fatal(err)
+	}
+	if v := <-done; v != 2 {
+		t.Fatalf("got %d, want 2", v)
+	}
+}
+
+func TestMaxPendingSpill(t *testing.T) {
+	f := newCountingFetcher(echo)
+	l := NewTyped(f.fetcher, WithWait(time.Hour), WithMaxPending(2, PendingSpill))
+	ctx := context.Background()
+
+	first := l.LoadThunk(ctx, 1)
+	l.LoadThunk(ctx, 2)
+	// sends the full batch
+	third := l.LoadThunk(ctx, 3)
+	if _, err := first(); err != nil {
+		t.Fatal(err)
+	}
+	l.Dispatch()
+	if _, err := third(); err != nil {
+		t.Fatal(err)
+	}
+	if sizes := f.batchSizes(); len(sizes) != 2 || sizes[0] != 2 || sizes[1] != 1 {
+		t.Fatalf("batch sizes %v, want [2 1]", sizes)
+	}
+}
@@ -0,0 +1,57 @@
+package dataloaders
+
+import "context"
+
+// Prefetch adds the keys missing the cache to batches without waiting for them,
+// e.g. to hint loads coming soon like the IDs of the next page.
+// The fetched values just land in the cache for the later loads.
+// Keys already being fetched are skipped, as are keys beyond the limit of WithMaxPending.
+// ctx only provides the values of the fetch context,
+// the fetch isn't canceled when ctx is done.
+func (l *TypedDataLoader[K, V]) Prefetch(ctx context.Context, keys ...K) {
+	if l.closed.Load() {
+		return
+	}
+	ctx = context.WithoutCancel(ctx)
+	// look up the cache, which may be remote, before taking l.mu
+	missing := make(map[K]K, len(keys))
+	for _, key := range keys {
+		id := l.id(key)
+		if _, ok := l.fresh(id); !ok {
+			missing[id] = key
+		}
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.closed.Load() {
+		return
+	}
+	for id, key := range missing {
+		if l.maxPending > 0 && l.pendingKeys >= l.maxPending {
+			return
+		}
+		if _, ok := l.negative(id); ok {
+			continue
+		}
+		if _, ok := l.cached(ctx, key, id); ok {
+			continue
+		}
+		l.revalidate(ctx, key, id)
+	}
+}
+
+// Prefetch adds the keys missing the cache of attribute to batches without waiting for them,
+// see DataLoader.Prefetch. Propagators are not run for prefetched values.
+func (l *AttrDataLoader) Prefetch(ctx context.Context, attribute Attribute, keys ...Key) {
+	if loader := l.loader(attribute); loader != nil {
+		loader.Prefetch(ctx, keys...)
+	}
+}
+
+// Prefetch adds the keys missing the cache of attribute for objectType to batches
+// without waiting for them, see DataLoader.Prefetch.
+func (l *ObjAttrDataLoader) Prefetch(ctx context.Context, objectType ObjectType, attribute Attribute, keys ...Key) {
+	if loader := l.loader(objectType); loader != nil {
+		loader.Prefetch(ctx, attribute, keys...)
+	}
+}
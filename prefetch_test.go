@@ -0,0 +1,31 @@
+package dataloaders
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestPrefetch(t *testing.T) {
+	f := newCountingFetcher(echo)
+	l := NewTyped(func(ctx context.Context, keys []int) ([]int, []error) {
+		if err := ctx.Err(); err != nil {
+			return nil, []error{err}
+		}
+		return f.fetcher(ctx, keys)
+	}, WithWait(5*time.Millisecond))
+	ctx, cancel := context.WithCancel(context.Background())
+	l.Prime(1, 1)
+	l.Prefetch(ctx, 1, 2, 3)
+	// doesn't cancel the prefetch
+	cancel()
+
+	for _, key := range []int{2, 3} {
+		if v, err := l.Load(context.Background(), key); err != nil || v != key {
+			t.Fatalf("got %v, %v, want %d", v, err, key)
+		}
+	}
+	if sizes := f.batchSizes(); len(sizes) != 1 || sizes[0] != 2 {
+		t.Fatalf("batch sizes %v, want [2]", sizes)
+	}
+}
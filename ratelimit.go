@@ -0,0 +1,34 @@
+package dataloaders
+
+import "context"
+
+// RateLimiter limits how often batches are sent to the fetcher.
+// It is implemented by *rate.Limiter of golang.org/x/time/rate.
+type RateLimiter interface {
+	// Wait blocks until a batch may be sent or returns an error if ctx is done first.
+	Wait(ctx context.Context) error
+}
+
+// WithFetchRateLimit limits the batches sent to the fetcher by limiter.
+// While a batch waits for the limiter it keeps collecting keys,
+// so bursts are fetched in fewer but larger batches (up to the max batch size).
+func WithFetchRateLimit(limiter RateLimiter) Option {
+	return func(o *options) {
+		o.limiter = limiter
+	}
+}
+
+// admit waits for the rate limiter before ending the batch.
+// It returns an error if the batch must fail instead.
+func (b *batch[K, V]) admit(l *TypedDataLoader[K, V], ctx context.Context) error {
+	if l.limiter == nil {
+		return nil
+	}
+	l.mu.Lock()
+	admitted := b.admitted
+	l.mu.Unlock()
+	if admitted {
+		return nil
+	}
+	return l.limiter.Wait(ctx)
+}
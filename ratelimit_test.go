@@ -0,0 +1,49 @@
+package dataloaders
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// gateLimiter admits batches once its gate is closed.
+type gateLimiter struct {
+	gate chan struct{}
+}
+
+func (g *gateLimiter) Wait(ctx context.Context) error {
+	select {
+	case <-g.gate:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func TestRateLimitMaxBatchWhileWaiting(t *testing.T) {
+	limiter := &gateLimiter{gate: make(chan struct{})}
+	var sizes []int
+	l := NewTyped(func(ctx context.Context, keys []int) ([]int, []error) {
+		sizes = append(sizes, len(keys))
+		return keys, nil
+	}, WithWait(time.Millisecond), WithMaxBatch(2), WithFetchRateLimit(limiter))
+	ctx := context.Background()
+
+	first := l.LoadThunk(ctx, 1)
+	// not synchronized with the limiter on purpose, so the race detector sees
+	// the wait of the batch racing with the batch being ended
+	time.Sleep(20 * time.Millisecond)
+	// fills the batch waiting for the limiter, ending it concurrently
+	second := l.LoadThunk(ctx, 2)
+	time.Sleep(20 * time.Millisecond)
+	close(limiter.gate)
+
+	for key, thunk := range map[int]func() (int, error){1: first, 2: second} {
+		if v, err := thunk(); err != nil || v != key {
+			t.Fatalf("key %d: got %d, %v", key, v, err)
+		}
+	}
+	if len(sizes) != 1 || sizes[0] != 2 {
+		t.Fatalf("batch sizes %v, want [2]", sizes)
+	}
+}
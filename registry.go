@@ -0,0 +1,124 @@
+package dataloaders
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// Registry holds loaders of any type by name, e.g. to wire them in dependency injection containers.
+// It is safe for concurrent use.
+type Registry struct {
+	loaders map[string]interface{}
+
+	mu sync.RWMutex
+}
+
+// NewRegistry creates an empty Registry.
+func NewRegistry() *Registry {
+	return &Registry{loaders: map[string]interface{}{}}
+}
+
+// Register adds the loader, e.g. a *TypedDataLoader[K, V], under name.
+// Returns a *LoaderDupRegError if a loader is already registered under name.
+func (r *Registry) Register(name string, loader interface{}) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, exists := r.loaders[name]; exists {
+		return NewLoaderDupRegError(fmt.Sprintf("dataloader '%s' already registered", name))
+	}
+	r.loaders[name] = loader
+	return nil
+}
+
+// MustRegister is like Register but panics if a loader is already registered under name.
+func (r *Registry) MustRegister(name string, loader interface{}) {
+	if err := r.Register(name, loader); err != nil {
+		panic(err)
+	}
+}
+
+// Get returns the loader registered under name.
+// Returns a *LoaderNotRegError if none is registered.
+func (r *Registry) Get(name string) (interface{}, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	loader, exists := r.loaders[name]
+	if !exists {
+		return nil, NewLoaderNotRegError(fmt.Sprintf("no dataloader '%s' registered", name))
+	}
+	return loader, nil
+}
+
+// Names returns the names of all registered loaders in ascending order.
+func (r *Registry) Names() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	names := make([]string, 0, len(r.loaders))
+	for name := range r.loaders {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Len returns the number of registered loaders.
+func (r *Registry) Len() int {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return len(r.loaders)
+}
+
+// Lookup returns the loader registered under name as L, e.g. *TypedDataLoader[int, *User].
+// Returns a *LoaderNotRegError if none is registered or a *LoaderTypeError if it is not an L.
+func Lookup[L any](r *Registry, name string) (L, error) {
+	var typed L
+	loader, err := r.Get(name)
+	if err != nil {
+		return typed, err
+	}
+	typed, ok := loader.(L)
+	if !ok {
+		return typed, NewLoaderTypeError(fmt.Sprintf("dataloader '%s' of type %T is not a %s", name, loader, TypeOf[L]()))
+	}
+	return typed, nil
+}
+
+// Occurs when a loader is registered under a name already in use.
+type LoaderDupRegError struct {
+	msg string
+}
+
+func (e *LoaderDupRegError) Error() string {
+	return e.msg
+}
+
+func NewLoaderDupRegError(msg string) error {
+	return &LoaderDupRegError{msg: msg}
+}
+
+// Occurs when an unregistered loader name is requested.
+type LoaderNotRegError struct {
+	msg string
+}
+
+func (e *LoaderNotRegError) Error() string {
+	return e.msg
+}
+
+func NewLoaderNotRegError(msg string) error {
+	return &LoaderNotRegError{msg: msg}
+}
+
+// Occurs when a registered loader doesn't match the requested Go type.
+type LoaderTypeError struct {
+	msg string
+}
+
+func (e *LoaderTypeError) Error() string {
+	return e.msg
+}
+
+func NewLoaderTypeError(msg string) error {
+	return &LoaderTypeError{msg: msg}
+}
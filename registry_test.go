@@ -0,0 +1,44 @@
+package dataloaders
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestRegistry(t *testing.T) {
+	r := NewRegistry()
+	l := NewTyped(echoFetcher)
+	r.MustRegister("users", l)
+	r.MustRegister("accounts", newAccountDB().loader())
+	var dupErr *LoaderDupRegError
+	if err := r.Register("users", l); !errors.As(err, &dupErr) {
+		t.Fatalf("got %v, want a LoaderDupRegError", err)
+	}
+	if names := r.Names(); fmt.Sprint(names) != "[accounts users]" || r.Len() != 2 {
+		t.Fatalf("got names %v", names)
+	}
+
+	if got, err := Lookup[*TypedDataLoader[int, int]](r, "users"); err != nil || got != l {
+		t.Fatalf("got %v, %v, want the registered loader", got, err)
+	}
+	var typeErr *LoaderTypeError
+	if _, err := Lookup[*DataLoader](r, "users"); !errors.As(err, &typeErr) {
+		t.Fatalf("got %v, want a LoaderTypeError", err)
+	}
+	var notRegErr *LoaderNotRegError
+	if _, err := r.Get("orders"); !errors.As(err, &notRegErr) {
+		t.Fatalf("got %v, want a LoaderNotRegError", err)
+	}
+}
+
+func TestRegistryMustRegisterDuplicate(t *testing.T) {
+	r := NewRegistry()
+	r.MustRegister("users", 1)
+	defer func() {
+		if recover() == nil {
+			t.Fatal("no panic registering a duplicate name")
+		}
+	}()
+	r.MustRegister("users", 2)
+}
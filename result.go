@@ -0,0 +1,82 @@
+package dataloaders
+
+import (
+	"context"
+	"sync"
+)
+
+// Result is the result of loading a key with the untyped DataLoader.
+type Result = TypedResult[Value]
+
+// TypedResult is the value or error of a loaded key.
+type TypedResult[V any] struct {
+	Value V
+	Err   error
+}
+
+// KeyedResult is the result of a key loaded with the untyped DataLoader.
+type KeyedResult = TypedKeyedResult[Key, Value]
+
+// TypedKeyedResult is the result of a loaded key.
+type TypedKeyedResult[K comparable, V any] struct {
+	Key K
+	TypedResult[V]
+}
+
+// LoadThunkResult is like LoadThunk but the thunk returns a Result.
+func (l *TypedDataLoader[K, V]) LoadThunkResult(ctx context.Context, key K, opts ...LoadOption) func() TypedResult[V] {
+	thunk := l.LoadThunk(ctx, key, opts...)
+	return func() TypedResult[V] {
+		v, err := thunk()
+		return TypedResult[V]{Value: v, Err: err}
+	}
+}
+
+// LoadAllResults is like LoadAll but returns one Result per key.
+func (l *TypedDataLoader[K, V]) LoadAllResults(ctx context.Context, keys []K, opts ...LoadOption) []TypedResult[V] {
+	values, errs := l.LoadAll(ctx, keys, opts...)
+	results := make([]TypedResult[V], len(keys))
+	for i := range keys {
+		results[i] = TypedResult[V]{Value: values[i], Err: errs[i]}
+	}
+	return results
+}
+
+// LoadChan loads the key and sends its result on the returned channel,
+// so it can be received in a select alongside other channels.
+// The channel is buffered and closed after the result was sent.
+func (l *TypedDataLoader[K, V]) LoadChan(ctx context.Context, key K, opts ...LoadOption) <-chan TypedResult[V] {
+	thunk := l.LoadThunkResult(ctx, key, opts...)
+	ch := make(chan TypedResult[V], 1)
+	go func() {
+		ch <- thunk()
+		close(ch)
+	}()
+	return ch
+}
+
+// LoadAllChan loads the keys and sends each result on the returned channel
+// as soon as it is available, not in the order of the keys.
+// The channel is buffered for all keys and closed after all results were sent.
+func (l *TypedDataLoader[K, V]) LoadAllChan(ctx context.Context, keys []K, opts ...LoadOption) <-chan TypedKeyedResult[K, V] {
+	o := newLoadOptions(opts)
+	o.concurrent = true
+	ctx, release := o.context(ctx)
+	ch := make(chan TypedKeyedResult[K, V], len(keys))
+	var wg sync.WaitGroup
+	wg.Add(len(keys))
+	for _, key := range keys {
+		thunk := l.loadThunk(ctx, key, o)
+		go func(key K) {
+			defer wg.Done()
+			v, err := thunk()
+			ch <- TypedKeyedResult[K, V]{Key: key, TypedResult: TypedResult[V]{Value: v, Err: err}}
+		}(key)
+	}
+	go func() {
+		wg.Wait()
+		release()
+		close(ch)
+	}()
+	return ch
+}
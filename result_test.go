@@ -0,0 +1,53 @@
+package dataloaders
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+// evenFetcher returns the even keys and ErrNotFound for the odd ones.
+func evenFetcher(ctx context.Context, keys []int) ([]int, []error) {
+	values, errs := make([]int, len(keys)), make([]error, len(keys))
+	for i, key := range keys {
+		if key%2 == 0 {
+			values[i] = key
+		} else {
+			errs[i] = ErrNotFound
+		}
+	}
+	return values, errs
+}
+
+func TestLoadResults(t *testing.T) {
+	l := NewTyped(evenFetcher, WithNotFound(NotFoundError))
+	ctx := context.Background()
+	if r := l.LoadThunkResult(ctx, 2)(); r.Err != nil || r.Value != 2 {
+		t.Fatalf("got %+v", r)
+	}
+	results := l.LoadAllResults(ctx, []int{2, 3})
+	if len(results) != 2 || results[0].Value != 2 || results[0].Err != nil || !errors.Is(results[1].Err, ErrNotFound) {
+		t.Fatalf("got %+v", results)
+	}
+}
+
+func TestLoadChan(t *testing.T) {
+	l := NewTyped(evenFetcher, WithNotFound(NotFoundError))
+	ctx := context.Background()
+	ch := l.LoadChan(ctx, 4)
+	if r := <-ch; r.Err != nil || r.Value != 4 {
+		t.Fatalf("got %+v", r)
+	}
+	if _, ok := <-ch; ok {
+		t.Fatal("channel not closed after the result")
+	}
+
+	results := map[int]TypedResult[int]{}
+	for r := range l.LoadAllChan(ctx, []int{1, 2, 3}) {
+		results[r.Key] = r.TypedResult
+	}
+	if len(results) != 3 || results[2].Value != 2 || results[2].Err != nil ||
+		!errors.Is(results[1].Err, ErrNotFound) || !errors.Is(results[3].Err, ErrNotFound) {
+		t.Fatalf("got %+v", results)
+	}
+}
@@ -0,0 +1,77 @@
+package dataloaders
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// Backoff returns how long to wait before the given retry attempt, starting at 1.
+type Backoff func(attempt int) time.Duration
+
+// ExponentialBackoff waits base before the first retry and doubles
+// the duration for every further retry, up to max.
+func ExponentialBackoff(base, max time.Duration) Backoff {
+	return func(attempt int) time.Duration {
+		d := base
+		for i := 1; i < attempt && d < max; i++ {
+			d *= 2
+		}
+		if d > max {
+			d = max
+		}
+		return d
+	}
+}
+
+// WithRetry retries a batch up to max times, waiting backoff between the attempts,
+// when the fetcher returned a single error for the whole batch.
+// Panics and context errors are not retried.
+func WithRetry(max int, backoff Backoff) Option {
+	return func(o *options) {
+		o.retries = max
+		o.backoff = backoff
+	}
+}
+
+// batchError returns the error if the fetcher failed the whole batch.
+// The lone error of a batch of one key may as well be the key's own error, see batchFailed.
+func batchError(errs []error) error {
+	if len(errs) == 1 {
+		return errs[0]
+	}
+	return nil
+}
+
+// batchFailed returns true if the whole-batch error is a failure of the fetcher,
+// not the ErrNotFound of a single missing key, e.g. returned by a MapFetcher.
+func batchFailed(err error) bool {
+	return err != nil && !errors.Is(err, ErrNotFound)
+}
+
+// retryable returns true if a failed batch should be fetched again.
+func retryable(err error) bool {
+	var panicErr *FetchPanicError
+	return batchFailed(err) && cacheableError(err) && !errors.As(err, &panicErr)
+}
+
+// fetchWithRetry calls the fetcher and retries whole-batch errors as configured by WithRetry.
+func (l *TypedDataLoader[K, V]) fetchWithRetry(ctx context.Context, keys []K) ([]V, []error) {
+	data, errs := l.hedgedFetch(ctx, keys)
+	for attempt := 1; attempt <= l.retries && retryable(batchError(errs)); attempt++ {
+		var wait time.Duration
+		if l.backoff != nil {
+			wait = l.backoff(attempt)
+		}
+		elapsed := make(chan struct{})
+		timer := l.clock.AfterFunc(wait, func() { close(elapsed) })
+		select {
+		case <-elapsed:
+		case <-ctx.Done():
+			timer.Stop()
+			return data, errs
+		}
+		data, errs = l.hedgedFetch(ctx, keys)
+	}
+	return data, errs
+}
@@ -0,0 +1,80 @@
+package dataloaders
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestExponentialBackoff(t *testing.T) {
+	backoff := ExponentialBackoff(time.Second, 3*time.Second)
+	for attempt, want := range map[int]time.Duration{1: time.Second, 2: 2 * time.Second, 3: 3 * time.Second, 10: 3 * time.Second} {
+		if d := backoff(attempt); d != want {
+			t.Fatalf("attempt %d: waited %v, want %v", attempt, d, want)
+		}
+	}
+}
+
+func TestWithRetry(t *testing.T) {
+	errUnavailable := errors.New("unavailable")
+	var attempts int
+	l := NewTyped(func(ctx context.Context, keys []int) ([]int, []error) {
+		if attempts++; attempts < 3 {
+			return nil, []error{errUnavailable}
+		}
+		return keys, nil
+	}, WithSynchronous(), WithRetry(2, nil))
+	if v, err := l.Load(context.Background(), 1); err != nil || v != 1 {
+		t.Fatalf("got %d, %v", v, err)
+	}
+	if attempts != 3 {
+		t.Fatalf("fetched %d times, want 3", attempts)
+	}
+}
+
+func TestWithRetryKeyErrors(t *testing.T) {
+	var attempts int
+	l := NewTyped(func(ctx context.Context, keys []int) ([]int, []error) {
+		attempts++
+		return keys, []error{nil, errors.New("invalid key")}
+	}, WithSynchronous(), WithRetry(2, nil))
+	if _, errs := l.LoadAll(context.Background(), []int{1, 2}); errs[0] != nil || errs[1] == nil {
+		t.Fatalf("got %v", errs)
+	}
+	// only errors failing the whole batch are retried
+	if attempts != 1 {
+		t.Fatalf("fetched %d times, want 1", attempts)
+	}
+}
+
+func TestWithRetryNotFound(t *testing.T) {
+	var attempts int
+	l := NewTyped(TypedMapFetcher[int, int](func(ctx context.Context, keys []int) (map[int]int, error) {
+		attempts++
+		return nil, nil
+	}).Fetcher(), WithSynchronous(), WithNotFound(NotFoundError), WithRetry(3, nil))
+	if _, err := l.Load(context.Background(), 1); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("got %v, want %v", err, ErrNotFound)
+	}
+	// the single missing key isn't a failed batch
+	if attempts != 1 {
+		t.Fatalf("fetched %d times, want 1", attempts)
+	}
+}
+
+func TestWithRetryBackoff(t *testing.T) {
+	var waited []int
+	l := NewTyped(func(ctx context.Context, keys []int) ([]int, []error) {
+		return nil, []error{errors.New("unavailable")}
+	}, WithSynchronous(), WithRetry(2, func(attempt int) time.Duration {
+		waited = append(waited, attempt)
+		return time.Millisecond
+	}))
+	if _, err := l.Load(context.Background(), 1); err == nil {
+		t.Fatal("got no error after the last retry")
+	}
+	if len(waited) != 2 || waited[0] != 1 || waited[1] != 2 {
+		t.Fatalf("waited before attempts %v, want [1 2]", waited)
+	}
+}
@@ -0,0 +1,78 @@
+package dataloaders
+
+import (
+	"hash/maphash"
+	"time"
+)
+
+// NewShardedCache creates a cache spreading its keys over n caches created by newShard,
+// so concurrent use of different keys doesn't contend on a single lock.
+// The cache supports GetStale (see TypedStaleCache) and per-key TTLs (see TypedTTLCache) if the shards do.
+func NewShardedCache[K comparable, V any](n int, newShard func() TypedCache[K, V]) TypedCache[K, V] {
+	if n < 1 {
+		n = 1
+	}
+	c := &shardedCache[K, V]{
+		seed:   maphash.MakeSeed(),
+		shards: make([]TypedCache[K, V], n),
+	}
+	for i := range c.shards {
+		c.shards[i] = newShard()
+	}
+	if _, ok := c.shards[0].(TypedStaleCache[K, V]); ok {
+		return &shardedStaleCache[K, V]{c}
+	}
+	return c
+}
+
+type shardedCache[K comparable, V any] struct {
+	// seeds the hash selecting the shard of a key
+	seed   maphash.Seed
+	shards []TypedCache[K, V]
+}
+
+// shard returns the cache holding key.
+func (c *shardedCache[K, V]) shard(key K) TypedCache[K, V] {
+	return c.shards[maphash.Comparable(c.seed, key)%uint64(len(c.shards))]
+}
+
+func (c *shardedCache[K, V]) Get(key K) (V, bool) {
+	return c.shard(key).Get(key)
+}
+
+func (c *shardedCache[K, V]) Set(key K, value V) {
+	c.shard(key).Set(key, value)
+}
+
+// SetWithTTL sets the value with ttl if the shards support per-key TTLs, else without.
+func (c *shardedCache[K, V]) SetWithTTL(key K, value V, ttl time.Duration) {
+	setWithTTL(c.shard(key), key, value, ttl)
+}
+
+func (c *shardedCache[K, V]) Delete(key K) {
+	c.shard(key).Delete(key)
+}
+
+// Clear clears the shards one after another.
+func (c *shardedCache[K, V]) Clear() {
+	for _, s := range c.shards {
+		s.Clear()
+	}
+}
+
+func (c *shardedCache[K, V]) Len() int {
+	var n int
+	for _, s := range c.shards {
+		n += s.Len()
+	}
+	return n
+}
+
+// shardedStaleCache is a shardedCache of TypedStaleCache shards.
+type shardedStaleCache[K comparable, V any] struct {
+	*shardedCache[K, V]
+}
+
+func (c *shardedStaleCache[K, V]) GetStale(key K) (V, bool, bool) {
+	return c.shard(key).(TypedStaleCache[K, V]).GetStale(key)
+}
@@ -0,0 +1,53 @@
+package dataloaders
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestShardedCache(t *testing.T) {
+	var shards []TypedCache[int, int]
+	c := NewShardedCache(4, func() TypedCache[int, int] {
+		shard := NewMapCache[int, int]()
+		shards = append(shards, shard)
+		return shard
+	})
+	for i := 0; i < 100; i++ {
+		c.Set(i, i)
+	}
+	if v, ok := c.Get(42); !ok || v != 42 || c.Len() != 100 {
+		t.Fatalf("got %d, %t with %d values", v, ok, c.Len())
+	}
+	for i, shard := range shards {
+		if shard.Len() == 0 {
+			t.Fatalf("shard %d holds no keys", i)
+		}
+	}
+	c.Delete(42)
+	if _, ok := c.Get(42); ok {
+		t.Fatal("deleted value still cached")
+	}
+	c.Clear()
+	if n := c.Len(); n != 0 {
+		t.Fatalf("cached %d values after Clear", n)
+	}
+}
+
+func TestWithShards(t *testing.T) {
+	clock := NewFakeClock(time.Now())
+	f := newCountingFetcher(echo)
+	l := NewTyped(f.fetcher, WithClock(clock), WithSynchronous(), WithShards(4), WithTTL(time.Minute))
+	ctx := context.Background()
+	l.LoadAll(ctx, []int{1, 2, 3})
+	l.Load(ctx, 2)
+	if n := f.fetches(2); n != 1 {
+		t.Fatalf("fetched key %d times, want 1", n)
+	}
+	// the shards keep the TTLs of the default cache
+	clock.Advance(time.Minute)
+	l.Load(ctx, 2)
+	if n := f.fetches(2); n != 2 {
+		t.Fatalf("fetched expired key %d times, want 2", n)
+	}
+}
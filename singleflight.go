@@ -0,0 +1,41 @@
+package dataloaders
+
+// WithSingleflight allows only one fetch per key outstanding at a time, across batches:
+// a load of a key being fetched joins that fetch instead of adding the key to another batch,
+// even if the key was cleared meanwhile or is loaded with ForceRefresh or NoCache.
+// It protects hot keys from stampedes when their cached values are cleared repeatedly,
+// at the cost of such loads getting the result of the fetch started before.
+// That result is not cached if the key was cleared, so the next load fetches it again.
+func WithSingleflight() Option {
+	return func(o *options) {
+		o.singleflight = true
+	}
+}
+
+// outstanding returns the flight of the key with identity id
+// if it is in a batch not yet done. Always false without WithSingleflight.
+// Must be called with l.mu held.
+func (l *TypedDataLoader[K, V]) outstanding(id K) (flight[K, V], bool) {
+	f, ok := l.flights[id]
+	return f, ok
+}
+
+// takeOff records the flight of the key with identity id until its batch is done.
+// Must be called with l.mu held.
+func (l *TypedDataLoader[K, V]) takeOff(id K, f flight[K, V]) {
+	if !l.singleflight {
+		return
+	}
+	if l.flights == nil {
+		l.flights = map[K]flight[K, V]{}
+	}
+	l.flights[id] = f
+}
+
+// land removes the flight of the key with identity id recorded for batch b.
+// Must be called with l.mu held.
+func (l *TypedDataLoader[K, V]) land(id K, b *batch[K, V]) {
+	if f, ok := l.flights[id]; ok && f.batch == b {
+		delete(l.flights, id)
+	}
+}
@@ -0,0 +1,65 @@
+package dataloaders
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestWithSingleflight(t *testing.T) {
+	for _, singleflight := range []bool{false, true} {
+		fetching := make(chan struct{})
+		release := make(chan struct{})
+		var mu sync.Mutex
+		var fetches int
+		opts := []Option{WithWait(time.Millisecond)}
+		if singleflight {
+			opts = append(opts, WithSingleflight())
+		}
+		l := NewTyped(func(ctx context.Context, keys []int) ([]int, []error) {
+			mu.Lock()
+			fetches++
+			n := fetches
+			mu.Unlock()
+			if n == 1 {
+				close(fetching)
+				<-release
+			}
+			return []int{n}, nil
+		}, opts...)
+		ctx := context.Background()
+
+		first := l.LoadThunk(ctx, 1)
+		<-fetching
+		l.Clear(1)
+		cleared := l.LoadThunk(ctx, 1)
+		refreshed := l.LoadThunk(ctx, 1, ForceRefresh())
+		close(release)
+		var got []int
+		for _, thunk := range []func() (int, error){first, cleared, refreshed} {
+			v, err := thunk()
+			if err != nil {
+				t.Fatal(err)
+			}
+			got = append(got, v)
+		}
+
+		if !singleflight {
+			if got[0] != 1 || got[1] == 1 || got[2] == 1 {
+				t.Fatalf("got versions %v without singleflight, want later loads fetched again", got)
+			}
+			continue
+		}
+		if got[0] != 1 || got[1] != 1 || got[2] != 1 {
+			t.Fatalf("got versions %v, want all joining the first fetch", got)
+		}
+		// the result of the cleared key isn't cached
+		if v, err := l.Load(ctx, 1); err != nil || v != 2 {
+			t.Fatalf("got %v, %v, want 2", v, err)
+		}
+		if v, err := l.Load(ctx, 1); err != nil || v != 2 {
+			t.Fatalf("got %v, %v, want cached 2", v, err)
+		}
+	}
+}
@@ -0,0 +1,69 @@
+package dataloaders
+
+import (
+	"context"
+	"fmt"
+	"slices"
+)
+
+// WithSortedKeys sorts the keys of every batch with cmp before they are passed to the fetcher,
+// so queries and test fixtures get a deterministic key order.
+// cmp returns a negative number if a < b, a positive number if a > b and zero otherwise (like cmp.Compare).
+// The fetched values and errors are returned in the original order of the keys.
+// The sorted keys are seen by all middleware (see WithFetcherMiddleware).
+// The key type of cmp must match the one of the DataLoader.
+func WithSortedKeys[K comparable](cmp func(a, b K) int) Option {
+	return func(o *options) {
+		o.sortKeys = cmp
+	}
+}
+
+// sortFetcher returns fetch passing the keys sorted as configured by WithSortedKeys.
+func sortFetcher[K comparable, V any](fetch TypedFetcher[K, V], o *options) TypedFetcher[K, V] {
+	if o.sortKeys == nil {
+		return fetch
+	}
+	cmp, ok := o.sortKeys.(func(a, b K) int)
+	if !ok {
+		panic(fmt.Sprintf("dataloaders: key comparator %T does not match the DataLoader's key type", o.sortKeys))
+	}
+	return SortKeys[K, V](cmp)(fetch)
+}
+
+// SortKeys returns middleware sorting the keys with cmp before calling the next fetcher,
+// see WithSortedKeys.
+func SortKeys[K comparable, V any](cmp func(a, b K) int) TypedFetcherMiddleware[K, V] {
+	return func(next TypedFetcher[K, V]) TypedFetcher[K, V] {
+		return func(ctx context.Context, keys []K) ([]V, []error) {
+			// the original positions of the keys in sorted order
+			order := make([]int, len(keys))
+			for i := range order {
+				order[i] = i
+			}
+			slices.SortStableFunc(order, func(a, b int) int {
+				return cmp(keys[a], keys[b])
+			})
+			sorted := make([]K, len(keys))
+			for i, pos := range order {
+				sorted[i] = keys[pos]
+			}
+
+			data, errs := next(ctx, sorted)
+			return unsort(order, data), unsort(order, errs)
+		}
+	}
+}
+
+// unsort returns the results of sorted keys in the original order of the keys.
+// Results not matching the number of keys are returned as is,
+// they are invalid anyway (see WithStrict) unless it is a single error for all keys.
+func unsort[T any](order []int, results []T) []T {
+	if len(results) != len(order) {
+		return results
+	}
+	out := make([]T, len(results))
+	for i, pos := range order {
+		out[pos] = results[i]
+	}
+	return out
+}
@@ -0,0 +1,43 @@
+package dataloaders
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestWithSortedKeys(t *testing.T) {
+	var fetched []int
+	l := NewTyped(func(ctx context.Context, keys []int) ([]int, []error) {
+		fetched = keys
+		values, errs := make([]int, len(keys)), make([]error, len(keys))
+		for i, key := range keys {
+			values[i] = key * 10
+			if key == 2 {
+				errs[i] = errors.New("two")
+			}
+		}
+		return values, errs
+	}, WithSynchronous(), WithSortedKeys(cmp.Compare[int]))
+	values, errs := l.LoadAll(context.Background(), []int{3, 1, 2})
+	if fmt.Sprint(fetched) != "[1 2 3]" {
+		t.Fatalf("fetched %v, want the sorted keys", fetched)
+	}
+	// the results are returned in the original order
+	if fmt.Sprint(values) != "[30 10 20]" || errs[0] != nil || errs[1] != nil || errs[2] == nil {
+		t.Fatalf("got %v, %v", values, errs)
+	}
+}
+
+func TestSortKeysBatchError(t *testing.T) {
+	errDown := errors.New("down")
+	fetch := SortKeys[int, int](cmp.Compare[int])(func(ctx context.Context, keys []int) ([]int, []error) {
+		return nil, []error{errDown}
+	})
+	// results not matching the keys are returned as is
+	if values, errs := fetch(context.Background(), []int{2, 1}); values != nil || len(errs) != 1 || errs[0] != errDown {
+		t.Fatalf("got %v, %v", values, errs)
+	}
+}
@@ -0,0 +1,67 @@
+package dataloaders
+
+import "sync/atomic"
+
+// Stats are the counters of a DataLoader since it was created.
+type Stats struct {
+	// Number of loaded keys found in the cache.
+	Hits uint64
+	// Number of loaded keys not found in the cache and therefore added to a batch.
+	Misses uint64
+	// Number of keys primed into the cache.
+	Primes uint64
+	// Number of Clear and ClearAll calls.
+	Clears uint64
+	// Number of batches sent to the fetcher.
+	Batches uint64
+	// Number of keys sent to the fetcher in all batches.
+	BatchedKeys uint64
+	// Average number of keys per batch.
+	AvgBatchSize float64
+	// Number of batches the fetcher returned any error for.
+	FetchErrors uint64
+	// Number of batches sent to the fetcher a second time, see WithHedging.
+	Hedges uint64
+}
+
+type stats struct {
+	hits        atomic.Uint64
+	misses      atomic.Uint64
+	primes      atomic.Uint64
+	clears      atomic.Uint64
+	batches     atomic.Uint64
+	batchedKeys atomic.Uint64
+	fetchErrors atomic.Uint64
+	hedges      atomic.Uint64
+}
+
+// Stats returns the current counters of the DataLoader.
+func (l *TypedDataLoader[K, V]) Stats() Stats {
+	s := Stats{
+		Hits:        l.stats.hits.Load(),
+		Misses:      l.stats.misses.Load(),
+		Primes:      l.stats.primes.Load(),
+		Clears:      l.stats.clears.Load(),
+		Batches:     l.stats.batches.Load(),
+		BatchedKeys: l.stats.batchedKeys.Load(),
+		FetchErrors: l.stats.fetchErrors.Load(),
+		Hedges:      l.stats.hedges.Load(),
+	}
+	if s.Batches != 0 {
+		s.AvgBatchSize = float64(s.BatchedKeys) / float64(s.Batches)
+	}
+	return s
+}
+
+// Pending returns the number of keys waiting in the current batch
+// and the number of batches dispatched but not yet done.
+func (l *TypedDataLoader[K, V]) Pending() (keys int, fetching int) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	fetching = len(l.running)
+	if l.batch != nil {
+		keys = len(l.batch.keys)
+		fetching--
+	}
+	return keys, fetching
+}
@@ -0,0 +1,62 @@
+package dataloaders
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestStats(t *testing.T) {
+	l := NewTyped(func(ctx context.Context, keys []int) ([]int, []error) {
+		errs := make([]error, len(keys))
+		if len(keys) == 1 {
+			errs[0] = ErrNotFound
+		}
+		return keys, errs
+	}, WithSynchronous(), WithNotFound(NotFoundError))
+	ctx := context.Background()
+	l.LoadAll(ctx, []int{1, 2, 3})
+	l.Load(ctx, 1)
+	l.Load(ctx, 4)
+	l.Prime(5, 5)
+	l.Clear(5)
+	want := Stats{Hits: 1, Misses: 4, Primes: 1, Clears: 1, Batches: 2, BatchedKeys: 4, AvgBatchSize: 2, FetchErrors: 1}
+	if got := l.Stats(); got != want {
+		t.Fatalf("got %+v, want %+v", got, want)
+	}
+}
+
+func TestPending(t *testing.T) {
+	release := make(chan struct{})
+	l := NewTyped(func(ctx context.Context, keys []int) ([]int, []error) {
+		<-release
+		return keys, nil
+	}, WithSynchronous())
+	ctx := context.Background()
+	l.LoadThunk(ctx, 1)
+	l.LoadThunk(ctx, 2)
+	if keys, fetching := l.Pending(); keys != 2 || fetching != 0 {
+		t.Fatalf("got %d keys and %d batches pending, want 2 and 0", keys, fetching)
+	}
+	go l.Dispatch()
+	defer close(release)
+	for {
+		if keys, fetching := l.Pending(); keys == 0 && fetching == 1 {
+			return
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestStatsCountFetchedBatches(t *testing.T) {
+	clock := NewFakeClock(time.Now())
+	f := &switchFetcher{down: true}
+	l := NewTyped(f.fetcher, WithClock(clock), WithSynchronous(), WithCircuitBreaker(1, time.Minute))
+	ctx := context.Background()
+	l.Load(ctx, 1)
+	// fails fast without reaching the fetcher
+	l.Load(ctx, 2)
+	if s := l.Stats(); s.Batches != 1 || s.BatchedKeys != 1 || s.FetchErrors != 1 {
+		t.Fatalf("got %+v, want only the fetched batch counted", s)
+	}
+}
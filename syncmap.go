@@ -0,0 +1,51 @@
+package dataloaders
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// NewSyncMapCache creates an unbounded cache backed by a sync.Map.
+// Reads don't take a lock, which suits read-heavy workloads with many
+// concurrent loads of keys that are rarely changed once cached.
+func NewSyncMapCache[K comparable, V any]() TypedCache[K, V] {
+	return &syncMapCache[K, V]{}
+}
+
+type syncMapCache[K comparable, V any] struct {
+	m sync.Map
+	// the number of values, sync.Map doesn't track it
+	n atomic.Int64
+}
+
+func (c *syncMapCache[K, V]) Get(key K) (V, bool) {
+	v, ok := c.m.Load(key)
+	if !ok {
+		var zero V
+		return zero, false
+	}
+	return v.(V), true
+}
+
+func (c *syncMapCache[K, V]) Set(key K, value V) {
+	if _, loaded := c.m.Swap(key, value); !loaded {
+		c.n.Add(1)
+	}
+}
+
+func (c *syncMapCache[K, V]) Delete(key K) {
+	if _, loaded := c.m.LoadAndDelete(key); loaded {
+		c.n.Add(-1)
+	}
+}
+
+func (c *syncMapCache[K, V]) Clear() {
+	c.m.Range(func(key, _ interface{}) bool {
+		c.Delete(key.(K))
+		return true
+	})
+}
+
+func (c *syncMapCache[K, V]) Len() int {
+	return int(c.n.Load())
+}
@@ -0,0 +1,49 @@
+package dataloaders
+
+import (
+	"context"
+	"sync"
+	"testing"
+)
+
+func TestSyncMapCache(t *testing.T) {
+	c := NewSyncMapCache[int, int]()
+	c.Set(1, 1)
+	c.Set(1, 2)
+	c.Set(2, 2)
+	if v, ok := c.Get(1); !ok || v != 2 || c.Len() != 2 {
+		t.Fatalf("got %d, %t with %d values, want the replaced 2 of 2 values", v, ok, c.Len())
+	}
+	// deleting a missing key doesn't change the length
+	c.Delete(1)
+	c.Delete(1)
+	if n := c.Len(); n != 1 {
+		t.Fatalf("cached %d values after Delete, want 1", n)
+	}
+	c.Clear()
+	if n := c.Len(); n != 0 {
+		t.Fatalf("cached %d values after Clear", n)
+	}
+}
+
+func TestSyncMapCacheConcurrentLoads(t *testing.T) {
+	f := newCountingFetcher(echo)
+	l := NewTyped(f.fetcher, WithCache[int, int](NewSyncMapCache[int, int]()))
+	ctx := context.Background()
+	var wg sync.WaitGroup
+	for i := 0; i < 100; i++ {
+		wg.Add(1)
+		go func(key int) {
+			defer wg.Done()
+			if v, err := l.Load(ctx, key); err != nil || v != key {
+				t.Errorf("key %d: got %d, %v", key, v, err)
+			}
+		}(i % 10)
+	}
+	wg.Wait()
+	for key := 0; key < 10; key++ {
+		if n := f.fetches(key); n != 1 {
+			t.Fatalf("fetched key %d %d times, want 1", key, n)
+		}
+	}
+}
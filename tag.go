@@ -0,0 +1,71 @@
+package dataloaders
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// TagPropagators builds the ValuePropagators for the struct type of example
+// from its fields tagged with `dataloader:"<attribute>"`.
+// When a value is loaded by one tagged attribute, the caches of all other tagged
+// attributes are primed with the value at the keys of their fields.
+// Fields with zero values are not primed. Loaded nil values are ignored.
+// It panics if a tagged field is unexported.
+//
+// 	type Account struct {
+// 		ID    int    `dataloader:"id"`
+// 		Email string `dataloader:"email"`
+// 	}
+//
+// 	NewAttrDataLoader(inits, TagPropagators(&Account{}))
+func TagPropagators(example interface{}) ValuePropagators {
+	typ := reflect.TypeOf(example)
+	if typ != nil && typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+	if typ == nil || typ.Kind() != reflect.Struct {
+		panic(fmt.Sprintf("dataloaders: TagPropagators requires a struct or struct pointer, got %T", example))
+	}
+
+	// the field index of every tagged attribute
+	fields := map[Attribute][]int{}
+	for i := 0; i < typ.NumField(); i++ {
+		f := typ.Field(i)
+		if attribute, ok := f.Tag.Lookup("dataloader"); ok && attribute != "" && attribute != "-" {
+			if !f.IsExported() {
+				panic(fmt.Sprintf("dataloaders: TagPropagators requires exported fields, %s.%s tagged %q is unexported", typ, f.Name, attribute))
+			}
+			fields[attribute] = f.Index
+		}
+	}
+
+	propagators := make(ValuePropagators, len(fields))
+	for loadedBy := range fields {
+		loadedBy := loadedBy
+		propagators[loadedBy] = func(loadedValue Value, l *AttrDataLoader) error {
+			v := reflect.ValueOf(loadedValue)
+			if v.Kind() == reflect.Ptr {
+				if v.IsNil() {
+					return nil
+				}
+				v = v.Elem()
+			}
+			if !v.IsValid() {
+				return nil
+			}
+			if v.Type() != typ {
+				return fmt.Errorf("tag propagator for %s got value of type %T", typ, loadedValue)
+			}
+			for attribute, index := range fields {
+				if attribute == loadedBy {
+					continue
+				}
+				if key := v.FieldByIndex(index); !key.IsZero() {
+					l.Prime(attribute, key.Interface(), loadedValue)
+				}
+			}
+			return nil
+		}
+	}
+	return propagators
+}
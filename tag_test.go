@@ -0,0 +1,39 @@
+package dataloaders
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestTagPropagatorsUnexportedField(t *testing.T) {
+	type user struct {
+		ID    int    `dataloader:"id"`
+		email string `dataloader:"email"`
+	}
+	defer func() {
+		r := recover()
+		if msg, ok := r.(string); !ok || !strings.Contains(msg, "email") {
+			t.Fatalf("got panic %v, want one naming the unexported field", r)
+		}
+	}()
+	TagPropagators(&user{})
+}
+
+func TestTagPropagatorsPrimeTaggedAttributes(t *testing.T) {
+	type user struct {
+		ID    int    `dataloader:"id"`
+		Email string `dataloader:"email"`
+	}
+	l := NewAttrDataLoader(AttrDataLoaderInits{
+		"id":    func() *DataLoader { return New(nil) },
+		"email": func() *DataLoader { return New(nil) },
+	}, TagPropagators(&user{}))
+	u := &user{ID: 1, Email: "a"}
+	if err := l.RunPropagator(u, "id"); err != nil {
+		t.Fatal(err)
+	}
+	loader, _ := l.Loader("email")
+	if v, ok := loader.cache.Get("a"); !ok || v != u {
+		t.Fatalf("email cached %v, want %v", v, u)
+	}
+}
@@ -0,0 +1,152 @@
+package dataloaders
+
+import (
+	"sync"
+	"time"
+)
+
+// NewTieredCache creates a two-level cache of a fast local l1 and a shared l2, e.g. Redis.
+// Values not in l1 are read from l2 and then kept in l1 (read-through).
+// Values are set and deleted in both caches (write-through).
+// Len returns the number of values in l1.
+// The cache supports GetStale (see TypedStaleCache) if l1 does.
+func NewTieredCache[K comparable, V any](l1, l2 TypedCache[K, V]) TypedCache[K, V] {
+	return newTiered(&tieredCache[K, V]{l1: l1, l2: l2})
+}
+
+// newWriteBehindCache creates the tiered cache of WithL2Cache. It queues the writes to l2
+// instead of writing through, so the DataLoader doesn't wait for l2 with its lock held
+// but writes them once it released it with flush.
+func newWriteBehindCache[K comparable, V any](l1, l2 TypedCache[K, V]) TypedCache[K, V] {
+	return newTiered(&tieredCache[K, V]{l1: l1, l2: l2, behind: &writeBehind{}})
+}
+
+// newTiered returns c, as tieredStaleCache if l1 supports GetStale.
+func newTiered[K comparable, V any](c *tieredCache[K, V]) TypedCache[K, V] {
+	if _, ok := c.l1.(TypedStaleCache[K, V]); ok {
+		return &tieredStaleCache[K, V]{c}
+	}
+	return c
+}
+
+// writeBehindCache returns the tiered cache of WithL2Cache, nil if cache is none.
+func writeBehindCache[K comparable, V any](cache TypedCache[K, V]) *tieredCache[K, V] {
+	var c *tieredCache[K, V]
+	switch cache := cache.(type) {
+	case *tieredCache[K, V]:
+		c = cache
+	case *tieredStaleCache[K, V]:
+		c = cache.tieredCache
+	}
+	if c == nil || c.behind == nil {
+		return nil
+	}
+	return c
+}
+
+type tieredCache[K comparable, V any] struct {
+	l1, l2 TypedCache[K, V]
+	// the queued writes to l2, nil to write through
+	behind *writeBehind
+}
+
+// writeBehind is the queue of writes to the second level of a tiered cache.
+type writeBehind struct {
+	mu     sync.Mutex
+	writes []func()
+	// serializes flushes, so the writes reach l2 in the order they were queued
+	flushing sync.Mutex
+}
+
+func (c *tieredCache[K, V]) Get(key K) (V, bool) {
+	if v, ok := c.l1.Get(key); ok {
+		return v, true
+	}
+	return c.readThrough(key)
+}
+
+// readThrough reads the value at key from l2 and keeps it in l1.
+func (c *tieredCache[K, V]) readThrough(key K) (V, bool) {
+	// don't read values deleted by queued writes
+	c.flush()
+	v, ok := c.l2.Get(key)
+	if ok {
+		c.l1.Set(key, v)
+	}
+	return v, ok
+}
+
+func (c *tieredCache[K, V]) Set(key K, value V) {
+	c.l1.Set(key, value)
+	c.write(func() { c.l2.Set(key, value) })
+}
+
+// SetWithTTL sets the value with ttl in l1 if it supports per-key TTLs, else without.
+func (c *tieredCache[K, V]) SetWithTTL(key K, value V, ttl time.Duration) {
+	setWithTTL(c.l1, key, value, ttl)
+	c.write(func() { c.l2.Set(key, value) })
+}
+
+func (c *tieredCache[K, V]) Delete(key K) {
+	c.l1.Delete(key)
+	c.write(func() { c.l2.Delete(key) })
+}
+
+func (c *tieredCache[K, V]) Clear() {
+	c.l1.Clear()
+	c.write(c.l2.Clear)
+}
+
+func (c *tieredCache[K, V]) Len() int {
+	return c.l1.Len()
+}
+
+// tieredStaleCache is a tieredCache of a TypedStaleCache l1.
+type tieredStaleCache[K comparable, V any] struct {
+	*tieredCache[K, V]
+}
+
+// GetStale returns the value at key of l1 if not expired, else the value read through from l2,
+// else the expired value of l1.
+func (c *tieredStaleCache[K, V]) GetStale(key K) (V, bool, bool) {
+	v, stale, ok := c.l1.(TypedStaleCache[K, V]).GetStale(key)
+	if ok && !stale {
+		return v, false, true
+	}
+	if fresh, found := c.readThrough(key); found {
+		return fresh, false, true
+	}
+	return v, stale, ok
+}
+
+// write writes to l2 or queues the write until the next flush.
+func (c *tieredCache[K, V]) write(fn func()) {
+	if c.behind == nil {
+		fn()
+		return
+	}
+	c.behind.mu.Lock()
+	c.behind.writes = append(c.behind.writes, fn)
+	c.behind.mu.Unlock()
+}
+
+// flush runs the queued writes to l2, including the ones queued while flushing.
+func (c *tieredCache[K, V]) flush() {
+	if c.behind == nil {
+		return
+	}
+	c.behind.flushing.Lock()
+	defer c.behind.flushing.Unlock()
+	for {
+		c.behind.mu.Lock()
+		writes := c.behind.writes
+		c.behind.writes = nil
+		c.behind.mu.Unlock()
+		if len(writes) == 0 {
+			return
+		}
+		for _, fn := range writes {
+			fn()
+		}
+	}
+}
@@ -0,0 +1,83 @@
+package dataloaders
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// gateCache is a cache whose writes of key block until its gate is closed.
+type gateCache struct {
+	TypedCache[int, int]
+	key     int
+	gate    chan struct{}
+	writing chan struct{}
+}
+
+func (c *gateCache) Set(key, value int) {
+	if key == c.key {
+		close(c.writing)
+		<-c.gate
+	}
+	c.TypedCache.Set(key, value)
+}
+
+func TestL2CacheWrittenAfterUnlock(t *testing.T) {
+	l2 := &gateCache{TypedCache: NewMapCache[int, int](), key: 1, gate: make(chan struct{}), writing: make(chan struct{})}
+	l := NewTyped(func(ctx context.Context, keys []int) ([]int, []error) {
+		return keys, nil
+	}, WithL2Cache[int, int](l2))
+	ctx := context.Background()
+	l.Prime(2, 2)
+
+	loaded := make(chan struct{})
+	go func() {
+		defer close(loaded)
+		l.Load(ctx, 1)
+	}()
+	<-l2.writing
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		l.Pending()
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("lock held while writing to the second level cache")
+	}
+	close(l2.gate)
+	<-loaded
+
+	l.Clear(1)
+	if _, ok := l2.Get(1); ok {
+		t.Fatal("cleared key still in the second level cache")
+	}
+	if _, ok := l2.Get(2); !ok {
+		t.Fatal("primed key not in the second level cache")
+	}
+}
+
+func TestL2CacheStaleWhileRevalidate(t *testing.T) {
+	clock := NewFakeClock(time.Now())
+	f := &versionFetcher{}
+	l2 := NewMapCache[int, int]()
+	l := NewTyped(f.fetcher, WithClock(clock), WithSynchronous(), WithTTL(time.Minute),
+		WithStaleWhileRevalidate(), WithL2Cache[int, int](l2))
+	ctx := context.Background()
+	l.Load(ctx, 1)
+	// expired in both levels
+	l2.Delete(1)
+	clock.Advance(time.Minute)
+
+	if v, err := l.Load(ctx, 1); err != nil || v != 1 {
+		t.Fatalf("expired: got %d, %v, want the stale value", v, err)
+	}
+	l.Dispatch()
+	if v, err := l.Load(ctx, 1); err != nil || v != 2 {
+		t.Fatalf("revalidated: got %d, %v", v, err)
+	}
+	if v, ok := l2.Get(1); !ok || v != 2 {
+		t.Fatalf("second level cache holds %d, %v, want the revalidated value", v, ok)
+	}
+}
@@ -0,0 +1,24 @@
+package dataloaders
+
+import (
+	"context"
+	"time"
+)
+
+// LoadWithTimeout loads the key like Load, but returns context.DeadlineExceeded
+// if its result is not available within d (see WithTimeout).
+// The batch is still fetched for the other loads waiting for it
+// and its result is cached for later loads.
+func (l *TypedDataLoader[K, V]) LoadWithTimeout(ctx context.Context, key K, d time.Duration, opts ...LoadOption) (V, error) {
+	return l.Load(ctx, key, append(opts[:len(opts):len(opts)], WithTimeout(d))...)
+}
+
+// LoadWithTimeout loads the key at attribute within d, see DataLoader.LoadWithTimeout.
+func (l *AttrDataLoader) LoadWithTimeout(ctx context.Context, attribute Attribute, key Key, d time.Duration, opts ...LoadOption) (Value, error) {
+	return l.Load(ctx, attribute, key, append(opts[:len(opts):len(opts)], WithTimeout(d))...)
+}
+
+// LoadWithTimeout loads the key at attribute for objectType within d, see DataLoader.LoadWithTimeout.
+func (l *ObjAttrDataLoader) LoadWithTimeout(ctx context.Context, objectType ObjectType, attribute Attribute, key Key, d time.Duration, opts ...LoadOption) (Value, error) {
+	return l.Load(ctx, objectType, attribute, key, append(opts[:len(opts):len(opts)], WithTimeout(d))...)
+}
@@ -0,0 +1,47 @@
+package dataloaders
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestLoadWithTimeout(t *testing.T) {
+	release := make(chan struct{})
+	f := newCountingFetcher(func(keys []int) ([]int, []error) {
+		<-release
+		return echo(keys)
+	})
+	l := NewTyped(f.fetcher, WithWait(time.Millisecond))
+	ctx := context.Background()
+
+	slow := l.LoadThunk(ctx, 1)
+	_, err := l.LoadWithTimeout(ctx, 1, 10*time.Millisecond)
+	if !errors.Is(err, context.DeadlineExceeded) || !IsTimeout(err) {
+		t.Fatalf("got %v, want %v", err, context.DeadlineExceeded)
+	}
+	close(release)
+	// the batch isn't torn down by the timed out load
+	if v, err := slow(); err != nil || v != 1 {
+		t.Fatalf("got %v, %v, want 1", v, err)
+	}
+	if v, err := l.LoadWithTimeout(ctx, 1, 10*time.Millisecond); err != nil || v != 1 {
+		t.Fatalf("got %v, %v, want cached 1", v, err)
+	}
+	if n := f.fetches(1); n != 1 {
+		t.Fatalf("fetched key 1 %d times, want 1", n)
+	}
+}
+
+func TestLoadWithTimeoutKeepsCallerOptions(t *testing.T) {
+	l := NewTyped(echoFetcher, WithSynchronous())
+	opts := make([]LoadOption, 1, 2)
+	opts[0] = NoCache()
+	if _, err := l.LoadWithTimeout(context.Background(), 1, time.Second, opts...); err != nil {
+		t.Fatal(err)
+	}
+	if opts[:2][1] != nil {
+		t.Fatal("LoadWithTimeout wrote into the caller's options")
+	}
+}
@@ -0,0 +1,44 @@
+package dataloaders
+
+import (
+	"fmt"
+	"runtime/debug"
+)
+
+// WithTransform applies fn to every value fetched without error before it is cached
+// and returned, e.g. to deep copy, normalize or redact fields of values
+// living in a long-lived shared cache. Primed values are not transformed.
+// If fn panics, the key fails with a *FetchPanicError passed to the handler set with WithOnPanic.
+// The key and value types of fn must match the ones of the DataLoader.
+func WithTransform[K comparable, V any](fn func(key K, value V) V) Option {
+	return func(o *options) {
+		o.transform = fn
+	}
+}
+
+// newTransform returns the function set by WithTransform, nil if none.
+func newTransform[K comparable, V any](o *options) func(K, V) V {
+	if o.transform == nil {
+		return nil
+	}
+	fn, ok := o.transform.(func(K, V) V)
+	if !ok {
+		panic(fmt.Sprintf("dataloaders: transform %T does not match the DataLoader's key and value types", o.transform))
+	}
+	return fn
+}
+
+// safeTransform applies the transform to the value fetched for key,
+// recovering a panic like safeFetch.
+func (l *TypedDataLoader[K, V]) safeTransform(key K, value V) (transformed V, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			panicErr := &FetchPanicError{Recovered: r, Stack: debug.Stack()}
+			err = panicErr
+			if l.onPanic != nil {
+				l.onPanic(panicErr)
+			}
+		}
+	}()
+	return l.transform(key, value), nil
+}
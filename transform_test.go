@@ -0,0 +1,41 @@
+package dataloaders
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestTransformWithoutLock(t *testing.T) {
+	var l *TypedDataLoader[int, int]
+	l = NewTyped(func(ctx context.Context, keys []int) ([]int, []error) {
+		return keys, nil
+	}, WithTransform(func(key, value int) int {
+		// deadlocks if called with l.mu held
+		l.Pending()
+		return value * 10
+	}))
+	if v, err := l.Load(context.Background(), 1); err != nil || v != 10 {
+		t.Fatalf("got %d, %v, want 10", v, err)
+	}
+}
+
+func TestTransformPanic(t *testing.T) {
+	var handled *FetchPanicError
+	l := NewTyped(func(ctx context.Context, keys []int) ([]int, []error) {
+		return keys, nil
+	}, WithTransform(func(key, value int) int {
+		if key == 1 {
+			panic("bad value")
+		}
+		return value
+	}), WithOnPanic(func(err *FetchPanicError) { handled = err }))
+	values, errs := l.LoadAll(context.Background(), []int{1, 2})
+	var panicErr *FetchPanicError
+	if !errors.As(errs[0], &panicErr) || panicErr.Recovered != "bad value" || panicErr != handled {
+		t.Fatalf("got %v, want the handled panic", errs[0])
+	}
+	if errs[1] != nil || values[1] != 2 {
+		t.Fatalf("got %d, %v, want 2", values[1], errs[1])
+	}
+}
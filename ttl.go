@@ -0,0 +1,160 @@
+package dataloaders
+
+import (
+	"sync"
+	"time"
+)
+
+// NewTTLCache wraps a cache so its values expire ttl after they were set, 0 = never.
+// Expired values are evicted lazily when they are accessed.
+// Values evicted by a bounded inner cache (see NewLRUCache) drop their expiration with them.
+// The cache supports per-key TTLs (see TypedTTLCache).
+func NewTTLCache[K comparable, V any](inner TypedCache[K, V], ttl time.Duration) TypedCache[K, V] {
+	return newExpiringCache(inner, ttl, false, SystemClock)
+}
+
+// NewSlidingTTLCache is like NewTTLCache, but extends the expiration of values by their TTL
+// every time they are got, so only values not accessed for their TTL expire.
+func NewSlidingTTLCache[K comparable, V any](inner TypedCache[K, V], ttl time.Duration) TypedCache[K, V] {
+	return newExpiringCache(inner, ttl, true, SystemClock)
+}
+
+// TTLCache is the TTL cache of the untyped DataLoader.
+type TTLCache = TypedTTLCache[Key, Value]
+
+// TypedTTLCache is a cache supporting per-key TTLs, used by PrimeWithTTL.
+type TypedTTLCache[K comparable, V any] interface {
+	TypedCache[K, V]
+	// SetWithTTL caches the value at key, expiring after ttl, 0 = never.
+	SetWithTTL(key K, value V, ttl time.Duration)
+}
+
+// setWithTTL sets the value with ttl if the cache supports per-key TTLs, else without.
+func setWithTTL[K comparable, V any](cache TypedCache[K, V], key K, value V, ttl time.Duration) {
+	if c, ok := cache.(TypedTTLCache[K, V]); ok {
+		c.SetWithTTL(key, value, ttl)
+	} else {
+		cache.Set(key, value)
+	}
+}
+
+// StaleCache is the stale cache of the untyped DataLoader.
+type StaleCache = TypedStaleCache[Key, Value]
+
+// TypedStaleCache is a cache able to return expired values,
+// required by WithStaleWhileRevalidate.
+type TypedStaleCache[K comparable, V any] interface {
+	TypedCache[K, V]
+	// GetStale returns the value at key, whether it is expired and whether it exists.
+	GetStale(key K) (value V, stale bool, ok bool)
+}
+
+type ttlCache[K comparable, V any] struct {
+	// the cache holding the values
+	inner TypedCache[K, V]
+
+	// how long values are valid after set, 0 = forever
+	ttl time.Duration
+	// whether gets extend the expiration
+	sliding bool
+	// the source of time
+	clock Clock
+
+	// the expiration of the expiring values by key,
+	// pruned of the values evicted by inner if it is an evicter
+	expires map[K]expiry
+
+	mu sync.Mutex
+}
+
+type expiry struct {
+	// when the value expires
+	at time.Time
+	// the TTL of the value
+	ttl time.Duration
+}
+
+func (c *ttlCache[K, V]) Get(key K) (V, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	now := c.clock.Now()
+	if exp, ok := c.expires[key]; ok && !now.Before(exp.at) {
+		c.inner.Delete(key)
+		delete(c.expires, key)
+		var zero V
+		return zero, false
+	}
+	v, ok := c.inner.Get(key)
+	if !ok {
+		// evicted by the inner cache
+		delete(c.expires, key)
+	} else {
+		c.slide(key, now)
+	}
+	return v, ok
+}
+
+// GetStale returns the value at key even if it expired.
+// Expired values are not evicted so they can be served while being refetched.
+func (c *ttlCache[K, V]) GetStale(key K) (V, bool, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	v, ok := c.inner.Get(key)
+	if !ok {
+		delete(c.expires, key)
+		return v, false, false
+	}
+	now := c.clock.Now()
+	exp, expires := c.expires[key]
+	stale := expires && !now.Before(exp.at)
+	if !stale {
+		c.slide(key, now)
+	}
+	return v, stale, true
+}
+
+func (c *ttlCache[K, V]) Set(key K, value V) {
+	c.SetWithTTL(key, value, c.ttl)
+}
+
+func (c *ttlCache[K, V]) SetWithTTL(key K, value V, ttl time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.inner.Set(key, value)
+	if ttl > 0 {
+		c.expires[key] = expiry{at: c.clock.Now().Add(ttl), ttl: ttl}
+	} else {
+		delete(c.expires, key)
+	}
+}
+
+// slide extends the expiration of the value at key, if sliding.
+// Must be called with c.mu held.
+func (c *ttlCache[K, V]) slide(key K, now time.Time) {
+	if !c.sliding {
+		return
+	}
+	if exp, ok := c.expires[key]; ok {
+		c.expires[key] = expiry{at: now.Add(exp.ttl), ttl: exp.ttl}
+	}
+}
+
+func (c *ttlCache[K, V]) Delete(key K) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.inner.Delete(key)
+	delete(c.expires, key)
+}
+
+func (c *ttlCache[K, V]) Clear() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.inner.Clear()
+	c.expires = map[K]expiry{}
+}
+
+// Len returns the number of cached values,
+// including expired ones not yet evicted.
+func (c *ttlCache[K, V]) Len() int {
+	return c.inner.Len()
+}
@@ -0,0 +1,107 @@
+package dataloaders
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestWithTTL(t *testing.T) {
+	clock := NewFakeClock(time.Now())
+	f := newCountingFetcher(echo)
+	l := NewTyped(f.fetcher, WithClock(clock), WithSynchronous(), WithTTL(time.Minute))
+	ctx := context.Background()
+	l.Load(ctx, 1)
+	clock.Advance(time.Minute - time.Second)
+	l.Load(ctx, 1)
+	if n := f.fetches(1); n != 1 {
+		t.Fatalf("fetched key %d times before the TTL, want 1", n)
+	}
+	clock.Advance(time.Second)
+	l.Load(ctx, 1)
+	if n := f.fetches(1); n != 2 {
+		t.Fatalf("fetched key %d times after the TTL, want 2", n)
+	}
+}
+
+func TestTTLCacheEvictsExpiredValues(t *testing.T) {
+	inner := NewMapCache[int, int]()
+	c := NewTTLCache(inner, time.Nanosecond)
+	c.Set(1, 1)
+	time.Sleep(time.Millisecond)
+	if _, ok := c.Get(1); ok {
+		t.Fatal("got expired value")
+	}
+	if n := inner.Len(); n != 0 {
+		t.Fatalf("expired value not evicted from the inner cache, %d values", n)
+	}
+}
+
+func TestTTLCacheBoundedByInnerEvictions(t *testing.T) {
+	c := NewTTLCache(NewLRUCache[int, int](10), time.Hour).(*ttlCache[int, int])
+	for i := 0; i < 10000; i++ {
+		c.Set(i, i)
+	}
+	if n := c.Len(); n != 10 {
+		t.Fatalf("cached %d values, want 10", n)
+	}
+	// the expirations of the evicted values are dropped with them
+	if n := len(c.expires); n > 10 {
+		t.Fatalf("tracked %d expirations, want at most 10", n)
+	}
+}
+
+func TestWithTTLAndCacheSizeBounded(t *testing.T) {
+	l := NewTyped(echoFetcher, WithSynchronous(), WithCacheSize(10), WithTTL(time.Hour))
+	ctx := context.Background()
+	for i := 0; i < 100; i++ {
+		l.Load(ctx, i)
+	}
+	if n := len(l.cache.(*ttlCache[int, int]).expires); n > 10 {
+		t.Fatalf("tracked %d expirations, want at most 10", n)
+	}
+}
+
+func TestPrimeWithTTLDefaultCache(t *testing.T) {
+	clock := NewFakeClock(time.Now())
+	f := newCountingFetcher(func(keys []int) ([]int, []error) { return keys, nil })
+	l := NewTyped(f.fetcher, WithClock(clock), WithSynchronous())
+	ctx := context.Background()
+	l.PrimeWithTTL(1, 10, time.Minute)
+	if v, _ := l.Load(ctx, 1); v != 10 {
+		t.Fatalf("got %d, want the primed 10", v)
+	}
+	clock.Advance(time.Minute)
+	if v, _ := l.Load(ctx, 1); v != 1 {
+		t.Fatalf("got %d after the TTL, want the fetched 1", v)
+	}
+}
+
+func TestPrimeWithTTLWithoutTTLCache(t *testing.T) {
+	l := NewTyped(func(ctx context.Context, keys []int) ([]int, []error) {
+		return keys, nil
+	}, WithCache[int, int](NewMapCache[int, int]()))
+	defer func() {
+		if recover() == nil {
+			t.Fatal("primed cache without per-key TTLs")
+		}
+	}()
+	l.PrimeWithTTL(1, 10, time.Minute)
+}
+
+func TestStaleWhileRevalidate(t *testing.T) {
+	clock := NewFakeClock(time.Now())
+	f := &versionFetcher{}
+	l := NewTyped(f.fetcher, WithClock(clock), WithSynchronous(), WithTTL(time.Minute), WithStaleWhileRevalidate())
+	ctx := context.Background()
+	l.Load(ctx, 1)
+	clock.Advance(time.Minute)
+	// the expired value is served while being refetched
+	if v, err := l.Load(ctx, 1); err != nil || v != 1 {
+		t.Fatalf("expired: got %d, %v, want the stale value", v, err)
+	}
+	l.Dispatch()
+	if v, err := l.Load(ctx, 1); err != nil || v != 2 {
+		t.Fatalf("revalidated: got %d, %v", v, err)
+	}
+}
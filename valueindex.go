@@ -0,0 +1,111 @@
+package dataloaders
+
+import "fmt"
+
+// WithValueID maintains a reverse index from the identity of cached values, e.g. their ID,
+// to the keys they are cached at, enabling ClearByValueID. fn must return comparable identities.
+// Keys evicted by the cache (see WithCacheSize and WithTTL) stay in the index until cleared.
+// The value type of fn must match the one of the DataLoader.
+func WithValueID[V any](fn func(value V) interface{}) Option {
+	return func(o *options) {
+		o.valueID = fn
+	}
+}
+
+// newValueID returns the function set by WithValueID, nil if none.
+func newValueID[V any](o *options) func(V) interface{} {
+	if o.valueID == nil {
+		return nil
+	}
+	fn, ok := o.valueID.(func(V) interface{})
+	if !ok {
+		panic(fmt.Sprintf("dataloaders: value id func %T does not match the DataLoader's value type", o.valueID))
+	}
+	return fn
+}
+
+// ClearByValueID clears all keys caching a value with the identity valueID, see WithValueID.
+// The cleared keys are published like by Clear (see WithInvalidation), by their identity if
+// WithKeyFunc is set. Does nothing without WithValueID.
+func (l *TypedDataLoader[K, V]) ClearByValueID(valueID interface{}) *TypedDataLoader[K, V] {
+	for _, id := range l.clearByValueID(valueID) {
+		l.invalidator.publishKey(id)
+	}
+	return l
+}
+
+// clearByValueID clears the keys caching a value with the identity valueID
+// and returns their identities.
+func (l *TypedDataLoader[K, V]) clearByValueID(valueID interface{}) []K {
+	defer l.flushL2()
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	ids := make([]K, 0, len(l.valueKeys[valueID]))
+	for id := range l.valueKeys[valueID] {
+		l.cache.Delete(id)
+		delete(l.negatives, id)
+		// don't cache the result of a fetch in flight
+		delete(l.inflight, id)
+		delete(l.keyValues, id)
+		l.untag(id)
+		ids = append(ids, id)
+	}
+	delete(l.valueKeys, valueID)
+	l.stats.clears.Add(1)
+	return ids
+}
+
+// ClearByValueID clears the values with the identity valueID from the loaders of all attributes,
+// see DataLoader.ClearByValueID.
+func (l *AttrDataLoader) ClearByValueID(valueID interface{}) *AttrDataLoader {
+	l.mu.Lock()
+	loaders := make([]*DataLoader, 0, len(l.loaders))
+	for _, loader := range l.loaders {
+		loaders = append(loaders, loader)
+	}
+	l.mu.Unlock()
+	for _, loader := range loaders {
+		loader.ClearByValueID(valueID)
+	}
+	return l
+}
+
+// indexValue records that value is cached at id.
+// Must be called with l.mu held.
+func (l *TypedDataLoader[K, V]) indexValue(id K, value V) {
+	if l.valueID == nil {
+		return
+	}
+	l.unindexValue(id)
+	valueID := l.valueID(value)
+	if valueID == nil {
+		return
+	}
+	if l.valueKeys == nil {
+		l.valueKeys = map[interface{}]map[K]struct{}{}
+		l.keyValues = map[K]interface{}{}
+	}
+	keys := l.valueKeys[valueID]
+	if keys == nil {
+		keys = map[K]struct{}{}
+		l.valueKeys[valueID] = keys
+	}
+	keys[id] = struct{}{}
+	l.keyValues[id] = valueID
+}
+
+// unindexValue removes the value cached at id from the index.
+// Must be called with l.mu held.
+func (l *TypedDataLoader[K, V]) unindexValue(id K) {
+	valueID, ok := l.keyValues[id]
+	if !ok {
+		return
+	}
+	delete(l.keyValues, id)
+	if keys := l.valueKeys[valueID]; keys != nil {
+		delete(keys, id)
+		if len(keys) == 0 {
+			delete(l.valueKeys, valueID)
+		}
+	}
+}
@@ -0,0 +1,66 @@
+package dataloaders
+
+import (
+	"context"
+	"testing"
+)
+
+type user struct {
+	ID   int
+	Name string
+}
+
+func TestClearByValueID(t *testing.T) {
+	calls := 0
+	l := NewTyped(func(ctx context.Context, keys []string) ([]*user, []error) {
+		calls++
+		values := make([]*user, len(keys))
+		for i := range keys {
+			values[i] = &user{ID: 1}
+		}
+		return values, nil
+	}, WithSynchronous(), WithValueID(func(u *user) interface{} { return u.ID }))
+	ctx := context.Background()
+	l.LoadAll(ctx, []string{"alice", "admin"})
+	l.Prime("root", &user{ID: 2})
+
+	l.ClearByValueID(1)
+	// every key caching the value is cleared, others are kept
+	l.LoadAll(ctx, []string{"alice", "admin"})
+	l.Load(ctx, "root")
+	if calls != 2 {
+		t.Fatalf("fetched %d times, want the keys of the value refetched once", calls)
+	}
+	if len(l.valueKeys) != 2 {
+		t.Fatalf("indexed %d values, want 2", len(l.valueKeys))
+	}
+}
+
+func TestClearUnindexesValue(t *testing.T) {
+	l := NewTyped(echoFetcher, WithSynchronous(), WithValueID(func(v int) interface{} { return v }))
+	l.Load(context.Background(), 1)
+	l.Clear(1)
+	l.Prime(2, 2)
+	l.ClearAll()
+	if len(l.valueKeys) != 0 {
+		t.Fatalf("indexed %d values after clearing, want 0", len(l.valueKeys))
+	}
+}
+
+func TestClearByValueIDPublishesKeys(t *testing.T) {
+	bus := &localBus{}
+	f := newCountingFetcher(echo)
+	newLoader := func() *TypedDataLoader[int, int] {
+		return NewTyped(f.fetcher, WithSynchronous(), WithValueID(func(v int) interface{} { return v }),
+			WithInvalidation(bus, "users", JSONCodec[int]()))
+	}
+	a, b := newLoader(), newLoader()
+	ctx := context.Background()
+	a.Load(ctx, 1)
+	b.Load(ctx, 1)
+	a.ClearByValueID(1)
+	b.Load(ctx, 1)
+	if n := f.fetches(1); n != 3 {
+		t.Fatalf("fetched key %d times, want 3", n)
+	}
+}
@@ -0,0 +1,62 @@
+package dataloaders
+
+// WarmFrom primes the cache with the values of a previously executed query,
+// e.g. a list query, at the keys returned by keyFn.
+// All values are primed under a single lock acquisition like PrimeMany.
+// Keys that already exist are not changed. Returns the number of primed keys.
+func (l *TypedDataLoader[K, V]) WarmFrom(values []V, keyFn func(value V) K) int {
+	// keyFn is user code, not called with l.mu held
+	keys := make([]K, len(values))
+	for i, value := range values {
+		keys[i] = keyFn(value)
+	}
+
+	defer l.flushL2()
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	var n int
+	for i, value := range values {
+		if l.unsafePrime(keys[i], value, false) {
+			n++
+		}
+	}
+	return n
+}
+
+// WarmFrom primes the cache of attribute with the values of a previously executed query
+// at the keys returned by keyFn and runs the propagators of attribute for every value,
+// so the caches of the other attributes are primed as well.
+// Keys that already exist are not changed.
+// Returns the number of primed keys, 0 if attribute not registered.
+func (l *AttrDataLoader) WarmFrom(attribute Attribute, values []Value, keyFn func(value Value) Key) int {
+	if l.loader(attribute) == nil {
+		return 0
+	}
+	var n int
+	for _, value := range values {
+		if l.prime(attribute, keyFn(value), value, false) {
+			n++
+		}
+		l.RunPropagator(value, attribute)
+	}
+	return n
+}
+
+// WarmFrom primes the cache of attribute for objectType with the values of a previously executed query
+// and runs the propagators of the AttrDataLoader and the object type for every value,
+// see AttrDataLoader.WarmFrom. Returns the number of primed keys, 0 if objectType or attribute not registered.
+func (l *ObjAttrDataLoader) WarmFrom(objectType ObjectType, attribute Attribute, values []Value, keyFn func(value Value) Key) int {
+	loader := l.loader(objectType)
+	if loader == nil {
+		return 0
+	}
+	if _, ok := loader.Loader(attribute); !ok {
+		return 0
+	}
+	n := loader.WarmFrom(attribute, values, keyFn)
+	for _, value := range values {
+		l.RunPropagators(value, objectType, attribute)
+	}
+	return n
+}
@@ -0,0 +1,64 @@
+package dataloaders
+
+import (
+	"context"
+	"testing"
+)
+
+func TestWarmFrom(t *testing.T) {
+	db := newAccountDB()
+	l := NewRegistryBuilder().
+		Object("account").
+		Attr("id", db.fetcher("id")).
+		Attr("email", db.fetcher("email")).
+		PropagateFrom("id", func(v Value, l *AttrDataLoader) error {
+			l.Prime("email", v.(*account).Email, v)
+			return nil
+		}).
+		Build()
+	accounts := []Value{&account{ID: 1, Email: "a"}, &account{ID: 2, Email: "b"}}
+	if n := l.WarmFrom("account", "id", accounts, func(v Value) Key { return v.(*account).ID }); n != 2 {
+		t.Fatalf("primed %d keys, want 2", n)
+	}
+	if n := l.WarmFrom("post", "id", accounts, func(v Value) Key { return v.(*account).ID }); n != 0 {
+		t.Fatalf("primed %d keys of unregistered object type, want 0", n)
+	}
+
+	ctx := context.Background()
+	byID, _ := l.Load(ctx, "account", "id", 1)
+	// primed by the propagator
+	byEmail, _ := l.Load(ctx, "account", "email", "b")
+	if byID != accounts[0] || byEmail != accounts[1] {
+		t.Fatalf("got %v and %v, want the warmed accounts", byID, byEmail)
+	}
+	if db.fetches("id") != 0 || db.fetches("email") != 0 {
+		t.Fatalf("fetched id %d and email %d times, want 0", db.fetches("id"), db.fetches("email"))
+	}
+}
+
+func TestTypedWarmFrom(t *testing.T) {
+	f := newCountingFetcher(echo)
+	l := NewTyped(f.fetcher)
+	l.Prime(3, 3)
+	// the existing key isn't changed
+	if n := l.WarmFrom([]int{1, 2, 33}, func(v int) int { return v % 10 }); n != 2 {
+		t.Fatalf("primed %d keys, want 2", n)
+	}
+	if v, err := l.Load(context.Background(), 3); err != nil || v != 3 {
+		t.Fatalf("got %v, %v, want 3", v, err)
+	}
+	if sizes := f.batchSizes(); len(sizes) != 0 {
+		t.Fatalf("batch sizes %v, want none", sizes)
+	}
+}
+
+func TestTypedWarmFromRepeatedKeys(t *testing.T) {
+	l := NewTyped(echoFetcher, WithSynchronous())
+	// the first value of a key is primed, like priming the values one by one
+	if n := l.WarmFrom([]int{11, 21, 12}, func(v int) int { return v % 10 }); n != 2 {
+		t.Fatalf("primed %d keys, want 2", n)
+	}
+	if v, err := l.Load(context.Background(), 1); err != nil || v != 11 {
+		t.Fatalf("got %v, %v, want 11", v, err)
+	}
+}